@@ -0,0 +1,113 @@
+package sqlargs
+
+import (
+	"reflect"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+)
+
+// The analyzers running a part of the checks, so that only some of them can be
+// registered in a multichecker or golangci-lint. They share the extraction of
+// the queries, which runs once per package, and their results are the
+// *Inventory of the queries, like the one of Analyzer.
+var (
+	// ArgCount checks that the args match the placeholders of the queries.
+	ArgCount = newAnalyzer("sqlargcount", "check that the args of sql queries match their placeholders", extract,
+		catArgCount, catArgType, catArity, catPlaceholderStyle, catMock)
+	// Syntax checks that the queries are valid, and do what is meant.
+	Syntax = newAnalyzer("sqlsyntax", "check sql queries for syntax errors and for mistakes in valid queries", extract,
+		catSyntax, catSemantics, catDirective)
+	// Injection checks for values interpolated into the queries.
+	Injection = newAnalyzer("sqlinjection", "check sql queries for values interpolated into them", extract,
+		catInjection)
+	// Schema checks the queries against the schema and the database.
+	Schema = newAnalyzer("sqlschema", "check sql queries against the schema of -schema and the database of -dsn", extract,
+		catSchema, catDatabase)
+	// ResourceUse checks that the rows returned by the queries are used, that
+	// the rows and prepared statements are closed, and that the transactions
+	// end.
+	ResourceUse = newAnalyzer("sqlresourceuse", "check that sql queries are run with a method matching the rows they return, that the rows and prepared statements are closed, and that the transactions end", extract,
+		catMethod, catRows, catStmt, catTx)
+	// Policy runs the opt-in checks, like -select-star.
+	Policy = newAnalyzer("sqlpolicy", "check sql queries against the policies enabled with flags", extract,
+		optInCategories...)
+)
+
+// analyzers are all the analyzers of the package.
+var analyzers = []*analysis.Analyzer{Analyzer, ArgCount, Syntax, Injection, Schema, ResourceUse, Policy}
+
+// extract extracts the queries of a package and runs all the checks on them,
+// with the configuration of the flags, for the other analyzers to report the
+// ones of their categories.
+var extract = newExtract(flagConfig)
+
+// newExtract returns an analyzer extracting the queries of a package and
+// running the checks configured by cfg on them.
+func newExtract(cfg *config) *analysis.Analyzer {
+	return &analysis.Analyzer{
+		Name:             "sqlargsextract",
+		Doc:              "extract the sql queries of a package and check them, for the sqlargs analyzers",
+		Run:              cfg.run,
+		Requires:         []*analysis.Analyzer{inspect.Analyzer},
+		RunDespiteErrors: true,
+		ResultType:       reflect.TypeOf((*findings)(nil)),
+		FactTypes:        []analysis.Fact{(*Queries)(nil), (*verifiedQuery)(nil), (*driverDialect)(nil)},
+	}
+}
+
+// findings is the result of extract.
+type findings struct {
+	diagnostics []analysis.Diagnostic
+	inventory   *Inventory
+}
+
+// collect returns a copy of pass recording its diagnostics in f.
+func (f *findings) collect(pass *analysis.Pass) *analysis.Pass {
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		f.diagnostics = append(f.diagnostics, d)
+	}
+	return &p
+}
+
+// sort sorts the diagnostics by position, then category and message, so that
+// they are reported in the same order whichever order the checks ran in.
+func (f *findings) sort() {
+	sort.SliceStable(f.diagnostics, func(i, j int) bool {
+		a, b := f.diagnostics[i], f.diagnostics[j]
+		if a.Pos != b.Pos {
+			return a.Pos < b.Pos
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Message < b.Message
+	})
+}
+
+// newAnalyzer returns an analyzer reporting the findings of extract which are
+// of one of categories.
+func newAnalyzer(name, doc string, extract *analysis.Analyzer, categories ...string) *analysis.Analyzer {
+	reported := make(map[string]bool)
+	for _, c := range categories {
+		reported[c] = true
+	}
+	return &analysis.Analyzer{
+		Name: name,
+		Doc:  doc,
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			f := pass.ResultOf[extract].(*findings)
+			for _, d := range f.diagnostics {
+				if reported[d.Category] {
+					pass.Report(d)
+				}
+			}
+			return f.inventory, nil
+		},
+		Requires:         []*analysis.Analyzer{extract},
+		RunDespiteErrors: true,
+		ResultType:       reflect.TypeOf((*Inventory)(nil)),
+	}
+}
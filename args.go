@@ -0,0 +1,302 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// argCount is the no. of args passed to a query. When the exact count cannot
+// be determined, it holds the range of possible counts; a negative max means
+// that there is no upper bound.
+type argCount struct {
+	min, max int
+}
+
+// unknownArgs is used when nothing is known about the args.
+var unknownArgs = argCount{min: 0, max: -1}
+
+func (c argCount) exact() bool {
+	return c.min == c.max
+}
+
+// lessThan reports whether every possible count is less than n.
+func (c argCount) lessThan(n int) bool {
+	return c.max >= 0 && c.max < n
+}
+
+func (c argCount) add(o argCount) argCount {
+	sum := argCount{min: c.min + o.min, max: c.max + o.max}
+	if c.max < 0 || o.max < 0 {
+		sum.max = -1
+	}
+	return sum
+}
+
+func (c argCount) String() string {
+	switch {
+	case c.exact():
+		return strconv.Itoa(c.min)
+	case c == unknownArgs:
+		return "unknown"
+	case c.max < 0:
+		return fmt.Sprintf("at least %d", c.min)
+	default:
+		return fmt.Sprintf("%d to %d", c.min, c.max)
+	}
+}
+
+// numArgs returns the number of query arguments passed to call, excluding the
+// query itself. If a slice is spread, its length is worked out from the
+// enclosing function body.
+func numArgs(call *ast.CallExpr, body *ast.BlockStmt, info *types.Info) argCount {
+	args := call.Args[1:]
+	if call.Ellipsis == token.NoPos {
+		return argCount{len(args), len(args)}
+	}
+	// With a spread, the only argument after the query is the slice itself.
+	if len(args) != 1 {
+		return unknownArgs
+	}
+	return sliceLen(args[0], body, call.Pos(), info)
+}
+
+// sliceLen returns the length of the slice expression expr at position pos.
+// It understands composite literals, and local variables inside body which are
+// initialized with a composite literal and optionally grown with append.
+func sliceLen(expr ast.Expr, body *ast.BlockStmt, pos token.Pos, info *types.Info) argCount {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return sliceLen(e.X, body, pos, info)
+	case *ast.CompositeLit:
+		for _, elt := range e.Elts {
+			// Keyed elements can make the length differ from the no. of elements.
+			if _, ok := elt.(*ast.KeyValueExpr); ok {
+				return unknownArgs
+			}
+		}
+		return argCount{len(e.Elts), len(e.Elts)}
+	case *ast.Ident:
+		v, ok := info.Uses[e].(*types.Var)
+		if !ok || body == nil || v.Pos() < body.Pos() || v.Pos() >= body.End() {
+			return unknownArgs
+		}
+		return localSliceLen(v, body, pos, info)
+	}
+	return unknownArgs
+}
+
+// localSliceLen returns the length of the local slice variable v at position
+// pos by looking at all its assignments inside body. Apart from the initial
+// composite literal, only appends to v itself are allowed. Appends nested in
+// conditionals widen the range, and appends inside loops remove the upper
+// bound. It gives up if v is assigned anything else, or if its address is taken.
+func localSliceLen(v *types.Var, body *ast.BlockStmt, pos token.Pos, info *types.Info) argCount {
+	var (
+		def     ast.Node
+		init    ast.Expr
+		appends []*ast.AssignStmt
+		stacks  [][]ast.Node
+		invalid bool
+		stack   []ast.Node
+	)
+	refersTo := func(e ast.Expr) bool {
+		id, ok := e.(*ast.Ident)
+		return ok && (info.Defs[id] == v || info.Uses[id] == v)
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		defer func() { stack = append(stack, n) }()
+
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				if !refersTo(lhs) {
+					continue
+				}
+				if len(n.Lhs) != len(n.Rhs) {
+					invalid = true
+					return true
+				}
+				if def == nil && n.Tok == token.DEFINE {
+					def, init = n, n.Rhs[i]
+					continue
+				}
+				if n.Pos() > pos {
+					continue
+				}
+				if !isSelfAppend(n.Rhs[i], refersTo) || len(n.Lhs) != 1 {
+					invalid = true
+					return true
+				}
+				appends = append(appends, n)
+				stacks = append(stacks, append([]ast.Node(nil), stack...))
+			}
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if !refersTo(name) {
+					continue
+				}
+				switch len(n.Values) {
+				case 0:
+					// A declaration without a value is an empty slice.
+					def, init = n, &ast.CompositeLit{}
+				case len(n.Names):
+					def, init = n, n.Values[i]
+				default:
+					invalid = true
+					return true
+				}
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND && refersTo(n.X) {
+				invalid = true
+			}
+		}
+		return true
+	})
+	if invalid || init == nil {
+		return unknownArgs
+	}
+	lit, ok := init.(*ast.CompositeLit)
+	if !ok {
+		return unknownArgs
+	}
+	count := sliceLen(lit, nil, pos, info)
+	for i, a := range appends {
+		grow := appendLen(a.Rhs[0].(*ast.CallExpr), body, pos, info)
+		switch branching(stacks[i], def) {
+		case token.FOR:
+			grow = argCount{min: 0, max: -1}
+		case token.IF:
+			grow.min = 0
+		}
+		count = count.add(grow)
+	}
+	return count
+}
+
+// isSelfAppend reports whether e is of the form append(v, ...).
+func isSelfAppend(e ast.Expr, isVar func(ast.Expr) bool) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return false
+	}
+	fn, ok := call.Fun.(*ast.Ident)
+	return ok && fn.Name == "append" && isVar(call.Args[0])
+}
+
+// appendLen returns the no. of elements added by the append call.
+func appendLen(call *ast.CallExpr, body *ast.BlockStmt, pos token.Pos, info *types.Info) argCount {
+	elems := call.Args[1:]
+	if call.Ellipsis == token.NoPos {
+		return argCount{len(elems), len(elems)}
+	}
+	if len(elems) != 1 {
+		return unknownArgs
+	}
+	return sliceLen(elems[0], body, pos, info)
+}
+
+// branching tells whether a statement with the ancestors in stack runs
+// unconditionally relative to def. It returns token.FOR if the statement can
+// run any number of times, token.IF if it may not run at all, and
+// token.ILLEGAL if it always runs exactly once.
+func branching(stack []ast.Node, def ast.Node) token.Token {
+	tok := token.ILLEGAL
+	for _, n := range stack {
+		// Ancestors enclosing the definition too do not make a difference.
+		if n.Pos() <= def.Pos() && def.End() <= n.End() {
+			continue
+		}
+		switch n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.FuncLit:
+			return token.FOR
+		case *ast.IfStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			tok = token.IF
+		}
+	}
+	return tok
+}
+
+// unspreadSlices returns the args of call which are slices of interface{}
+// passed without a spread. These are almost always meant to be spread, as
+// drivers reject them as a single arg.
+func unspreadSlices(call *ast.CallExpr, info *types.Info) []ast.Expr {
+	if call.Ellipsis != token.NoPos {
+		return nil
+	}
+	var slices []ast.Expr
+	for _, arg := range call.Args[1:] {
+		typ := info.TypeOf(arg)
+		if typ == nil {
+			continue
+		}
+		slice, ok := typ.Underlying().(*types.Slice)
+		if !ok {
+			continue
+		}
+		if iface, ok := slice.Elem().Underlying().(*types.Interface); ok && iface.Empty() {
+			slices = append(slices, arg)
+		}
+	}
+	return slices
+}
+
+// namedArgs returns the lower cased names of the args of call created with
+// sql.Named. The second return value is false if the args are spread from a
+// slice, in which case they are not known.
+func namedArgs(call *ast.CallExpr, info *types.Info) (map[string]bool, bool) {
+	if call.Ellipsis != token.NoPos {
+		return nil, false
+	}
+	names := make(map[string]bool)
+	for _, arg := range call.Args[1:] {
+		if name, ok := namedArg(arg, info); ok {
+			names[strings.ToLower(name)] = true
+		}
+	}
+	return names, true
+}
+
+// namedArg returns the name of arg, if it is a call to sql.Named, or to the
+// Named of clickhouse-go, with a constant name.
+func namedArg(arg ast.Expr, info *types.Info) (string, bool) {
+	call, ok := arg.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Name() != "Named" || fn.Pkg() == nil || fn.Pkg().Path() != "database/sql" && !strings.HasPrefix(fn.Pkg().Path(), "github.com/ClickHouse/clickhouse-go") {
+		return "", false
+	}
+	typ, ok := info.Types[call.Args[0]]
+	if !ok || typ.Value == nil || typ.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(typ.Value), true
+}
+
+// enclosingBody returns the body of the innermost function in stack.
+func enclosingBody(stack []ast.Node) *ast.BlockStmt {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch f := stack[i].(type) {
+		case *ast.FuncDecl:
+			return f.Body
+		case *ast.FuncLit:
+			return f.Body
+		}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// goKind is the kind of value a Go arg holds, as far as binding it to a
+// placeholder goes.
+type goKind int
+
+const (
+	kindUnknown goKind = iota
+	kindInt
+	kindFloat
+	kindString
+	kindBool
+	kindTime
+)
+
+// goKindOf returns the kind of the values of typ. Types implementing
+// driver.Valuer can be converted to anything, so their kind is unknown.
+func goKindOf(typ types.Type) goKind {
+	if isTime(typ) {
+		return kindTime
+	}
+	if isValuer(typ) {
+		return kindUnknown
+	}
+	basic, ok := typ.Underlying().(*types.Basic)
+	switch {
+	case !ok:
+		return kindUnknown
+	case basic.Info()&types.IsInteger != 0:
+		return kindInt
+	case basic.Info()&types.IsFloat != 0:
+		return kindFloat
+	case basic.Info()&types.IsString != 0:
+		return kindString
+	case basic.Info()&types.IsBoolean != 0:
+		return kindBool
+	}
+	return kindUnknown
+}
+
+// isValuer reports whether typ has a Value method, like driver.Valuer.
+func isValuer(typ types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(typ, false, nil, "Value")
+	_, ok := obj.(*types.Func)
+	return ok
+}
+
+// isTime reports whether typ is time.Time.
+func isTime(typ types.Type) bool {
+	n, ok := typ.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "time" && n.Obj().Name() == "Time"
+}
+
+// checkStructArgs reports the args of call which are structs, or pointers to
+// structs, which do not implement driver.Valuer. database/sql cannot convert
+// them, so the call fails at runtime. time.Time and the types of database/sql,
+// like sql.NamedArg, are handled by database/sql itself, and the
+// driver.NamedValue of clickhouse.Named and the types of go-mssqldb, like
+// mssql.TVP, by the driver.
+func checkStructArgs(call *ast.CallExpr, pass *analysis.Pass) {
+	for _, arg := range call.Args[1:] {
+		typ := pass.TypesInfo.TypeOf(arg)
+		if typ == nil || isValuer(typ) {
+			continue
+		}
+		elem := typ
+		if ptr, ok := typ.(*types.Pointer); ok {
+			elem = ptr.Elem()
+		}
+		if _, ok := elem.Underlying().(*types.Struct); !ok || isTime(elem) {
+			continue
+		}
+		if n, ok := elem.(*types.Named); ok && n.Obj().Pkg() != nil && (n.Obj().Pkg().Path() == "database/sql" || n.Obj().Pkg().Path() == "database/sql/driver") || isMSSQLType(elem) {
+			continue
+		}
+		reportf(pass, catArgType, arg.Pos(), "Arg of type %s does not implement driver.Valuer: it cannot be bound", types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+	}
+}
+
+// checkErrorArgs reports the args of call whose static type is error. These
+// are usually err passed by mistake instead of another variable.
+func checkErrorArgs(call *ast.CallExpr, pass *analysis.Pass) {
+	errorType := types.Universe.Lookup("error").Type()
+	for _, arg := range call.Args[1:] {
+		if typ := pass.TypesInfo.TypeOf(arg); typ != nil && types.Identical(typ, errorType) {
+			reportf(pass, catArgType, arg.Pos(), "Arg of type error passed to the query: it is most likely the wrong variable")
+		}
+	}
+}
+
+// checkCollectionArgs reports the args of call which are slices or maps, which
+// database/sql drivers cannot bind as a single value. Byte slices are bound as
+// binary data, and slices of interface{} are reported as unspread slices. pgx
+// and clickhouse-go bind slices natively, and godror binds them for array DML
+// and PL/SQL arrays, so packages using them are not checked.
+func checkCollectionArgs(call *ast.CallExpr, d *dialect, pass *analysis.Pass) {
+	for _, imp := range pass.Pkg.Imports() {
+		if strings.HasPrefix(imp.Path(), "github.com/jackc/pgx") || strings.HasPrefix(imp.Path(), "github.com/ClickHouse/clickhouse-go") || imp.Path() == godrorPath {
+			return
+		}
+	}
+	for _, arg := range call.Args[1:] {
+		typ := pass.TypesInfo.TypeOf(arg)
+		if typ == nil || isValuer(typ) {
+			continue
+		}
+		var fix string
+		switch u := typ.Underlying().(type) {
+		case *types.Slice:
+			if basic, ok := u.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+				continue
+			}
+			if iface, ok := u.Elem().Underlying().(*types.Interface); ok && iface.Empty() {
+				continue
+			}
+			fix = "expand it into one placeholder per element"
+			if d.dollarParams && !d.questionParams {
+				fix = "wrap it with pq.Array"
+			}
+		case *types.Map:
+			fix = "encode it, e.g. with json.Marshal"
+		default:
+			continue
+		}
+		reportf(pass, catArgType, arg.Pos(), "Arg of type %s cannot be bound as a single value: %s", types.TypeString(typ, types.RelativeTo(pass.Pkg)), fix)
+	}
+}
+
+var (
+	integerCast   = map[goKind]bool{kindFloat: true, kindBool: true, kindTime: true}
+	numericCast   = map[goKind]bool{kindBool: true, kindTime: true}
+	uuidCast      = map[goKind]bool{kindInt: true, kindFloat: true, kindBool: true, kindTime: true}
+	timestampCast = map[goKind]bool{kindInt: true, kindFloat: true, kindBool: true}
+	boolCast      = map[goKind]bool{kindFloat: true, kindTime: true}
+)
+
+// castMismatches maps SQL types to the kinds of Go args which cannot be cast
+// to them. Types made of several words, like double precision, are keyed by
+// their first word.
+var castMismatches = map[string]map[goKind]bool{
+	"int": integerCast, "int2": integerCast, "int4": integerCast, "int8": integerCast,
+	"integer": integerCast, "smallint": integerCast, "bigint": integerCast,
+	"numeric": numericCast, "decimal": numericCast, "real": numericCast, "float4": numericCast,
+	"float8": numericCast, "double": numericCast,
+	"uuid":      uuidCast,
+	"timestamp": timestampCast, "timestamptz": timestampCast, "date": timestampCast,
+	"time": timestampCast, "timetz": timestampCast, "interval": timestampCast,
+	"bool": boolCast, "boolean": boolCast,
+}
+
+// checkCastArgs reports the args bound to placeholders which are cast to a
+// SQL type, like $1::uuid or CAST($1 AS int), when the Go type of the arg
+// cannot be converted to it.
+func checkCastArgs(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	args := call.Args[1:]
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	n := 0
+	for i, l := range lexemes {
+		if l.kind != lexPlaceholder {
+			continue
+		}
+		if l.text == "?" {
+			n++
+		} else {
+			n, _ = strconv.Atoi(l.text[1:])
+		}
+		if n < 1 || n > len(args) {
+			continue
+		}
+		sqlType := castType(lexemes, i)
+		mismatches, ok := castMismatches[sqlType]
+		if !ok {
+			continue
+		}
+		typ := pass.TypesInfo.TypeOf(args[n-1])
+		if typ == nil || !mismatches[goKindOf(typ)] {
+			continue
+		}
+		reportf(pass, catArgType, args[n-1].Pos(), "Arg %d has type %s but is cast to %s", n, types.TypeString(typ, types.RelativeTo(pass.Pkg)), sqlType)
+	}
+}
+
+// castType returns the lower cased SQL type the placeholder lexemes[i] is
+// cast to, or "" if it is not cast.
+func castType(lexemes []lexeme, i int) string {
+	if i+2 < len(lexemes) && lexemes[i+1].text == "::" && lexemes[i+2].kind == lexWord {
+		return strings.ToLower(lexemes[i+2].text)
+	}
+	if i >= 2 && i+2 < len(lexemes) && lexemes[i-1].text == "(" && strings.EqualFold(lexemes[i-2].text, "CAST") &&
+		strings.EqualFold(lexemes[i+1].text, "AS") && lexemes[i+2].kind == lexWord {
+		return strings.ToLower(lexemes[i+2].text)
+	}
+	return ""
+}
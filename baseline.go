@@ -0,0 +1,145 @@
+package sqlargs
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// baselineFile is the file selected with the -baseline flag, listing the
+// findings which are not reported.
+var baselineFile string
+
+// writeBaseline makes the analyzer append its findings to baselineFile,
+// instead of reporting them.
+var writeBaseline bool
+
+var (
+	baselinesMu sync.Mutex
+	// baselines caches the findings read from the baseline files by path, as
+	// the analyzer runs on many packages.
+	baselines = make(map[string]map[string]int)
+)
+
+// baseline suppresses, or records, the findings of a package. A finding is
+// written as path: category: message, where path is the file relative to the
+// directory of the baseline file. Positions are left out, so that the
+// findings survive edits of the code around them.
+type baseline struct {
+	path string
+	// remaining counts the findings of the baseline which are yet to be
+	// suppressed in the package.
+	remaining map[string]int
+	// findings are the findings to write to the baseline.
+	findings []string
+}
+
+// newBaseline returns the baseline of -baseline, or nil if there is none.
+func newBaseline() (*baseline, error) {
+	if baselineFile == "" {
+		if writeBaseline {
+			return nil, fmt.Errorf("-write-baseline requires -baseline")
+		}
+		return nil, nil
+	}
+	path, err := filepath.Abs(baselineFile)
+	if err != nil {
+		return nil, fmt.Errorf("-baseline: %v", err)
+	}
+	b := &baseline{path: path, remaining: make(map[string]int)}
+	if writeBaseline {
+		return b, nil
+	}
+	counts, err := readBaseline(path)
+	if err != nil {
+		return nil, err
+	}
+	for finding, n := range counts {
+		b.remaining[finding] = n
+	}
+	return b, nil
+}
+
+// readBaseline returns the no. of times each finding is listed in the
+// baseline file at path. Empty lines and lines starting with # are skipped.
+func readBaseline(path string) (map[string]int, error) {
+	baselinesMu.Lock()
+	defer baselinesMu.Unlock()
+	if counts, ok := baselines[path]; ok {
+		return counts, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("-baseline: %v", err)
+	}
+	defer f.Close()
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		counts[line]++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("-baseline: %v", err)
+	}
+	baselines[path] = counts
+	return counts, nil
+}
+
+// wrap returns a copy of pass whose diagnostics are checked against the
+// baseline before being reported.
+func (b *baseline) wrap(pass *analysis.Pass) *analysis.Pass {
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		finding := b.finding(pass, d)
+		switch {
+		case writeBaseline:
+			b.findings = append(b.findings, finding)
+		case b.remaining[finding] > 0:
+			b.remaining[finding]--
+		default:
+			pass.Report(d)
+		}
+	}
+	return &p
+}
+
+// finding returns d as it is written in the baseline.
+func (b *baseline) finding(pass *analysis.Pass, d analysis.Diagnostic) string {
+	file := pass.Fset.Position(d.Pos).Filename
+	if rel, err := filepath.Rel(filepath.Dir(b.path), file); err == nil {
+		file = rel
+	}
+	message := strings.ReplaceAll(d.Message, "\n", " ")
+	return fmt.Sprintf("%s: %s: %s", filepath.ToSlash(file), d.Category, message)
+}
+
+// write appends the recorded findings to the baseline file. They are written
+// at once, as the packages may be analyzed concurrently.
+func (b *baseline) write() error {
+	if !writeBaseline || len(b.findings) == 0 {
+		return nil
+	}
+	// The findings are sorted, so that the file does not depend on the order
+	// the checks ran in.
+	sort.Strings(b.findings)
+	f, err := os.OpenFile(b.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("-baseline: %v", err)
+	}
+	if _, err := f.WriteString(strings.Join(b.findings, "\n") + "\n"); err != nil {
+		f.Close()
+		return fmt.Errorf("-baseline: %v", err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,153 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const bigqueryPath = "cloud.google.com/go/bigquery"
+
+// bigquery is the dialect of BigQuery standard SQL. It is not selectable, since
+// BigQuery queries are not run through database/sql.
+var bigquery = &dialect{
+	name:           "bigquery",
+	questionParams: true,
+	atParams:       true,
+}
+
+// isBigQueryCall reports whether call is of the form client.Query(q), where
+// client is a *bigquery.Client.
+func isBigQueryCall(call *ast.CallExpr, info *types.Info) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Query" || len(call.Args) != 1 {
+		return false
+	}
+	return isNamedPtr(info.TypeOf(sel.X), bigqueryPath, "Client")
+}
+
+// checkBigQuery checks the placeholders of a BigQuery query against the
+// Parameters set on the *bigquery.Query returned by call. It expects the query
+// to be assigned to a variable, and the parameters to be set exactly once with
+// a composite literal. @name placeholders need a parameter with that name, and
+// ? placeholders need a parameter without a name each.
+func checkBigQuery(call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	typ, ok := pass.TypesInfo.Types[call.Args[0]]
+	if !ok || typ.Value == nil || typ.Value.Kind() != constant.String {
+		return
+	}
+	body := enclosingBody(stack)
+	if body == nil || len(stack) < 2 {
+		return
+	}
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 || assign.Rhs[0] != call {
+		return
+	}
+	id, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	q := pass.TypesInfo.ObjectOf(id)
+
+	var values ast.Expr
+	sets := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		a, ok := n.(*ast.AssignStmt)
+		if !ok || len(a.Lhs) != len(a.Rhs) {
+			return true
+		}
+		for i, lhs := range a.Lhs {
+			sel, ok := lhs.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Parameters" {
+				continue
+			}
+			if x, ok := sel.X.(*ast.Ident); ok && pass.TypesInfo.Uses[x] == q {
+				values = a.Rhs[i]
+				sets++
+			}
+		}
+		return true
+	})
+	if sets > 1 {
+		return
+	}
+	names := make(map[string]bool)
+	positional := 0
+	if sets == 1 {
+		lit, ok := values.(*ast.CompositeLit)
+		if !ok {
+			return
+		}
+		for _, elt := range lit.Elts {
+			name, ok := paramName(elt, pass.TypesInfo)
+			if !ok {
+				return
+			}
+			if name == "" {
+				positional++
+			} else {
+				names[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	params, _ := placeholders(constant.StringVal(typ.Value), bigquery)
+	question := 0
+	reported := make(map[string]bool)
+	for _, p := range params {
+		if p.text == "?" {
+			question++
+			continue
+		}
+		name := strings.ToLower(p.name)
+		if !names[name] && !reported[name] {
+			reported[name] = true
+			reportf(pass, catArgCount, call.Lparen, "No value for parameter %s", p.text)
+		}
+	}
+	if question > 0 {
+		checkPositionalArgs(question, nil, call, argCount{positional, positional}, pass)
+	}
+}
+
+// paramName returns the Name of a bigquery.QueryParameter composite literal.
+// The name is empty for positional parameters.
+func paramName(elt ast.Expr, info *types.Info) (string, bool) {
+	if u, ok := elt.(*ast.UnaryExpr); ok {
+		elt = u.X
+	}
+	lit, ok := elt.(*ast.CompositeLit)
+	if !ok {
+		return "", false
+	}
+	for _, e := range lit.Elts {
+		kv, ok := e.(*ast.KeyValueExpr)
+		if !ok {
+			// Positional fields are not worth supporting.
+			return "", false
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Name" {
+			continue
+		}
+		typ, ok := info.Types[kv.Value]
+		if !ok || typ.Value == nil || typ.Value.Kind() != constant.String {
+			return "", false
+		}
+		return constant.StringVal(typ.Value), true
+	}
+	return "", true
+}
+
+// isNamedPtr reports whether typ is a pointer to the named type path.name.
+func isNamedPtr(typ types.Type, path, name string) bool {
+	ptr, ok := typ.(*types.Pointer)
+	if !ok {
+		return false
+	}
+	n, ok := ptr.Elem().(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == path && n.Obj().Name() == name
+}
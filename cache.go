@@ -0,0 +1,203 @@
+package sqlargs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// cacheDir is the directory selected with the -cache flag, where the parsed
+// schemas and the results of the query parser are cached between runs. go vet
+// runs the analyzer in a new process for each package, so without it, large
+// repositories would parse the same schema for every package. The cache is
+// disabled if it is empty.
+var cacheDir = defaultCacheDir()
+
+// defaultCacheDir returns the sqlargs directory of the user cache directory,
+// or "" if there is none.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "sqlargs")
+}
+
+// cacheVersion is part of every cache key, and is incremented when the format
+// or the meaning of the cache entries changes.
+const cacheVersion = "1"
+
+// cacheKey returns the key of the cache entry for the content parts.
+func cacheKey(parts ...string) string {
+	h := sha256.New()
+	h.Write([]byte(cacheVersion))
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readCache returns the cache entry key of kind, if there is one.
+func readCache(kind, key string) ([]byte, bool) {
+	if cacheDir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(cacheDir, kind, key))
+	return data, err == nil
+}
+
+// writeCache sets the cache entry key of kind to data. The cache is only an
+// optimization, so errors are ignored.
+func writeCache(kind, key string, data []byte) {
+	if cacheDir == "" {
+		return
+	}
+	dir := filepath.Join(cacheDir, kind)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return
+	}
+	// Write to a temporary file first, as packages are analyzed in parallel.
+	f, err := os.CreateTemp(dir, key+".tmp")
+	if err != nil {
+		return
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(dir, key))
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+}
+
+// parseQuery returns the error of the backend p, selected as name, for query
+// in dialect d, using the cached result if there is one.
+func parseQuery(name string, p Parser, query string, d *dialect) error {
+	key := cacheKey(name, d.name, query)
+	if data, ok := readCache("queries", key); ok {
+		if msg, ok := strings.CutPrefix(string(data), "error: "); ok {
+			return errors.New(msg)
+		}
+		return nil
+	}
+	err := safeParse(name, p, query, d)
+	// A panic may not happen with another version of the parser.
+	if _, ok := err.(*parserPanic); ok {
+		return err
+	}
+	result := "ok"
+	if err != nil {
+		result = "error: " + err.Error()
+	}
+	writeCache("queries", key, []byte(result))
+	return err
+}
+
+// parserPanic is the error of a parser which panicked on a query.
+type parserPanic struct {
+	parser string
+	value  interface{}
+}
+
+func (e *parserPanic) Error() string {
+	return fmt.Sprintf("the %s parser panicked: %v", e.parser, e.value)
+}
+
+// safeParse parses query with p, the parser name, recovering from its panics,
+// so that exotic queries do not stop the analysis of the whole package.
+func safeParse(name string, p Parser, query string, d *dialect) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &parserPanic{name, r}
+		}
+	}()
+	return p.Parse(query, d)
+}
+
+// parsedKey is the key of the result of parsing a query in a parseMemo.
+type parsedKey struct {
+	d     *dialect
+	query string
+}
+
+// parseMemo records the results of parseQuery in a pass, as large packages run
+// the same constant queries many times, and reading the cache of -cache hashes
+// the query every time.
+type parseMemo map[parsedKey]error
+
+// parse returns the result of parseQuery for query in dialect d, parsing it
+// only the first time.
+func (m parseMemo) parse(name string, p Parser, query string, d *dialect) error {
+	key := parsedKey{d, query}
+	if err, ok := m[key]; ok {
+		return err
+	}
+	err := parseQuery(name, p, query, d)
+	m[key] = err
+	return err
+}
+
+// cachedTable is the encoding of a table in the cache.
+type cachedTable struct {
+	// Key is the normalized name of the table.
+	Key     string
+	Name    string
+	Columns []cachedColumn
+}
+
+// cachedColumn is the encoding of a column in the cache.
+type cachedColumn struct {
+	// Key is the normalized name of the column.
+	Key        string
+	Name       string
+	Type       string
+	NotNull    bool
+	HasDefault bool
+}
+
+// readCachedSchema returns the cached schema with key, if there is one.
+func readCachedSchema(key string) (*schema, bool) {
+	data, ok := readCache("schemas", key)
+	if !ok {
+		return nil, false
+	}
+	var tables []cachedTable
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, false
+	}
+	s := newSchema()
+	for _, ct := range tables {
+		t := &table{name: ct.Name, columns: make(map[string]*column)}
+		for _, cc := range ct.Columns {
+			t.columns[cc.Key] = &column{name: cc.Name, typ: cc.Type, notNull: cc.NotNull, hasDefault: cc.HasDefault}
+		}
+		s.tables[ct.Key] = t
+	}
+	return s, true
+}
+
+// writeCachedSchema caches s with key.
+func writeCachedSchema(key string, s *schema) {
+	var tables []cachedTable
+	for key, t := range s.tables {
+		ct := cachedTable{Key: key, Name: t.name}
+		for key, c := range t.columns {
+			ct.Columns = append(ct.Columns, cachedColumn{Key: key, Name: c.name, Type: c.typ, NotNull: c.notNull, HasDefault: c.hasDefault})
+		}
+		tables = append(tables, ct)
+	}
+	data, err := json.Marshal(tables)
+	if err != nil {
+		return
+	}
+	writeCache("schemas", key, data)
+}
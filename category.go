@@ -0,0 +1,202 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// The categories of the diagnostics, so that tools can filter them. They are
+// part of the interface of the analyzer, and must not change.
+const (
+	// catArgCount is for args which do not match the placeholders.
+	catArgCount = "argcount"
+	// catArgType is for args whose Go type cannot be bound as intended.
+	catArgType = "argtype"
+	// catArity is for lists of different lengths, like the columns and the
+	// values of an INSERT.
+	catArity = "arity"
+	// catDatabase is for queries rejected by the database of -dsn.
+	catDatabase = "database"
+	// catDirective is for invalid //sqlargs: comments.
+	catDirective = "directive"
+	// catMethod is for queries run with the wrong method, like a SELECT run
+	// with Exec.
+	catMethod = "method"
+	// catPlaceholderStyle is for placeholders which are invalid, or written in
+	// the wrong style.
+	catPlaceholderStyle = "placeholder-style"
+	// catSchema is for queries which do not match the schema.
+	catSchema = "schema"
+	// catSemantics is for queries which are valid but do not do what is
+	// meant, like comparisons with NULL.
+	catSemantics = "semantics"
+	// catSyntax is for invalid queries.
+	catSyntax = "syntax"
+	// catInjection is for values interpolated into queries.
+	catInjection = "sqlinjection"
+	// catCustom is for the findings of the QueryVisitors of Options.
+	catCustom = "custom"
+	// catRows is for rows which are not closed, or whose iteration errors
+	// are not checked.
+	catRows = "rows"
+	// catStmt is for prepared statements which are not closed.
+	catStmt = "stmt"
+	// catTx is for transactions which are neither committed nor rolled back.
+	catTx = "tx"
+	// catMock is for go-sqlmock expectations which do not match the queries
+	// of the code.
+	catMock = "mock"
+)
+
+// The categories of the opt-in checks, which are named after their flags, so
+// that each of them can be selected with -only and -disable.
+const (
+	catStrict           = "strict"
+	catRequireConst     = "requireconstqueries"
+	catRequireWhere     = "requirewhere"
+	catGroupBy          = "groupby"
+	catSelectStar       = "selectstar"
+	catInsertColumns    = "insertcolumns"
+	catLoopQueries      = "loopqueries"
+	catErrNoRows        = "errnorows"
+	catUncheckedExec    = "uncheckedexec"
+	catContextMethods   = "contextmethods"
+	catSwappedArgs      = "swappedargs"
+	catDuplicateArgs    = "duplicateargs"
+	catUnusedQueries    = "unusedqueries"
+	catDuplicateQueries = "duplicatequeries"
+)
+
+// optInCategories are the categories of the opt-in checks.
+var optInCategories = []string{
+	catStrict, catRequireConst, catRequireWhere, catGroupBy, catSelectStar, catInsertColumns, catLoopQueries, catErrNoRows,
+	catUncheckedExec, catContextMethods, catSwappedArgs, catDuplicateArgs, catUnusedQueries, catDuplicateQueries,
+}
+
+// categories are all the categories of the diagnostics.
+var categories = append([]string{
+	catArgCount, catArgType, catArity, catDatabase, catDirective, catMethod, catPlaceholderStyle,
+	catSchema, catSemantics, catSyntax, catInjection, catCustom, catMock, catRows, catStmt, catTx,
+}, optInCategories...)
+
+// The severities a category can be given with -severity. Errors are reported
+// as is, warnings and infos with their severity in front of the message, so
+// that they can be told apart, and the diagnostics of an "off" category are
+// not reported.
+const (
+	sevError   = "error"
+	sevWarning = "warning"
+	sevInfo    = "info"
+	sevOff     = "off"
+)
+
+// severities maps categories to the severity set with -severity. Categories
+// which are not in it are errors.
+var severities = map[string]string{}
+
+// severityFlag is a flag.Value setting the severities of comma separated
+// category=severity pairs.
+type severityFlag struct{}
+
+func (severityFlag) String() string {
+	var pairs []string
+	for category, severity := range severities {
+		pairs = append(pairs, category+"="+severity)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (severityFlag) Set(list string) error {
+	for _, pair := range strings.Split(list, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		eq := strings.IndexByte(pair, '=')
+		if eq < 0 {
+			return fmt.Errorf("%s is not category=severity", pair)
+		}
+		category, severity := strings.TrimSpace(pair[:eq]), strings.TrimSpace(pair[eq+1:])
+		if !isCategory(category) {
+			return fmt.Errorf("unknown category %s: must be one of %s", category, strings.Join(categories, ", "))
+		}
+		switch severity {
+		case sevError, sevWarning, sevInfo, sevOff:
+		default:
+			return fmt.Errorf("unknown severity %s: must be one of error, warning, info, off", severity)
+		}
+		severities[category] = severity
+	}
+	return nil
+}
+
+func isCategory(name string) bool {
+	for _, c := range categories {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// categoriesFlag is a flag.Value adding comma separated categories to a set,
+// as for -only and -disable.
+type categoriesFlag struct {
+	set *map[string]bool
+}
+
+func (f categoriesFlag) String() string {
+	if f.set == nil {
+		return ""
+	}
+	var names []string
+	for name := range *f.set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
+
+func (f categoriesFlag) Set(list string) error {
+	for _, category := range strings.Split(list, ",") {
+		if category = strings.TrimSpace(category); category == "" {
+			continue
+		}
+		if !isCategory(category) {
+			return fmt.Errorf("unknown check %s: must be one of the categories %v", category, categories)
+		}
+		if *f.set == nil {
+			*f.set = make(map[string]bool)
+		}
+		(*f.set)[category] = true
+	}
+	return nil
+}
+
+// withSeverities returns a copy of pass which reports the diagnostics with
+// the severities of their categories.
+func withSeverities(pass *analysis.Pass) *analysis.Pass {
+	if len(severities) == 0 {
+		return pass
+	}
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		switch severity := severities[d.Category]; severity {
+		case sevOff:
+			return
+		case sevWarning, sevInfo:
+			d.Message = severity + ": " + d.Message
+		}
+		pass.Report(d)
+	}
+	return &p
+}
+
+// reportf reports a diagnostic of category at pos.
+func reportf(pass *analysis.Pass, category string, pos token.Pos, format string, args ...interface{}) {
+	pass.Report(analysis.Diagnostic{Pos: pos, Category: category, Message: fmt.Sprintf(format, args...)})
+}
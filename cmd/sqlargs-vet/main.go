@@ -0,0 +1,16 @@
+// package main runs the sqlargs analyzers as a go vet tool:
+//
+//	go vet -vettool=$(which sqlargs-vet) ./...
+//
+// The go command caches the results of each package, and passes the facts of
+// the dependencies, so this is faster than sqlargs on large codebases.
+package main
+
+import (
+	"github.com/agnivade/sqlargs"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(sqlargs.Analyzer)
+}
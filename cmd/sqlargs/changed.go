@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// changedArgs returns the args of the analyzer which analyze the packages of
+// the changed files of args, for sqlargs changed [-flag=value ...] [file ...].
+// The flags are passed through, so their values must follow a =. Without
+// files, the files changed since HEAD are taken from git, so that this can be
+// run as a pre-commit hook, which passes the staged files instead. It returns
+// no packages if no Go file changed.
+func changedArgs(args []string) (flags, pkgs []string, err error) {
+	var files []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			files = append(files, arg)
+		}
+	}
+	if len(files) == 0 {
+		out, err := exec.Command("git", "diff", "--name-only", "--relative", "HEAD").Output()
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing the changed files: git diff: %v", err)
+		}
+		files = strings.Fields(string(out))
+	}
+	dirs := make(map[string]bool)
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".go") {
+			continue
+		}
+		// The package of a deleted file changed too, if it still exists.
+		dir := filepath.Dir(file)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			dirs[dir] = true
+		}
+	}
+	for dir := range dirs {
+		if !filepath.IsAbs(dir) {
+			dir = "." + string(filepath.Separator) + dir
+		}
+		pkgs = append(pkgs, dir)
+	}
+	sort.Strings(pkgs)
+	return flags, pkgs, nil
+}
@@ -0,0 +1,12 @@
+//go:build drivers
+
+package main
+
+// The drivers -dsn can connect to. They are only linked into binaries built
+// with the drivers tag.
+import (
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	_ "github.com/microsoft/go-mssqldb"
+)
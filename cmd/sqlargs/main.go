@@ -1,9 +1,86 @@
-// package main runs the sqlargs analyzer.
+// package main runs the sqlargs analyzer, as in sqlargs ./..., with the flags
+// of the analyzer. It can also be run by go vet, with -vettool.
+//
+// Run as sqlargs drift dir, it prints the report of the usages of the schema
+// written to dir with -drift. Run as sqlargs sarif, it converts the -json
+// output of sqlargs read from stdin to a SARIF log:
+//
+//	sqlargs -json ./... | sqlargs sarif > sqlargs.sarif
+//
+// Run as sqlargs github, it converts it to GitHub Actions annotations.
+//
+// Run as sqlargs stats dir, it prints the summary of the statistics written
+// to dir with -stats.
+//
+// Run as sqlargs changed [-flag=value ...] [file ...], it only analyzes the
+// packages of the changed files, by default the ones changed since HEAD.
+//
+// Run as sqlargs report [-format=json|csv] dir, it lists the queries written
+// to dir with -inventory.
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
 	"github.com/agnivade/sqlargs"
 	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
-func main() { singlechecker.Main(sqlargs.Analyzer) }
+func main() {
+	if len(os.Args) == 3 && os.Args[1] == "drift" {
+		if err := sqlargs.DriftReport(os.Args[2], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "sqlargs:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) == 3 && os.Args[1] == "stats" {
+		if err := sqlargs.StatsReport(os.Args[2], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "sqlargs:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "report" {
+		fs := flag.NewFlagSet("report", flag.ExitOnError)
+		format := fs.String("format", "json", "format of the report: json or csv")
+		fs.Parse(os.Args[2:])
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "usage: sqlargs report [-format=json|csv] dir")
+			os.Exit(2)
+		}
+		if err := sqlargs.InventoryReport(fs.Arg(0), *format, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "sqlargs:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) >= 2 && os.Args[1] == "changed" {
+		flags, pkgs, err := changedArgs(os.Args[2:])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "sqlargs:", err)
+			os.Exit(1)
+		}
+		if len(pkgs) == 0 {
+			return
+		}
+		os.Args = append(append([]string{os.Args[0]}, flags...), pkgs...)
+	}
+	if len(os.Args) == 2 && os.Args[1] == "sarif" {
+		if err := sqlargs.SARIF(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "sqlargs:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) == 2 && os.Args[1] == "github" {
+		if err := sqlargs.GitHubAnnotations(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "sqlargs:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	singlechecker.Main(sqlargs.Analyzer)
+}
@@ -0,0 +1,21 @@
+// package main runs the sqlargs sub-analyzers, as in sqlcheck ./..., so that
+// their findings are reported under their own names. The analyzers share
+// their flags, so -sqlargcount.dialect=mysql selects the dialect of all of
+// them. A single analyzer can be run with, e.g., -sqlinjection.
+package main
+
+import (
+	"github.com/agnivade/sqlargs"
+	"golang.org/x/tools/go/analysis/multichecker"
+)
+
+func main() {
+	multichecker.Main(
+		sqlargs.ArgCount,
+		sqlargs.Syntax,
+		sqlargs.Injection,
+		sqlargs.Schema,
+		sqlargs.ResourceUse,
+		sqlargs.Policy,
+	)
+}
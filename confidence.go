@@ -0,0 +1,98 @@
+package sqlargs
+
+import (
+	"fmt"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// confidence is how likely a finding is to be a real problem.
+type confidence int
+
+const (
+	// confLow is for findings on queries which are only known through
+	// heuristics, like the ones built with text/template.
+	confLow confidence = iota
+	// confMedium is for findings relying on inferred facts, like the length of
+	// a spread slice or the dialect of a query.
+	confMedium
+	// confHigh is for findings on constant queries whose args are listed in
+	// the call.
+	confHigh
+)
+
+var confidenceNames = []string{"low", "medium", "high"}
+
+func (c confidence) String() string {
+	return confidenceNames[c]
+}
+
+// minConfidence is the confidence selected with -min-confidence, below which
+// findings are not reported.
+var minConfidence = confLow
+
+// confidenceFlag is a flag.Value selecting a confidence by name.
+type confidenceFlag struct {
+	c *confidence
+}
+
+func (f confidenceFlag) String() string {
+	if f.c == nil {
+		return ""
+	}
+	return f.c.String()
+}
+
+func (f confidenceFlag) Set(name string) error {
+	for i, n := range confidenceNames {
+		if n == name {
+			*f.c = confidence(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown confidence %q, must be one of low, medium, high", name)
+}
+
+// scorer scores the findings of the call being analyzed.
+type scorer struct {
+	// level is the confidence of the findings on the current call.
+	level confidence
+}
+
+// wrap returns a copy of pass which does not report the findings scored below
+// minConfidence.
+func (s *scorer) wrap(pass *analysis.Pass) *analysis.Pass {
+	if minConfidence == confLow {
+		return pass
+	}
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		level := s.level
+		// Interpolated values may well be validated before being used.
+		if d.Category == catInjection && level > confMedium {
+			level = confMedium
+		}
+		if level < minConfidence {
+			return
+		}
+		pass.Report(d)
+	}
+	return &p
+}
+
+// lower lowers the confidence of the findings on the current call to c, if it
+// is higher.
+func (s *scorer) lower(c confidence) {
+	if s.level > c {
+		s.level = c
+	}
+}
+
+// within runs check with the confidence of its findings lowered to c, without
+// lowering the one of the other findings on the current call.
+func (s *scorer) within(c confidence, check func()) {
+	level := s.level
+	s.lower(c)
+	check()
+	s.level = level
+}
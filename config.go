@@ -0,0 +1,356 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// config is the configuration of the checks. The one of Analyzer and the
+// sub-analyzers is set with flags, and the one of NewAnalyzer with Options.
+type config struct {
+	// dialect is the dialect of the queries, or permissive to detect it.
+	dialect *dialect
+	// strict makes the analyzer report every recognized call whose query
+	// cannot be determined statically.
+	strict bool
+	// requireWhere makes the analyzer report constant UPDATE and DELETE
+	// statements without a WHERE clause.
+	requireWhere bool
+	// groupBy makes the analyzer report select lists mixing aggregates with
+	// columns which are not grouped.
+	groupBy bool
+	// selectStar makes the analyzer report constant queries selecting *.
+	selectStar bool
+	// insertColumns makes the analyzer report constant INSERT statements
+	// without a column list.
+	insertColumns bool
+	// loopQueries makes the analyzer report constant queries run in loops.
+	loopQueries bool
+	// errNoRows makes the analyzer report errors of QueryRow handled as
+	// failures, without a check for sql.ErrNoRows.
+	errNoRows bool
+	// uncheckedExec makes the analyzer report Exec calls whose error is
+	// discarded.
+	uncheckedExec bool
+	// contextMethods makes the analyzer report the calls of Exec, Query and
+	// QueryRow in functions with a context.Context param.
+	contextMethods bool
+	// ddl makes the analyzer check the syntax of the constant CREATE TABLE,
+	// CREATE INDEX and ALTER TABLE statements run with Exec.
+	ddl bool
+	// duplicateQueries makes the analyzer report the constant queries which
+	// are defined more than once.
+	duplicateQueries bool
+	// unusedQueries makes the analyzer report the query constants which are
+	// never used.
+	unusedQueries bool
+	// duplicateArgs makes the analyzer report the variables passed for
+	// placeholders of columns of different names.
+	duplicateArgs bool
+	// sqlConstants makes the analyzer check the string constants which look
+	// like queries, whether they are run or not.
+	sqlConstants bool
+	// swappedArgs makes the analyzer report the variables named after each
+	// other's columns.
+	swappedArgs bool
+	// requireConst makes the analyzer report every recognized call whose
+	// query is not a constant.
+	requireConst bool
+	// schemaFile is the DDL file, or the directory of migrations, with the
+	// schema the queries are checked against.
+	schemaFile string
+	// parser is the name of the backend validating the syntax of the
+	// queries, or "" for the default one.
+	parser string
+	// sanitizers are the functions whose results are safe to interpolate
+	// into queries.
+	sanitizers map[string]bool
+	// funcs are the functions, as pkgpath.Func or pkgpath.Type.Method, which
+	// run queries like Exec: their first string param is the query, and their
+	// variadic param the args.
+	funcs map[string]bool
+	// disabled are the categories of the diagnostics which are not reported.
+	disabled map[string]bool
+	// only are the categories of the diagnostics which are reported, if it is
+	// not empty.
+	only map[string]bool
+	// visitors are the custom checks run on every query known statically.
+	visitors []QueryVisitor
+	// skipGenerated makes the analyzer skip the files with a
+	// "// Code generated ... DO NOT EDIT." header.
+	skipGenerated bool
+	// exclude are the globs of the files which are not analyzed.
+	exclude []string
+	// skipTests makes the analyzer skip the _test.go files.
+	skipTests bool
+	// imports are the packages running queries, in addition to
+	// defaultImports, whose importers are analyzed.
+	imports []string
+}
+
+// flagConfig is the configuration set with the flags.
+var flagConfig = &config{dialect: permissive, sanitizers: copySet(defaultSanitizers)}
+
+func copySet(set map[string]bool) map[string]bool {
+	c := make(map[string]bool, len(set))
+	for k := range set {
+		c[k] = true
+	}
+	return c
+}
+
+// Options configure an analyzer created with NewAnalyzer. The zero value runs
+// the same checks as Analyzer without flags.
+type Options struct {
+	// Dialect is the SQL dialect of the queries, as for -dialect. By default,
+	// it is detected.
+	Dialect string
+	// ExtraFuncs are functions or methods, written as pkgpath.Func or
+	// pkgpath.Type.Method, which run queries like Exec, e.g. wrappers of
+	// database/sql. Their first string param is the query, and their variadic
+	// param the args.
+	ExtraFuncs []string
+	// DisabledChecks are the categories of the diagnostics which are not
+	// reported, like "selectstar".
+	DisabledChecks []string
+	// OnlyChecks are the only categories of the diagnostics which are
+	// reported, like "argcount", if it is not empty.
+	OnlyChecks []string
+	// Sanitizers are functions, as for -sanitizers, whose results are safe to
+	// interpolate into queries, in addition to the default ones.
+	Sanitizers []string
+	// Schema is the DDL file, or directory of migrations, as for -schema.
+	Schema string
+	// Parser is the backend validating the syntax of the queries, as for
+	// -parser. By default, it is pg_query when built with the sqlargs_pgquery
+	// tag, and the lexer otherwise.
+	Parser string
+	// Visitors are custom checks, run on every query which is known
+	// statically, before the checks of the analyzer.
+	Visitors []QueryVisitor
+	// SkipTypeErrors skips the packages with type errors, as for
+	// -skip-type-errors.
+	SkipTypeErrors bool
+	// SkipGenerated skips the generated files, as for -skip-generated.
+	SkipGenerated bool
+	// Exclude are globs of files which are not analyzed, as for -exclude.
+	Exclude []string
+	// SkipTests skips the _test.go files, as for -skip-tests.
+	SkipTests bool
+	// Imports are packages running queries, as for -imports. Packages which
+	// import them, even transitively, are analyzed.
+	Imports []string
+
+	// The opt-in checks, as for the flags of the same names.
+	Strict              bool
+	RequireWhere        bool
+	GroupBy             bool
+	SelectStar          bool
+	InsertColumns       bool
+	RequireConstQueries bool
+	LoopQueries         bool
+	ErrNoRows           bool
+	UncheckedExec       bool
+	ContextMethods      bool
+	DDL                 bool
+	DuplicateQueries    bool
+	UnusedQueries       bool
+	DuplicateArgs       bool
+	SQLConstants        bool
+	SwappedArgs         bool
+}
+
+// NewAnalyzer returns an analyzer running the checks of Analyzer, configured
+// with opts instead of flags, for programs embedding it. Settings which are
+// shared by the whole process, like -cache and -dsn, are still taken from
+// the flags of Analyzer. Its name is sqlargs too, so it cannot be registered
+// along with Analyzer.
+func NewAnalyzer(opts Options) (*analysis.Analyzer, error) {
+	cfg := &config{
+		dialect:          permissive,
+		strict:           opts.Strict,
+		requireWhere:     opts.RequireWhere,
+		groupBy:          opts.GroupBy,
+		selectStar:       opts.SelectStar,
+		insertColumns:    opts.InsertColumns,
+		requireConst:     opts.RequireConstQueries,
+		loopQueries:      opts.LoopQueries,
+		errNoRows:        opts.ErrNoRows,
+		uncheckedExec:    opts.UncheckedExec,
+		contextMethods:   opts.ContextMethods,
+		ddl:              opts.DDL,
+		duplicateQueries: opts.DuplicateQueries,
+		unusedQueries:    opts.UnusedQueries,
+		duplicateArgs:    opts.DuplicateArgs,
+		sqlConstants:     opts.SQLConstants,
+		swappedArgs:      opts.SwappedArgs,
+		schemaFile:       opts.Schema,
+		sanitizers:       copySet(defaultSanitizers),
+		funcs:            make(map[string]bool),
+		visitors:         opts.Visitors,
+		skipGenerated:    opts.SkipGenerated,
+		skipTests:        opts.SkipTests,
+		imports:          opts.Imports,
+	}
+	if opts.Dialect != "" {
+		if err := (dialectFlag{&cfg.dialect}).Set(opts.Dialect); err != nil {
+			return nil, err
+		}
+	}
+	if err := (parserFlag{&cfg.parser}).Set(opts.Parser); err != nil {
+		return nil, err
+	}
+	for _, glob := range opts.Exclude {
+		if err := (globsFlag{&cfg.exclude}).Set(glob); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range opts.Sanitizers {
+		cfg.sanitizers[name] = true
+	}
+	for _, name := range opts.ExtraFuncs {
+		cfg.funcs[name] = true
+	}
+	for _, category := range opts.DisabledChecks {
+		if err := (categoriesFlag{&cfg.disabled}).Set(category); err != nil {
+			return nil, err
+		}
+	}
+	for _, category := range opts.OnlyChecks {
+		if err := (categoriesFlag{&cfg.only}).Set(category); err != nil {
+			return nil, err
+		}
+	}
+	a := newAnalyzer("sqlargs", Doc, newExtract(cfg), categories...)
+	skipTypeErrors(!opts.SkipTypeErrors, a)
+	return a, nil
+}
+
+// skipTypeErrorsFlag is a flag.Value making the analyzers of the package skip
+// the packages with type errors, whose type info is incomplete.
+type skipTypeErrorsFlag struct{}
+
+func (skipTypeErrorsFlag) String() string {
+	return strconv.FormatBool(!extract.RunDespiteErrors)
+}
+
+func (skipTypeErrorsFlag) Set(value string) error {
+	skip, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	skipTypeErrors(!skip, analyzers...)
+	return nil
+}
+
+func (skipTypeErrorsFlag) IsBoolFlag() bool { return true }
+
+// skipTypeErrors sets RunDespiteErrors of the analyzers, and of the extract
+// analyzer they require, to runDespiteErrors.
+func skipTypeErrors(runDespiteErrors bool, analyzers ...*analysis.Analyzer) {
+	for _, a := range analyzers {
+		a.RunDespiteErrors = runDespiteErrors
+		for _, req := range a.Requires {
+			if req.Name == "sqlargsextract" {
+				req.RunDespiteErrors = runDespiteErrors
+			}
+		}
+	}
+}
+
+// withDisabled returns a copy of pass which does not report the diagnostics
+// of the categories disabled in cfg, nor, if it has only categories, of the
+// other ones.
+func (cfg *config) withDisabled(pass *analysis.Pass) *analysis.Pass {
+	if len(cfg.disabled) == 0 && len(cfg.only) == 0 {
+		return pass
+	}
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		if !cfg.disabled[d.Category] && (len(cfg.only) == 0 || cfg.only[d.Category]) {
+			pass.Report(d)
+		}
+	}
+	return &p
+}
+
+// queryCall returns call with the query as its first arg, followed by the args
+// of the query, along with the name of the method run, if it runs a query.
+// These are the Exec, Query and QueryRow methods of sql.DB and sql.Tx, and
+// their Context variants, whose method is the one without the suffix, the ones
+// of sqlx, like Get, and the funcs of cfg. The method of a func is its name if
+// it is one of these, and "" otherwise.
+func (cfg *config) queryCall(call *ast.CallExpr, info *types.Info) (*ast.CallExpr, string, bool) {
+	// A CallExpr has 2 parts - Fun and Args.
+	// A Fun can either be an Ident (Fun()) or a SelectorExpr (foo.Fun()).
+	// Since we are looking for patterns like db.Exec, we need to filter only SelectorExpr
+	// We will ignore dot imported functions.
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		// A SelectorExpr(db.Exec) has 2 parts - X (db) and Sel (Exec/Query/QueryRow).
+		// Now that we are inside the SelectorExpr, we need to verify 2 things -
+		// 1. The function name is Exec, Query or QueryRow, or one of their Context variants; because that is what we are interested in.
+		// 2. The type of the selector is sql.DB or sql.Tx.
+		if isProperSelExpr(sel, info) {
+			method := strings.TrimSuffix(sel.Sel.Name, "Context")
+			if method == sel.Sel.Name {
+				return call, method, true
+			}
+			// The query of the Context variants follows the context.
+			if len(call.Args) < 2 || !isContext(info.TypeOf(call.Args[0])) {
+				return nil, "", false
+			}
+			query := *call
+			query.Args = call.Args[1:]
+			return &query, method, true
+		}
+	}
+	if query, method, ok := sqlxCall(call, info); ok {
+		return query, method, true
+	}
+	if len(cfg.funcs) == 0 {
+		return nil, "", false
+	}
+	var fun *ast.Ident
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		fun = f
+	case *ast.SelectorExpr:
+		fun = f.Sel
+	default:
+		return nil, "", false
+	}
+	f, ok := info.Uses[fun].(*types.Func)
+	if !ok || !cfg.funcs[funcName(f)] {
+		return nil, "", false
+	}
+	sig := f.Type().(*types.Signature)
+	params := sig.Params()
+	if !sig.Variadic() {
+		return nil, "", false
+	}
+	for i := 0; i < params.Len()-1; i++ {
+		if basic, ok := params.At(i).Type().Underlying().(*types.Basic); !ok || basic.Kind() != types.String {
+			continue
+		}
+		if len(call.Args) < params.Len()-1 {
+			return nil, "", false
+		}
+		// The args of the query follow the query itself, as in Exec.
+		query := *call
+		query.Args = call.Args[i:]
+		if i != params.Len()-2 {
+			query.Args = append([]ast.Expr{call.Args[i]}, call.Args[params.Len()-1:]...)
+		}
+		method := ""
+		switch f.Name() {
+		case "Exec", "Query", "QueryRow":
+			method = f.Name()
+		}
+		return &query, method, true
+	}
+	return nil, "", false
+}
@@ -0,0 +1,86 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkContextMethod reports call, which runs method, like Exec, when the
+// function enclosing it has a context.Context param, given the stack of nodes
+// enclosing call, with a fix calling the Context variant of method with it, so
+// that the cancellation of the context is propagated to the query.
+func checkContextMethod(call *ast.CallExpr, method string, stack []ast.Node, pass *analysis.Pass) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	ctx := contextParam(stack, pass.TypesInfo)
+	if ctx == "" {
+		return
+	}
+	pass.Report(analysis.Diagnostic{
+		Pos:      sel.Sel.Pos(),
+		End:      sel.Sel.End(),
+		Category: catContextMethods,
+		Message:  fmt.Sprintf("%s does not use the context %s of the function: use %sContext, so that its cancellation is propagated", method, ctx, method),
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message: "Use " + method + "Context",
+			TextEdits: []analysis.TextEdit{
+				{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte(method + "Context")},
+				{Pos: call.Args[0].Pos(), End: call.Args[0].Pos(), NewText: []byte(ctx + ", ")},
+			},
+		}},
+	})
+}
+
+// contextParam returns the name of the context.Context param of the innermost
+// function of stack, or "" if it has none.
+func contextParam(stack []ast.Node, info *types.Info) string {
+	var typ *ast.FuncType
+	for i := len(stack) - 1; i >= 0 && typ == nil; i-- {
+		switch f := stack[i].(type) {
+		case *ast.FuncDecl:
+			typ = f.Type
+		case *ast.FuncLit:
+			typ = f.Type
+		}
+	}
+	if typ == nil {
+		return ""
+	}
+	for _, field := range typ.Params.List {
+		if !isContext(info.TypeOf(field.Type)) {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				return name.Name
+			}
+		}
+	}
+	return ""
+}
+
+// isContext reports whether t is context.Context.
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// checkContextArg reports call if it runs a Context variant of Exec, Query or
+// QueryRow with a string as its first arg, like a query passed before the
+// context in code which does not compile, as the query is then not
+// recognized.
+func checkContextArg(call *ast.CallExpr, pass *analysis.Pass) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasSuffix(sel.Sel.Name, "Context") || len(call.Args) == 0 || !isProperSelExpr(sel, pass.TypesInfo) {
+		return
+	}
+	if basic, ok := pass.TypesInfo.TypeOf(call.Args[0]).(*types.Basic); ok && basic.Info()&types.IsString != 0 {
+		reportf(pass, catArgType, call.Args[0].Pos(), "First arg of %s is a string, not a context.Context: pass the context first, then the query", sel.Sel.Name)
+	}
+}
@@ -0,0 +1,192 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// createModifiers are the keywords which can come between CREATE and the kind
+// of the object created.
+var createModifiers = keywordSet("GLOBAL", "LOCAL", "OR", "REPLACE", "TEMP", "TEMPORARY", "UNIQUE", "UNLOGGED")
+
+// alterActions are the keywords starting an action of ALTER TABLE.
+var alterActions = keywordSet("ADD", "ALTER", "ATTACH", "CHANGE", "CLUSTER", "DETACH", "DISABLE", "DROP",
+	"ENABLE", "FORCE", "INHERIT", "MODIFY", "NO", "OWNER", "RENAME", "REPLICA", "RESET", "SET", "VALIDATE")
+
+// checkDDL reports the CREATE TABLE, CREATE INDEX and ALTER TABLE statements
+// of query which are malformed, like a column without a type or an index
+// without ON, for the migrations run with Exec. The other statements are left
+// to the query parser.
+func checkDDL(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	for _, stmt := range statements(query, d) {
+		if len(stmt) < 2 {
+			continue
+		}
+		switch strings.ToUpper(stmt[0].text) {
+		case "CREATE":
+			i := 1
+			for i < len(stmt) && stmt[i].kind == lexWord && createModifiers[strings.ToUpper(stmt[i].text)] {
+				i++
+			}
+			if i == len(stmt) {
+				continue
+			}
+			switch strings.ToUpper(stmt[i].text) {
+			case "TABLE":
+				checkCreateTable(stmt, i, d, call, pass)
+			case "INDEX":
+				checkCreateIndex(stmt, i, call, pass)
+			}
+		case "ALTER":
+			if strings.EqualFold(stmt[1].text, "TABLE") {
+				checkAlterTable(stmt, d, call, pass)
+			}
+		}
+	}
+}
+
+// checkCreateTable checks the CREATE TABLE statement stmt, whose TABLE keyword
+// is stmt[i].
+func checkCreateTable(stmt []lexeme, i int, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	keyword := stmt[i]
+	i = skipWords(stmt, i+1, "IF", "NOT", "EXISTS")
+	name, i := qualifiedName(stmt, i)
+	if name == nil {
+		reportQuery(pass, call, catSyntax, keyword.pos, len(keyword.text), "CREATE TABLE without a table name")
+		return
+	}
+	if i == len(stmt) {
+		reportQuery(pass, call, catSyntax, name.pos, len(name.text), "CREATE TABLE %s without column definitions", name.text)
+		return
+	}
+	switch next := stmt[i]; {
+	case next.text == "(":
+	case next.kind == lexWord && (strings.EqualFold(next.text, "AS") || strings.EqualFold(next.text, "OF") ||
+		strings.EqualFold(next.text, "PARTITION") || strings.EqualFold(next.text, "LIKE")):
+		// Tables created from a query, a type or another table.
+		return
+	default:
+		reportQuery(pass, call, catSyntax, next.pos, len(next.text), "Unexpected %s after CREATE TABLE %s: expected the column definitions in parentheses", next.text, name.text)
+		return
+	}
+	for _, def := range splitList(stmt, i) {
+		checkColumnDef(def, d, call, pass)
+	}
+}
+
+// checkColumnDef reports the column definition def which has no type. SQLite
+// is the only dialect where the type is optional.
+func checkColumnDef(def []lexeme, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	if d.name == "sqlite" || columnDef(def) == nil {
+		return
+	}
+	if len(def) == 1 || def[1].kind == lexWord && columnOptions[strings.ToUpper(def[1].text)] {
+		reportQuery(pass, call, catSyntax, def[0].pos, len(def[0].text), "Column %s has no type", def[0].text)
+	}
+}
+
+// checkCreateIndex checks the CREATE INDEX statement stmt, whose INDEX keyword
+// is stmt[i].
+func checkCreateIndex(stmt []lexeme, i int, call *ast.CallExpr, pass *analysis.Pass) {
+	keyword := stmt[i]
+	i = skipWords(stmt, i+1, "CONCURRENTLY")
+	i = skipWords(stmt, i, "IF", "NOT", "EXISTS")
+	// The name of the index is optional in Postgres.
+	if i < len(stmt) && !strings.EqualFold(stmt[i].text, "ON") {
+		_, i = qualifiedName(stmt, i)
+	}
+	if i == len(stmt) || !strings.EqualFold(stmt[i].text, "ON") {
+		reportQuery(pass, call, catSyntax, keyword.pos, len(keyword.text), "CREATE INDEX without ON: expected the table the index is on")
+		return
+	}
+	name, i := qualifiedName(stmt, skipWords(stmt, i+1, "ONLY"))
+	if name == nil {
+		reportQuery(pass, call, catSyntax, stmt[i-1].pos, len(stmt[i-1].text), "CREATE INDEX without a table after ON")
+		return
+	}
+	if i < len(stmt) && strings.EqualFold(stmt[i].text, "USING") {
+		// The index method, like gin.
+		i += 2
+	}
+	if i >= len(stmt) || stmt[i].text != "(" || len(splitList(stmt, i)) == 0 {
+		reportQuery(pass, call, catSyntax, name.pos, len(name.text), "CREATE INDEX on %s without a column list", name.text)
+	}
+}
+
+// checkAlterTable checks the actions of the ALTER TABLE statement stmt.
+func checkAlterTable(stmt []lexeme, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	i := skipWords(stmt, 2, "IF", "EXISTS")
+	i = skipWords(stmt, i, "ONLY")
+	name, i := qualifiedName(stmt, i)
+	if name == nil {
+		reportQuery(pass, call, catSyntax, stmt[1].pos, len(stmt[1].text), "ALTER TABLE without a table name")
+		return
+	}
+	if i == len(stmt) {
+		reportQuery(pass, call, catSyntax, name.pos, len(name.text), "ALTER TABLE %s without an action", name.text)
+		return
+	}
+	depth, start := 0, i
+	for j := i; j <= len(stmt); j++ {
+		if j == len(stmt) || depth == 0 && stmt[j].text == "," {
+			if action := stmt[start:j]; len(action) > 0 {
+				checkAlterAction(action, d, call, pass)
+			}
+			start = j + 1
+			continue
+		}
+		switch stmt[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+	}
+}
+
+// checkAlterAction checks an action of ALTER TABLE, reporting unknown ones and
+// added columns without a type.
+func checkAlterAction(action []lexeme, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	first := action[0]
+	if first.kind != lexWord || !alterActions[strings.ToUpper(first.text)] {
+		reportQuery(pass, call, catSyntax, first.pos, len(first.text), "Unknown ALTER TABLE action %s: expected one like ADD, DROP, ALTER, RENAME or SET", first.text)
+		return
+	}
+	if !strings.EqualFold(first.text, "ADD") {
+		return
+	}
+	j := skipWords(action, 1, "COLUMN")
+	j = skipWords(action, j, "IF", "NOT", "EXISTS")
+	if j == len(action) {
+		reportQuery(pass, call, catSyntax, first.pos, len(first.text), "ADD without a column or constraint")
+		return
+	}
+	checkColumnDef(action[j:], d, call, pass)
+}
+
+// ddlKeywords are the keywords starting the DDL statements, which most
+// databases do not allow bind parameters in.
+var ddlKeywords = keywordSet("ALTER", "CREATE", "DROP")
+
+// checkDDLPlaceholders reports the placeholders of the DDL statements of
+// query. Postgres and MySQL fail to prepare them, and SQLite rejects them in
+// most clauses, like DEFAULT. It returns false if anything was reported, as
+// the no. of args then does not matter.
+func checkDDLPlaceholders(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) bool {
+	ok := true
+	for _, stmt := range statements(query, d) {
+		keyword := strings.ToUpper(stmt[0].text)
+		if stmt[0].kind != lexWord || !ddlKeywords[keyword] {
+			continue
+		}
+		for _, l := range stmt {
+			if l.kind == lexPlaceholder {
+				reportQuery(pass, call, catPlaceholderStyle, l.pos, len(l.text), "Placeholder %s in DDL: %s statements cannot have bind parameters, write the value in the statement", l.text, keyword)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
@@ -0,0 +1,524 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// dialect describes the flavour of SQL the queries are written in.
+type dialect struct {
+	name string
+	// dollarParams enables $N placeholders.
+	dollarParams bool
+	// questionParams enables ? placeholders.
+	questionParams bool
+	// colonParams enables :name and :N placeholders.
+	colonParams bool
+	// colonPaths makes a : right after an identifier or a ] the path into a
+	// semi-structured value, like v:name, instead of a placeholder.
+	colonPaths bool
+	// atParams enables @name and @pN placeholders.
+	atParams bool
+	// braceParams enables the {name:Type} server-side parameters of
+	// ClickHouse.
+	braceParams bool
+	// backslashEscapes makes a backslash escape the next character in string
+	// literals.
+	backslashEscapes bool
+	// hashComments makes # start a line comment.
+	hashComments bool
+	// doubleQuoteStrings makes "" quote string literals instead of
+	// identifiers.
+	doubleQuoteStrings bool
+	// sqliteParams enables ?NNN and $name placeholders, and makes all
+	// placeholders be bound the way SQLite does.
+	sqliteParams bool
+	// pgGrammar makes queries be validated with the Postgres parser.
+	pgGrammar bool
+	// multiStatements allows binding args to queries with more than one
+	// statement.
+	multiStatements bool
+	// maxParams is the no. of bind parameters above which the database
+	// rejects a query, or 0 if it is not known.
+	maxParams int
+	// reservedWords are the reserved keywords which are commonly mistaken
+	// for identifiers.
+	reservedWords map[string]bool
+	// foreignFuncs maps the functions of other dialects which do not exist in
+	// this one to their replacement.
+	foreignFuncs map[string]string
+}
+
+// permissive is used when no dialect is selected. It counts both $N and ?
+// placeholders, and validates queries which do not use ? with the Postgres
+// parser.
+var permissive = &dialect{
+	dollarParams:   true,
+	questionParams: true,
+	pgGrammar:      true,
+	reservedWords:  postgresReserved,
+}
+
+// dialects are all the dialects which can be selected with the -dialect flag.
+var dialects = map[string]*dialect{
+	"postgres": {
+		name:          "postgres",
+		dollarParams:  true,
+		pgGrammar:     true,
+		maxParams:     65535,
+		reservedWords: postgresReserved,
+		foreignFuncs:  postgresForeignFuncs,
+	},
+	"mysql": {
+		name:               "mysql",
+		questionParams:     true,
+		backslashEscapes:   true,
+		hashComments:       true,
+		doubleQuoteStrings: true,
+		maxParams:          65535,
+		reservedWords:      mysqlReserved,
+		foreignFuncs:       mysqlForeignFuncs,
+	},
+	"sqlite": {
+		name:            "sqlite",
+		questionParams:  true,
+		colonParams:     true,
+		atParams:        true,
+		sqliteParams:    true,
+		multiStatements: true,
+		maxParams:       32766,
+		reservedWords:   sqliteReserved,
+		foreignFuncs:    sqliteForeignFuncs,
+	},
+	"sqlserver": {
+		name:            "sqlserver",
+		atParams:        true,
+		multiStatements: true,
+		maxParams:       2100,
+		reservedWords:   sqlserverReserved,
+		foreignFuncs:    sqlserverForeignFuncs,
+	},
+	"oracle": {
+		name:          "oracle",
+		colonParams:   true,
+		reservedWords: oracleReserved,
+		foreignFuncs:  oracleForeignFuncs,
+	},
+	"snowflake": {
+		name:             "snowflake",
+		questionParams:   true,
+		colonParams:      true,
+		colonPaths:       true,
+		backslashEscapes: true,
+		reservedWords:    snowflakeReserved,
+		foreignFuncs:     snowflakeForeignFuncs,
+	},
+	"clickhouse": {
+		name:             "clickhouse",
+		questionParams:   true,
+		braceParams:      true,
+		backslashEscapes: true,
+		hashComments:     true,
+	},
+}
+
+// driverImports maps the import paths of well known drivers to the name of
+// their dialect.
+var driverImports = map[string]string{
+	"github.com/lib/pq":                       "postgres",
+	"github.com/jackc/pgx/stdlib":             "postgres",
+	"github.com/jackc/pgx/v4/stdlib":          "postgres",
+	"github.com/jackc/pgx/v5/stdlib":          "postgres",
+	"github.com/go-sql-driver/mysql":          "mysql",
+	"github.com/ziutek/mymysql/godrv":         "mysql",
+	"github.com/mattn/go-sqlite3":             "sqlite",
+	"modernc.org/sqlite":                      "sqlite",
+	"github.com/glebarez/go-sqlite":           "sqlite",
+	"github.com/ncruces/go-sqlite3/driver":    "sqlite",
+	"github.com/denisenkom/go-mssqldb":        "sqlserver",
+	"github.com/microsoft/go-mssqldb":         "sqlserver",
+	"github.com/microsoft/go-mssqldb/azuread": "sqlserver",
+	"github.com/godror/godror":                "oracle",
+	"github.com/mattn/go-oci8":                "oracle",
+	"github.com/sijms/go-ora/v2":              "oracle",
+	"github.com/snowflakedb/gosnowflake":      "snowflake",
+	"github.com/ClickHouse/clickhouse-go":     "clickhouse",
+	"github.com/ClickHouse/clickhouse-go/v2":  "clickhouse",
+}
+
+// driverNames maps the names drivers register themselves with in database/sql
+// to the name of their dialect.
+var driverNames = map[string]string{
+	"postgres":   "postgres",
+	"pgx":        "postgres",
+	"pgx/v5":     "postgres",
+	"mysql":      "mysql",
+	"mymysql":    "mysql",
+	"sqlite3":    "sqlite",
+	"sqlite":     "sqlite",
+	"sqlserver":  "sqlserver",
+	"mssql":      "sqlserver",
+	"azuresql":   "sqlserver",
+	"godror":     "oracle",
+	"oci8":       "oracle",
+	"oracle":     "oracle",
+	"snowflake":  "snowflake",
+	"clickhouse": "clickhouse",
+}
+
+// driverDialect is the fact exported for the packages whose dialect was
+// detected, so that the packages importing them, like the stores using the
+// connection opened by a db package, are checked in it too.
+type driverDialect struct {
+	Name string
+}
+
+func (*driverDialect) AFact() {}
+
+func (f *driverDialect) String() string {
+	return "dialect " + f.Name
+}
+
+// detectDialect returns the dialect of the driver used by the package, based on
+// its imports and the driver names passed to sql.Open, or to the Open and
+// Connect of sqlx. Without any, it is the dialect detected for the packages it
+// imports. It returns nil if there is no driver, or if drivers of different
+// dialects are used.
+func detectDialect(pass *analysis.Pass) *dialect {
+	var found *dialect
+	ambiguous := false
+	use := func(name string) {
+		d := dialects[name]
+		if found != nil && found != d {
+			ambiguous = true
+		}
+		found = d
+	}
+	for _, f := range pass.Files {
+		for _, imp := range f.Imports {
+			path, err := strconv.Unquote(imp.Path.Value)
+			if err != nil {
+				continue
+			}
+			if name, ok := driverImports[path]; ok {
+				use(name)
+			}
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) != 2 || !isOpen(call.Fun, pass.TypesInfo) {
+				return true
+			}
+			typ, ok := pass.TypesInfo.Types[call.Args[0]]
+			if !ok || typ.Value == nil || typ.Value.Kind() != constant.String {
+				return true
+			}
+			if name, ok := driverNames[constant.StringVal(typ.Value)]; ok {
+				use(name)
+			}
+			return true
+		})
+	}
+	if found == nil {
+		for _, imp := range pass.Pkg.Imports() {
+			var fact driverDialect
+			if pass.ImportPackageFact(imp, &fact) && dialects[fact.Name] != nil {
+				use(fact.Name)
+			}
+		}
+	}
+	if ambiguous {
+		return nil
+	}
+	return found
+}
+
+// isOpen reports whether fun refers to database/sql.Open, or to one of the
+// functions of sqlx opening a database with a driver name.
+func isOpen(fun ast.Expr, info *types.Info) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return false
+	}
+	switch fn.Pkg().Path() {
+	case "database/sql":
+		return fn.Name() == "Open"
+	case sqlxPath:
+		return fn.Name() == "Open" || fn.Name() == "MustOpen" || fn.Name() == "Connect" || fn.Name() == "MustConnect"
+	}
+	return false
+}
+
+// dialectDirectives are the dialects selected with //sqlargs:dialect comments
+// in the files of a package. A comment before the package clause selects the
+// dialect of its file, and one at the end of a call, or on the line above it,
+// the dialect of the call.
+type dialectDirectives struct {
+	files map[*token.File]*dialect
+	calls []span
+}
+
+// readDialectDirectives returns the dialect directives of the files of pass,
+// and reports the ones naming an unknown dialect.
+func readDialectDirectives(pass *analysis.Pass) *dialectDirectives {
+	dd := &dialectDirectives{files: make(map[*token.File]*dialect)}
+	for _, file := range pass.Files {
+		lines := make(map[int]string)
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				rest, ok := cutDirective(strings.TrimPrefix(c.Text, "//"), "sqlargs:dialect")
+				if !ok || rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+					continue
+				}
+				// The name can be followed by a reason.
+				var name string
+				if fields := strings.Fields(rest); len(fields) > 0 {
+					name = fields[0]
+				}
+				d, ok := dialects[name]
+				if !ok {
+					reportf(pass, catDirective, c.Slash, "Unknown dialect %q in //sqlargs:dialect: must be one of %s", name, dialectNames())
+					continue
+				}
+				if c.Slash < file.Package {
+					dd.files[pass.Fset.File(file.Package)] = d
+					continue
+				}
+				lines[pass.Fset.Position(c.Slash).Line] = name
+			}
+		}
+		dd.calls = append(dd.calls, directiveSpans(pass.Fset, file, lines)...)
+	}
+	return dd
+}
+
+// dialect returns the dialect selected for call, which is d if there is no
+// directive for it. The directive of the innermost call containing it wins
+// over the one of its file.
+func (dd *dialectDirectives) dialect(fset *token.FileSet, call *ast.CallExpr, d *dialect) *dialect {
+	var inner *span
+	for i, s := range dd.calls {
+		if s.pos <= call.Pos() && call.Pos() < s.end && (inner == nil || s.pos > inner.pos) {
+			inner = &dd.calls[i]
+		}
+	}
+	if inner != nil {
+		return dialects[inner.value]
+	}
+	if fd, ok := dd.files[fset.File(call.Pos())]; ok {
+		return fd
+	}
+	return d
+}
+
+// dialectFlag is a flag.Value selecting a dialect by name.
+type dialectFlag struct {
+	d **dialect
+}
+
+func (f dialectFlag) String() string {
+	if f.d == nil || *f.d == nil {
+		return ""
+	}
+	return (*f.d).name
+}
+
+func (f dialectFlag) Set(name string) error {
+	if name == "" {
+		*f.d = permissive
+		return nil
+	}
+	d, ok := dialects[name]
+	if !ok {
+		return fmt.Errorf("unknown dialect %q, must be one of %s", name, dialectNames())
+	}
+	*f.d = d
+	return nil
+}
+
+func dialectNames() string {
+	var names []string
+	for name := range dialects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Dialect is an SQL dialect. Dialects other than the built-in ones can be
+// added with RegisterDialect.
+type Dialect interface {
+	// Name is the name of the dialect, as for -dialect.
+	Name() string
+	// Rules are the placeholders, comments and quoting rules of the dialect.
+	Rules() DialectRules
+}
+
+// DialectRules are the rules of a dialect the queries are analyzed with.
+type DialectRules struct {
+	// DollarParams enables $N placeholders.
+	DollarParams bool
+	// QuestionParams enables ? placeholders.
+	QuestionParams bool
+	// ColonParams enables :name and :N placeholders.
+	ColonParams bool
+	// ColonPaths makes a : right after an identifier or a ] the path into a
+	// semi-structured value, like v:name, instead of a placeholder.
+	ColonPaths bool
+	// AtParams enables @name and @pN placeholders.
+	AtParams bool
+	// BraceParams enables the {name:Type} server-side parameters of
+	// ClickHouse.
+	BraceParams bool
+	// HashComments makes # start a line comment, in addition to --.
+	HashComments bool
+	// BackslashEscapes makes a backslash escape the next character in string
+	// literals.
+	BackslashEscapes bool
+	// DoubleQuoteStrings makes "" quote string literals instead of
+	// identifiers.
+	DoubleQuoteStrings bool
+	// MultiStatements allows binding args to queries with more than one
+	// statement.
+	MultiStatements bool
+	// MaxParams is the no. of bind parameters above which the database
+	// rejects a query, or 0 if it is not known.
+	MaxParams int
+	// PostgresGrammar makes queries be validated with the Postgres parser,
+	// for dialects which are a superset of it, like CockroachDB.
+	PostgresGrammar bool
+	// ReservedWords are the reserved keywords, in upper case, which are
+	// commonly mistaken for identifiers.
+	ReservedWords []string
+	// ForeignFuncs maps the functions of other dialects which do not exist in
+	// this one, in upper case, to their replacement.
+	ForeignFuncs map[string]string
+	// DriverImports are the import paths of the drivers of the dialect, and
+	// DriverNames the names they are registered with in database/sql, to
+	// detect it.
+	DriverImports []string
+	DriverNames   []string
+}
+
+func (d *dialect) Name() string { return d.name }
+
+func (d *dialect) Rules() DialectRules {
+	r := DialectRules{
+		DollarParams:       d.dollarParams,
+		QuestionParams:     d.questionParams,
+		ColonParams:        d.colonParams,
+		ColonPaths:         d.colonPaths,
+		AtParams:           d.atParams,
+		BraceParams:        d.braceParams,
+		HashComments:       d.hashComments,
+		BackslashEscapes:   d.backslashEscapes,
+		DoubleQuoteStrings: d.doubleQuoteStrings,
+		MultiStatements:    d.multiStatements,
+		MaxParams:          d.maxParams,
+		PostgresGrammar:    d.pgGrammar,
+		ForeignFuncs:       d.foreignFuncs,
+	}
+	for word := range d.reservedWords {
+		r.ReservedWords = append(r.ReservedWords, word)
+	}
+	sort.Strings(r.ReservedWords)
+	for path, name := range driverImports {
+		if name == d.name {
+			r.DriverImports = append(r.DriverImports, path)
+		}
+	}
+	sort.Strings(r.DriverImports)
+	for driver, name := range driverNames {
+		if name == d.name {
+			r.DriverNames = append(r.DriverNames, driver)
+		}
+	}
+	sort.Strings(r.DriverNames)
+	return r
+}
+
+// toDialect returns the rules of d as a dialect.
+func toDialect(d Dialect) *dialect {
+	if builtin, ok := d.(*dialect); ok {
+		return builtin
+	}
+	r := d.Rules()
+	reserved := make(map[string]bool)
+	for _, word := range r.ReservedWords {
+		reserved[strings.ToUpper(word)] = true
+	}
+	return &dialect{
+		name:               d.Name(),
+		dollarParams:       r.DollarParams,
+		questionParams:     r.QuestionParams,
+		colonParams:        r.ColonParams,
+		colonPaths:         r.ColonPaths,
+		atParams:           r.AtParams,
+		braceParams:        r.BraceParams,
+		hashComments:       r.HashComments,
+		backslashEscapes:   r.BackslashEscapes,
+		doubleQuoteStrings: r.DoubleQuoteStrings,
+		multiStatements:    r.MultiStatements,
+		maxParams:          r.MaxParams,
+		pgGrammar:          r.PostgresGrammar,
+		reservedWords:      reserved,
+		foreignFuncs:       r.ForeignFuncs,
+	}
+}
+
+// The built-in dialects. Permissive is used when the dialect is not known,
+// and counts both $N and ? placeholders.
+var (
+	Postgres   Dialect = dialects["postgres"]
+	MySQL      Dialect = dialects["mysql"]
+	SQLite     Dialect = dialects["sqlite"]
+	SQLServer  Dialect = dialects["sqlserver"]
+	Oracle     Dialect = dialects["oracle"]
+	Snowflake  Dialect = dialects["snowflake"]
+	ClickHouse Dialect = dialects["clickhouse"]
+	Permissive Dialect = permissive
+)
+
+// RegisterDialect adds d to the dialects which can be selected by name, with
+// -dialect, //sqlargs:dialect comments and Options, and which are detected
+// from the imports of their drivers. It must be called before the analyzer
+// runs, like from an init function.
+func RegisterDialect(d Dialect) error {
+	name := d.Name()
+	if name == "" || strings.ContainsAny(name, " \t,") {
+		return fmt.Errorf("invalid dialect name %q", name)
+	}
+	if _, ok := dialects[name]; ok {
+		return fmt.Errorf("dialect %s is already registered", name)
+	}
+	r := d.Rules()
+	dialects[name] = toDialect(d)
+	for _, path := range r.DriverImports {
+		driverImports[path] = name
+	}
+	for _, driver := range r.DriverNames {
+		driverNames[driver] = name
+	}
+	return nil
+}
+
+// LookupDialect returns the dialect of a name, as for -dialect.
+func LookupDialect(name string) (Dialect, bool) {
+	d, ok := dialects[name]
+	if !ok {
+		return nil, false
+	}
+	return d, true
+}
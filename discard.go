@@ -0,0 +1,31 @@
+package sqlargs
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkDiscardedExec reports call, an Exec, when both its result and its
+// error are discarded, given the stack of nodes enclosing it, as a failed
+// write then goes unnoticed.
+func checkDiscardedExec(call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	if len(stack) < 2 {
+		return
+	}
+	switch parent := stack[len(stack)-2].(type) {
+	case *ast.ExprStmt:
+	case *ast.AssignStmt:
+		if len(parent.Rhs) != 1 || parent.Rhs[0] != call {
+			return
+		}
+		for _, lhs := range parent.Lhs {
+			if ident, ok := lhs.(*ast.Ident); !ok || ident.Name != "_" {
+				return
+			}
+		}
+	default:
+		return
+	}
+	reportf(pass, catUncheckedExec, call.Lparen, "Error of Exec is discarded: a failed write goes unnoticed, check it")
+}
@@ -0,0 +1,136 @@
+package sqlargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// driftDir is the directory selected with the -drift flag, where the usage of
+// the schema by each package is written.
+var driftDir string
+
+// packageUsage is the usage of the schema by the queries of a package, as
+// written to the -drift directory.
+type packageUsage struct {
+	Package string
+	// Columns are all the columns of the schema, as table.column.
+	Columns []string
+	// Used are the columns read or written by the queries, as table.column.
+	Used []string
+	// Unknown are the references to tables and columns which are not in the
+	// schema, as file:line:column: message.
+	Unknown []string
+}
+
+// usage records the usage of a schema while a package is analyzed.
+type usage struct {
+	s        *schema
+	used     map[string]bool
+	unknowns []string
+}
+
+func newUsage(s *schema) *usage {
+	return &usage{s: s, used: make(map[string]bool)}
+}
+
+// use records the columns of the tables known to stmt which it uses. Every
+// identifier naming one of them counts, so this errs on the side of
+// considering columns used.
+func (u *usage) use(stmt []lexeme, known []*table) {
+	for _, l := range stmt {
+		if l.kind != lexWord && l.kind != lexQuotedIdent {
+			continue
+		}
+		for _, t := range known {
+			if c := t.columns[normalizeIdent(l)]; c != nil {
+				u.used[t.name+"."+c.name] = true
+			}
+		}
+	}
+}
+
+// unknown records the report msg of an unknown table or column at pos.
+func (u *usage) unknown(pos token.Position, msg string) {
+	u.unknowns = append(u.unknowns, fmt.Sprintf("%s: %s", pos, msg))
+}
+
+// write writes the usage of package path to dir.
+func (u *usage) write(dir, path string) error {
+	out := packageUsage{Package: path, Unknown: u.unknowns}
+	for _, t := range u.s.tables {
+		for _, c := range t.columns {
+			out.Columns = append(out.Columns, t.name+"."+c.name)
+		}
+	}
+	for column := range u.used {
+		out.Used = append(out.Used, column)
+	}
+	sort.Strings(out.Columns)
+	sort.Strings(out.Used)
+	data, err := json.MarshalIndent(out, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("-drift: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, cacheKey(path)+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("-drift: %v", err)
+	}
+	return nil
+}
+
+// DriftReport writes to w the report of the usages written to the -drift
+// directory dir: the references of the queries to tables and columns which are
+// not in the schema, and the columns of the schema which no query uses.
+func DriftReport(dir string, w io.Writer) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no usages in %s: run sqlargs -drift=%s first", dir, dir)
+	}
+	columns := make(map[string]bool)
+	used := make(map[string]bool)
+	var unknown []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var u packageUsage
+		if err := json.Unmarshal(data, &u); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		for _, c := range u.Columns {
+			columns[c] = true
+		}
+		for _, c := range u.Used {
+			used[c] = true
+		}
+		unknown = append(unknown, u.Unknown...)
+	}
+	var unused []string
+	for c := range columns {
+		if !used[c] {
+			unused = append(unused, c)
+		}
+	}
+	sort.Strings(unknown)
+	sort.Strings(unused)
+	fmt.Fprintf(w, "Unknown tables and columns (%d):\n", len(unknown))
+	for _, msg := range unknown {
+		fmt.Fprintf(w, "\t%s\n", msg)
+	}
+	fmt.Fprintf(w, "Unused columns (%d):\n", len(unused))
+	for _, c := range unused {
+		fmt.Fprintf(w, "\t%s\n", c)
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkDuplicateArgs reports the variables passed to call for two positional
+// placeholders of query which are used for columns of different names, like
+// id for both $1 and $2 in WHERE id = $1 AND org_id = $2, a common slip when
+// a line is copied. A value meant for both would rather be bound to a single
+// $N placeholder.
+func checkDuplicateArgs(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	columns := placeholderColumns(query, d)
+	// first maps the args to the first index they are passed at for a column.
+	first := make(map[string]int)
+	for i := 1; i < len(call.Args); i++ {
+		column, ok := columns[i]
+		if !ok {
+			continue
+		}
+		key, ok := argKey(call.Args[i], pass.TypesInfo)
+		if !ok {
+			continue
+		}
+		j, seen := first[key]
+		if !seen {
+			first[key] = i
+			continue
+		}
+		if columns[j] != column {
+			reportf(pass, catDuplicateArgs, call.Args[i].Pos(), "%s is passed both as arg %d, for column %s, and as arg %d, for column %s: one of them is likely meant to be another value", key, j, columns[j], i, column)
+		}
+	}
+}
+
+// checkSwappedArgs reports the variables passed to call for two positional
+// placeholders of query whose names are the ones of each other's columns,
+// like email and id in WHERE id = $1 AND email = $2, which are likely passed
+// in the wrong order. The names are compared without case and underscores, so
+// that userID matches user_id, and a field is named by its last selector.
+func checkSwappedArgs(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	columns := placeholderColumns(query, d)
+	// names maps the indices of the args to their normalized name.
+	names := make(map[int]string)
+	for i := 1; i < len(call.Args); i++ {
+		if _, ok := columns[i]; !ok {
+			continue
+		}
+		if _, ok := argKey(call.Args[i], pass.TypesInfo); !ok {
+			continue
+		}
+		name := types.ExprString(call.Args[i])
+		if sel, ok := call.Args[i].(*ast.SelectorExpr); ok {
+			name = sel.Sel.Name
+		}
+		names[i] = foldName(name)
+	}
+	for i := 1; i < len(call.Args); i++ {
+		for j := i + 1; j < len(call.Args); j++ {
+			ni, nj := names[i], names[j]
+			if ni == "" || nj == "" || ni == nj {
+				continue
+			}
+			if ni == foldName(columns[j]) && nj == foldName(columns[i]) {
+				reportf(pass, catSwappedArgs, call.Args[i].Pos(), "%s is passed for column %s, and %s for column %s: the args look swapped", types.ExprString(call.Args[i]), columns[i], types.ExprString(call.Args[j]), columns[j])
+			}
+		}
+	}
+}
+
+// foldName returns name lower cased and without underscores.
+func foldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// argKey returns the text of arg if it is a variable or a field, like id or
+// u.ID, which is the same for the same value.
+func argKey(arg ast.Expr, info *types.Info) (string, bool) {
+	for e := arg; ; {
+		switch x := e.(type) {
+		case *ast.Ident:
+			if _, ok := info.Uses[x].(*types.Var); !ok {
+				return "", false
+			}
+			return types.ExprString(arg), true
+		case *ast.SelectorExpr:
+			e = x.X
+		default:
+			return "", false
+		}
+	}
+}
+
+// placeholderColumns maps the indices of the args bound to the positional
+// placeholders of query, $N or ?, to the name of the column they are compared
+// to, assigned with SET, or inserted into.
+func placeholderColumns(query string, d *dialect) map[int]string {
+	columns := make(map[int]string)
+	// n is the no. of ? placeholders of the previous statements.
+	n := 0
+	for _, stmt := range statements(query, d) {
+		// indices maps the lexemes of the placeholders to the index of their
+		// arg, and positions lists them in order.
+		indices := make(map[int]int)
+		var positions []int
+		for i, l := range stmt {
+			if l.kind != lexPlaceholder {
+				continue
+			}
+			switch {
+			case l.text == "?":
+				n++
+				indices[i] = n
+				positions = append(positions, i)
+			case strings.HasPrefix(l.text, "$"):
+				if index, err := strconv.Atoi(l.text[1:]); err == nil {
+					indices[i] = index
+					positions = append(positions, i)
+				}
+			}
+		}
+		add := func(index int, column lexeme) {
+			if _, ok := columns[index]; !ok {
+				columns[index] = normalizeIdent(column)
+			}
+		}
+		for _, i := range positions {
+			index := indices[i]
+			switch {
+			case i >= 2 && comparisonOperators[stmt[i-1].text] && isIdentLexeme(stmt[i-2]):
+				// c = $1, or the SET assignment c = $1.
+				add(index, stmt[i-2])
+			case i+2 < len(stmt) && comparisonOperators[stmt[i+1].text] && isIdentLexeme(stmt[i+2]):
+				// $1 = c, or $1 = t.c.
+				j := i + 2
+				if j+2 < len(stmt) && stmt[j+1].text == "." {
+					j += 2
+				}
+				if j+1 == len(stmt) || stmt[j+1].text != "(" && stmt[j+1].text != "." {
+					add(index, stmt[j])
+				}
+			}
+		}
+		names := insertColumnList(stmt)
+		for i := range stmt {
+			if !isValuesKeyword(stmt, i) {
+				continue
+			}
+			for j := i + 1; j < len(stmt) && stmt[j].text == "("; {
+				for k, item := range splitList(stmt, j) {
+					if len(item) != 1 || k >= len(names) {
+						continue
+					}
+					for _, idx := range positions {
+						if stmt[idx].pos == item[0].pos {
+							add(indices[idx], names[k])
+						}
+					}
+				}
+				_, j = listLen(stmt, j)
+				if j >= len(stmt) || stmt[j].text != "," {
+					break
+				}
+				j++
+			}
+			break
+		}
+	}
+	return columns
+}
+
+// isIdentLexeme reports whether l is an identifier, quoted or not.
+func isIdentLexeme(l lexeme) bool {
+	return l.kind == lexWord || l.kind == lexQuotedIdent
+}
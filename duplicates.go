@@ -0,0 +1,142 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// minDuplicateLexemes is the no. of lexemes below which the copies of a query
+// are not reported, as short queries like SELECT 1 or COMMIT are not worth a
+// constant.
+const minDuplicateLexemes = 6
+
+// queryDef is a definition of a constant query: a named constant of the
+// package, or a literal.
+type queryDef struct {
+	pos token.Pos
+	d   *dialect
+}
+
+// queryDefs are the definitions of the constant queries of a package, by
+// their text normalized with normalizeQuery.
+type queryDefs struct {
+	defs map[string][]queryDef
+	// keys are the keys of defs, in the order they were added.
+	keys []string
+}
+
+func newQueryDefs() *queryDefs {
+	return &queryDefs{defs: make(map[string][]queryDef)}
+}
+
+// add records the definition of query, the value of expr, in dialect d. The
+// constants of other packages are left to them.
+func (q *queryDefs) add(query string, d *dialect, expr ast.Expr, pass *analysis.Pass) {
+	pos := expr.Pos()
+	if c := queryConst(expr, pass.TypesInfo); c != nil {
+		if c.Pkg() != pass.Pkg {
+			return
+		}
+		pos = c.Pos()
+	}
+	key, ok := normalizeQuery(query, d)
+	if !ok {
+		return
+	}
+	for _, def := range q.defs[key] {
+		if def.pos == pos {
+			return
+		}
+	}
+	if len(q.defs[key]) == 0 {
+		q.keys = append(q.keys, key)
+	}
+	q.defs[key] = append(q.defs[key], queryDef{pos, d})
+}
+
+// check reports the queries which are defined more than once in the package,
+// or which are also run by a package it depends on, as the copies drift apart
+// when only some of them are changed.
+func (q *queryDefs) check(pass *analysis.Pass) {
+	// deps are the queries of the dependencies, by dialect and normalized
+	// text.
+	deps := make(map[*dialect]map[string]dependencyQuery)
+	for _, key := range q.keys {
+		defs := q.defs[key]
+		for _, def := range defs[1:] {
+			pass.Report(analysis.Diagnostic{
+				Pos:      def.pos,
+				Category: catDuplicateQueries,
+				Message:  "Query is defined more than once: define it once, as a constant, so that the copies do not drift apart",
+				Related:  []analysis.RelatedInformation{{Pos: defs[0].pos, Message: "Query defined here"}},
+			})
+		}
+		if len(defs) > 1 {
+			continue
+		}
+		d := defs[0].d
+		if deps[d] == nil {
+			deps[d] = dependencyQueries(d, pass)
+		}
+		if dep, ok := deps[d][key]; ok {
+			diag := analysis.Diagnostic{
+				Pos:      defs[0].pos,
+				Category: catDuplicateQueries,
+				Message:  fmt.Sprintf("Query is also defined in %s: define it once, as a constant, so that the copies do not drift apart", dep.pkg),
+			}
+			if pos := factPos(pass, dep.pos); pos.IsValid() {
+				diag.Related = []analysis.RelatedInformation{{Pos: pos, Message: "Query defined here"}}
+			}
+			pass.Report(diag)
+		}
+	}
+}
+
+// dependencyQuery is a query of a package which another one depends on.
+type dependencyQuery struct {
+	// pkg is the path of the package.
+	pkg string
+	// pos is the position of the query, as in Query.
+	pos string
+}
+
+// dependencyQueries returns the queries of the packages the one of pass
+// depends on, by their text normalized in dialect d.
+func dependencyQueries(d *dialect, pass *analysis.Pass) map[string]dependencyQuery {
+	queries := make(map[string]dependencyQuery)
+	for _, f := range pass.AllPackageFacts() {
+		deps, ok := f.Fact.(*Queries)
+		if !ok || deps.Package == pass.Pkg.Path() {
+			continue
+		}
+		for _, dep := range deps.Queries {
+			if key, ok := normalizeQuery(dep.Text, d); ok {
+				if _, seen := queries[key]; !seen {
+					queries[key] = dependencyQuery{deps.Package, dep.Pos}
+				}
+			}
+		}
+	}
+	return queries
+}
+
+// normalizeQuery returns the text of query without its comments, and with its
+// lexemes separated by single spaces. It returns false for the queries which
+// are too short to be reported as copies.
+func normalizeQuery(query string, d *dialect) (string, bool) {
+	var texts []string
+	for _, stmt := range statements(query, d) {
+		for _, l := range stmt {
+			texts = append(texts, l.text)
+		}
+		texts = append(texts, ";")
+	}
+	if len(texts) < minDuplicateLexemes {
+		return "", false
+	}
+	return strings.Join(texts, " "), true
+}
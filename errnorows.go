@@ -0,0 +1,97 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkErrNoRows reports the error of db.QueryRow(...).Scan(...), where call
+// is the QueryRow, when it is only checked with err != nil and handled in
+// place, like with an HTTP 500, while the function never refers to
+// sql.ErrNoRows. A missing row is then handled as a failure of the database.
+// Errors which are returned are left to the callers, which may check for
+// sql.ErrNoRows.
+func checkErrNoRows(call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	body := enclosingBody(stack)
+	if body == nil || len(stack) < 4 {
+		return
+	}
+	scans := scanCalls(call, "Scan", stack, body, pass.TypesInfo)
+	if len(scans) != 1 || scans[0] != stack[len(stack)-3] {
+		return
+	}
+	assign, ok := stack[len(stack)-4].(*ast.AssignStmt)
+	if !ok || len(assign.Lhs) != 1 || len(assign.Rhs) != 1 || assign.Rhs[0] != scans[0] {
+		return
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return
+	}
+	errObj := pass.TypesInfo.ObjectOf(ident)
+	if errObj == nil || refersToErrNoRows(body, pass.TypesInfo) {
+		return
+	}
+	end := assignedUntil(errObj, assign, body, pass.TypesInfo)
+	var check *ast.IfStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		if check != nil {
+			return false
+		}
+		if ifStmt, ok := n.(*ast.IfStmt); ok && ifStmt.Cond.Pos() > assign.End() && ifStmt.Cond.Pos() < end && isErrNotNil(ifStmt.Cond, errObj, pass.TypesInfo) {
+			check = ifStmt
+			return false
+		}
+		return true
+	})
+	if check == nil {
+		return
+	}
+	returned := false
+	ast.Inspect(check.Body, func(n ast.Node) bool {
+		if ret, ok := n.(*ast.ReturnStmt); ok {
+			for _, r := range ret.Results {
+				returned = returned || mentions(r, errObj, pass.TypesInfo)
+			}
+		}
+		_, isFunc := n.(*ast.FuncLit)
+		return !returned && !isFunc
+	})
+	if !returned {
+		reportf(pass, catErrNoRows, check.Pos(), "Error of QueryRow is handled as a failure: check errors.Is(%s, sql.ErrNoRows) first, which is returned when no row matches", errObj.Name())
+	}
+}
+
+// isErrNotNil reports whether cond is err != nil, or nil != err.
+func isErrNotNil(cond ast.Expr, err types.Object, info *types.Info) bool {
+	bin, ok := cond.(*ast.BinaryExpr)
+	if !ok || bin.Op != token.NEQ {
+		return false
+	}
+	isErr := func(e ast.Expr) bool {
+		ident, ok := e.(*ast.Ident)
+		return ok && info.ObjectOf(ident) == err
+	}
+	isNil := func(e ast.Expr) bool {
+		ident, ok := e.(*ast.Ident)
+		return ok && ident.Name == "nil"
+	}
+	return isErr(bin.X) && isNil(bin.Y) || isNil(bin.X) && isErr(bin.Y)
+}
+
+// refersToErrNoRows reports whether body refers to sql.ErrNoRows.
+func refersToErrNoRows(body *ast.BlockStmt, info *types.Info) bool {
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "ErrNoRows" {
+			if v, ok := info.Uses[sel.Sel].(*types.Var); ok && v.Pkg() != nil && v.Pkg().Path() == "database/sql" {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
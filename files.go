@@ -0,0 +1,96 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// skippedFiles returns the files of pass whose calls are not analyzed, as
+// configured in cfg.
+func (cfg *config) skippedFiles(pass *analysis.Pass) map[*ast.File]bool {
+	skipped := make(map[*ast.File]bool)
+	for _, file := range pass.Files {
+		// The findings in generated files cannot be fixed by hand.
+		if cfg.skipGenerated && ast.IsGenerated(file) {
+			skipped[file] = true
+		}
+		name := pass.Fset.File(file.Pos()).Name()
+		if cfg.skipTests && strings.HasSuffix(name, "_test.go") {
+			skipped[file] = true
+		}
+		if len(cfg.exclude) > 0 && excluded(cfg.exclude, name) {
+			skipped[file] = true
+		}
+	}
+	return skipped
+}
+
+// excluded reports whether the file name matches one of the globs. A glob
+// matches the end of the path, at a directory boundary, so that it can be
+// written relative to any parent directory.
+func excluded(globs []string, name string) bool {
+	segments := strings.Split(filepath.ToSlash(name), "/")
+	for _, glob := range globs {
+		pattern := strings.Split(glob, "/")
+		for i := range segments {
+			if matchSegments(pattern, segments[i:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments reports whether the segments of a path match the ones of a
+// glob, where ** matches any no. of segments and the others are matched with
+// path.Match.
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
+
+// globsFlag is a flag.Value adding comma separated globs to a list.
+type globsFlag struct {
+	globs *[]string
+}
+
+func (f globsFlag) String() string {
+	if f.globs == nil {
+		return ""
+	}
+	return strings.Join(*f.globs, ",")
+}
+
+func (f globsFlag) Set(list string) error {
+	for _, glob := range strings.Split(list, ",") {
+		if glob = strings.TrimSpace(glob); glob == "" {
+			continue
+		}
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("invalid glob %q: %v", glob, err)
+		}
+		*f.globs = append(*f.globs, glob)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package sqlargs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// githubCommands are the workflow commands of the severities.
+var githubCommands = map[string]string{sevError: "error", sevWarning: "warning", sevInfo: "notice"}
+
+// GitHubAnnotations converts the -json output of the analyzers, read from r,
+// to GitHub Actions workflow commands written to w, like
+// ::error file=f.go,line=1,col=2::message, so that the findings appear inline
+// on pull requests. Paths are written relative to the current directory,
+// which is the root of the repository in a workflow.
+func GitHubAnnotations(r io.Reader, w io.Writer) error {
+	diagnostics, err := readJSONDiagnostics(r)
+	if err != nil {
+		return err
+	}
+	cwd, _ := os.Getwd()
+	for _, d := range diagnostics {
+		severity, message := d.severity()
+		pos := d.position()
+		props := fmt.Sprintf("file=%s,line=%d", escapeProperty(relPath(cwd, pos.file)), pos.line)
+		if pos.col > 0 {
+			props += fmt.Sprintf(",col=%d", pos.col)
+		}
+		if end, ok := d.end(); ok {
+			props += fmt.Sprintf(",endLine=%d,endColumn=%d", end.line, end.col)
+		}
+		props += ",title=" + escapeProperty("sqlargs "+d.rule())
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommands[severity], props, escapeData(message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeData escapes the message of a workflow command.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property of a workflow command.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
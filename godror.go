@@ -0,0 +1,38 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// godrorPath is the import path of godror, the Oracle driver.
+const godrorPath = "github.com/godror/godror"
+
+// withoutDriverOptions returns call without the args of type godror.Option,
+// like godror.FetchArraySize(100) or godror.PlSQLArrays, which change how the
+// statement is run, and the *mssql.ReturnStatus of go-mssqldb, which receive
+// the status returned by a procedure. These are not bound to parameters.
+func withoutDriverOptions(call *ast.CallExpr, info *types.Info) *ast.CallExpr {
+	if call.Ellipsis.IsValid() {
+		return call
+	}
+	var args []ast.Expr
+	for i, arg := range call.Args {
+		if t := info.TypeOf(arg); i > 0 && (isGodrorOption(t) || isReturnStatus(t)) {
+			continue
+		}
+		args = append(args, arg)
+	}
+	if len(args) == len(call.Args) {
+		return call
+	}
+	stripped := *call
+	stripped.Args = args
+	return &stripped
+}
+
+// isGodrorOption reports whether t is godror.Option.
+func isGodrorOption(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == godrorPath && n.Obj().Name() == "Option"
+}
@@ -0,0 +1,133 @@
+// Package golangci registers sqlargs as a golangci-lint module plugin. Add it
+// to the .custom-gcl.yml of golangci-lint custom:
+//
+//	plugins:
+//	  - module: github.com/agnivade/sqlargs
+//	    import: github.com/agnivade/sqlargs/golangci
+//
+// and enable it in .golangci.yml, with the settings of Settings:
+//
+//	linters-settings:
+//	  custom:
+//	    sqlargs:
+//	      type: module
+//	      settings:
+//	        dialect: postgres
+//	        disabled-checks: [selectstar]
+package golangci
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/agnivade/sqlargs"
+	"github.com/golangci/plugin-module-register/register"
+	"golang.org/x/tools/go/analysis"
+)
+
+func init() {
+	register.Plugin("sqlargs", func(settings any) (register.LinterPlugin, error) {
+		analyzers, err := New(settings)
+		if err != nil {
+			return nil, err
+		}
+		return plugin(analyzers), nil
+	})
+}
+
+// Settings are the settings of the plugin in .golangci.yml, which are the
+// Options of sqlargs.NewAnalyzer.
+type Settings struct {
+	Dialect             string   `json:"dialect"`
+	ExtraFuncs          []string `json:"extra-funcs"`
+	DisabledChecks      []string `json:"disabled-checks"`
+	OnlyChecks          []string `json:"only-checks"`
+	Sanitizers          []string `json:"sanitizers"`
+	Schema              string   `json:"schema"`
+	Parser              string   `json:"parser"`
+	SkipTypeErrors      bool     `json:"skip-type-errors"`
+	SkipGenerated       bool     `json:"skip-generated"`
+	SkipTests           bool     `json:"skip-tests"`
+	Exclude             []string `json:"exclude"`
+	Imports             []string `json:"imports"`
+	Strict              bool     `json:"strict"`
+	RequireWhere        bool     `json:"require-where"`
+	GroupBy             bool     `json:"group-by"`
+	SelectStar          bool     `json:"select-star"`
+	InsertColumns       bool     `json:"insert-columns"`
+	RequireConstQueries bool     `json:"require-const-queries"`
+	LoopQueries         bool     `json:"loop-queries"`
+	ErrNoRows           bool     `json:"err-no-rows"`
+	UncheckedExec       bool     `json:"unchecked-exec"`
+	ContextMethods      bool     `json:"context-methods"`
+	DDL                 bool     `json:"ddl"`
+	DuplicateQueries    bool     `json:"duplicate-queries"`
+	UnusedQueries       bool     `json:"unused-queries"`
+	DuplicateArgs       bool     `json:"duplicate-args"`
+	SQLConstants        bool     `json:"sql-constants"`
+	SwappedArgs         bool     `json:"swapped-args"`
+}
+
+// New returns the analyzers of the plugin, configured with settings, as
+// decoded from .golangci.yml. Unknown settings are an error, so that typos do
+// not go unnoticed.
+func New(settings any) ([]*analysis.Analyzer, error) {
+	var s Settings
+	if settings != nil {
+		data, err := json.Marshal(settings)
+		if err != nil {
+			return nil, fmt.Errorf("sqlargs settings: %v", err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&s); err != nil {
+			return nil, fmt.Errorf("sqlargs settings: %v", err)
+		}
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{
+		Dialect:             s.Dialect,
+		ExtraFuncs:          s.ExtraFuncs,
+		DisabledChecks:      s.DisabledChecks,
+		OnlyChecks:          s.OnlyChecks,
+		Sanitizers:          s.Sanitizers,
+		Schema:              s.Schema,
+		Parser:              s.Parser,
+		SkipTypeErrors:      s.SkipTypeErrors,
+		SkipGenerated:       s.SkipGenerated,
+		SkipTests:           s.SkipTests,
+		Exclude:             s.Exclude,
+		Imports:             s.Imports,
+		Strict:              s.Strict,
+		RequireWhere:        s.RequireWhere,
+		GroupBy:             s.GroupBy,
+		SelectStar:          s.SelectStar,
+		InsertColumns:       s.InsertColumns,
+		RequireConstQueries: s.RequireConstQueries,
+		LoopQueries:         s.LoopQueries,
+		ErrNoRows:           s.ErrNoRows,
+		UncheckedExec:       s.UncheckedExec,
+		ContextMethods:      s.ContextMethods,
+		DDL:                 s.DDL,
+		DuplicateQueries:    s.DuplicateQueries,
+		UnusedQueries:       s.UnusedQueries,
+		DuplicateArgs:       s.DuplicateArgs,
+		SQLConstants:        s.SQLConstants,
+		SwappedArgs:         s.SwappedArgs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sqlargs settings: %v", err)
+	}
+	return []*analysis.Analyzer{a}, nil
+}
+
+// plugin is the register.LinterPlugin of the analyzers.
+type plugin []*analysis.Analyzer
+
+func (p plugin) BuildAnalyzers() ([]*analysis.Analyzer, error) {
+	return p, nil
+}
+
+func (plugin) GetLoadMode() string {
+	return register.LoadModeTypesInfo
+}
@@ -0,0 +1,82 @@
+package sqlargs
+
+import (
+	"go/types"
+	"strings"
+)
+
+// defaultImports are the packages running queries. A package is analyzed if
+// it imports one of them, or one of their subpackages, even transitively.
+var defaultImports = []string{
+	"database/sql",
+	bigqueryPath,
+	sqlxPath,
+	"github.com/jackc/pgx",
+}
+
+// importsSQL reports whether pkg imports a package running queries: one of
+// defaultImports, of the imports of cfg, or a package of the funcs of cfg.
+// Wrappers are imported transitively, so the imports of the imports are
+// followed.
+func (cfg *config) importsSQL(pkg *types.Package) bool {
+	paths := append(append([]string(nil), defaultImports...), cfg.imports...)
+	for name := range cfg.funcs {
+		paths = append(paths, funcPackage(name))
+	}
+	seen := make(map[*types.Package]bool)
+	var visit func(pkg *types.Package) bool
+	visit = func(pkg *types.Package) bool {
+		for _, imp := range pkg.Imports() {
+			if seen[imp] {
+				continue
+			}
+			seen[imp] = true
+			if matchesImport(imp.Path(), paths) || visit(imp) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(pkg)
+}
+
+// matchesImport reports whether path is one of paths, or a subpackage of one.
+func matchesImport(path string, paths []string) bool {
+	for _, p := range paths {
+		if path == p || strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// funcPackage returns the package path of a func written as pkgpath.Func or
+// pkgpath.Type.Method.
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// listFlag is a flag.Value adding comma separated values to a list.
+type listFlag struct {
+	list *[]string
+}
+
+func (f listFlag) String() string {
+	if f.list == nil {
+		return ""
+	}
+	return strings.Join(*f.list, ",")
+}
+
+func (f listFlag) Set(list string) error {
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			*f.list = append(*f.list, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// defaultSanitizers are the functions, as pkgpath.Func or pkgpath.Type.Method,
+// whose results are safe to interpolate into queries. The -sanitizers flag and
+// Options.Sanitizers add to them.
+var defaultSanitizers = map[string]bool{
+	"github.com/lib/pq.QuoteIdentifier":                  true,
+	"github.com/lib/pq.QuoteLiteral":                     true,
+	"github.com/jackc/pgx.Identifier.Sanitize":           true,
+	"github.com/jackc/pgx/v4.Identifier.Sanitize":        true,
+	"github.com/jackc/pgx/v5.Identifier.Sanitize":        true,
+	"github.com/jackc/pgx/v5/pgconn.Identifier.Sanitize": true,
+}
+
+// sanitizersFlag is a flag.Value adding comma separated functions to a set of
+// sanitizers.
+type sanitizersFlag struct {
+	sanitizers map[string]bool
+}
+
+func (sanitizersFlag) String() string { return "" }
+
+func (f sanitizersFlag) Set(list string) error {
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			f.sanitizers[name] = true
+		}
+	}
+	return nil
+}
+
+// funcName returns the name of f as pkgpath.Func, or pkgpath.Type.Method for
+// methods.
+func funcName(f *types.Func) string {
+	if f.Pkg() == nil {
+		return f.Name()
+	}
+	if recv := f.Type().(*types.Signature).Recv(); recv != nil {
+		typ := recv.Type()
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = ptr.Elem()
+		}
+		if n, ok := typ.(*types.Named); ok {
+			return f.Pkg().Path() + "." + n.Obj().Name() + "." + f.Name()
+		}
+	}
+	return f.Pkg().Path() + "." + f.Name()
+}
+
+// isSanitized reports whether expr is the result of calling one of
+// sanitizers, directly or through a variable initialized with it inside body.
+func isSanitized(expr ast.Expr, sanitizers map[string]bool, body *ast.BlockStmt, pass *analysis.Pass) bool {
+	if id, ok := expr.(*ast.Ident); ok {
+		if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok {
+			if init := varInit(v, body, pass); init != nil {
+				expr = init
+			}
+		}
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	var fun *ast.Ident
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		fun = f
+	case *ast.SelectorExpr:
+		fun = f.Sel
+	default:
+		return false
+	}
+	f, ok := pass.TypesInfo.Uses[fun].(*types.Func)
+	return ok && sanitizers[funcName(f)]
+}
+
+// checkInjection reports the values which are not constants and are
+// interpolated into the query of call, rather than passed as args. The query
+// is either built in place, or a variable initialized inside body. Values
+// returned by sanitizers are safe. For queries built in place with
+// fmt.Sprintf, a fix passing the values as args is suggested when possible.
+func checkInjection(call *ast.CallExpr, d *dialect, sanitizers map[string]bool, body *ast.BlockStmt, pass *analysis.Pass) {
+	expr := call.Args[0]
+	if id, ok := expr.(*ast.Ident); ok {
+		if v, ok := pass.TypesInfo.Uses[id].(*types.Var); ok {
+			if init := varInit(v, body, pass); init != nil {
+				expr = init
+			}
+		}
+	}
+	switch expr := expr.(type) {
+	case *ast.CallExpr:
+		if !isFmtSprintf(expr.Fun, pass.TypesInfo) || len(expr.Args) < 2 {
+			return
+		}
+		var fixes []analysis.SuggestedFix
+		if expr == call.Args[0] {
+			fixes = sprintfFix(call, expr, d, sanitizers, body, pass)
+		}
+		for _, operand := range expr.Args[1:] {
+			if !isConstant(operand, pass.TypesInfo) && !isSanitized(operand, sanitizers, body, pass) {
+				pass.Report(analysis.Diagnostic{
+					Pos:            operand.Pos(),
+					Category:       catInjection,
+					Message:        fmt.Sprintf("Non-constant %s is interpolated into the query with fmt.Sprintf: pass it as an arg to avoid SQL injection", types.ExprString(operand)),
+					SuggestedFixes: fixes,
+				})
+				// The fix passes all the values, so it is only attached once.
+				fixes = nil
+			}
+		}
+	case *ast.BinaryExpr:
+		for _, operand := range concatOperands(expr) {
+			if !isConstant(operand, pass.TypesInfo) && !isSanitized(operand, sanitizers, body, pass) {
+				pass.Report(analysis.Diagnostic{
+					Pos:      operand.Pos(),
+					Category: catInjection,
+					Message:  fmt.Sprintf("Non-constant %s is concatenated into the query: pass it as an arg to avoid SQL injection", types.ExprString(operand)),
+				})
+			}
+		}
+	}
+}
+
+// sprintfFix returns the fix replacing the verbs of the query of call, built
+// with the fmt.Sprintf call sprintf, with placeholders of dialect d, and
+// passing the operands as args. Only '%s', '%v' and %d verbs are replaced, as
+// other verbs may substitute identifiers or SQL. It returns nil if the query
+// cannot be fixed that way.
+func sprintfFix(call, sprintf *ast.CallExpr, d *dialect, sanitizers map[string]bool, body *ast.BlockStmt, pass *analysis.Pass) []analysis.SuggestedFix {
+	lit, ok := sprintf.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING || call.Ellipsis.IsValid() {
+		return nil
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return nil
+	}
+	operands := sprintf.Args[1:]
+	for _, operand := range operands {
+		if isConstant(operand, pass.TypesInfo) || isSanitized(operand, sanitizers, body, pass) {
+			return nil
+		}
+	}
+	params, style := placeholders(format, d)
+	// The values are appended to the args, so positional placeholders they
+	// replace have to come after the existing ones.
+	positional := d.name == "mysql" || d.name == "sqlite" || d.name == "oracle" || d.name == "snowflake" || d.name == "clickhouse" || style == styleQuestion
+	if positional && len(params) > 0 {
+		return nil
+	}
+	n := len(call.Args) - 1
+	var b strings.Builder
+	verbs := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			b.WriteByte(format[i])
+			continue
+		}
+		if i+1 == len(format) {
+			return nil
+		}
+		verb := format[i+1]
+		quoted := i > 0 && format[i-1] == '\'' && i+2 < len(format) && format[i+2] == '\''
+		switch {
+		case quoted && (verb == 's' || verb == 'v' || verb == 'd'):
+			// Drop the opening quote which was already written.
+			str := b.String()
+			b.Reset()
+			b.WriteString(str[:len(str)-1])
+			i += 2
+		case verb == 'd':
+			i++
+		default:
+			return nil
+		}
+		n++
+		verbs++
+		b.WriteString(placeholderFor(d, style, n))
+	}
+	if verbs != len(operands) {
+		return nil
+	}
+	query := b.String()
+	newLit := strconv.Quote(query)
+	if strings.HasPrefix(lit.Value, "`") && !strings.Contains(query, "`") {
+		newLit = "`" + query + "`"
+	}
+	var values strings.Builder
+	for _, operand := range operands {
+		values.WriteString(", " + types.ExprString(operand))
+	}
+	last := call.Args[len(call.Args)-1]
+	return []analysis.SuggestedFix{{
+		Message: "Pass the values as args",
+		TextEdits: []analysis.TextEdit{
+			{Pos: sprintf.Pos(), End: sprintf.End(), NewText: []byte(newLit)},
+			{Pos: last.End(), End: last.End(), NewText: []byte(values.String())},
+		},
+	}}
+}
+
+// placeholderFor returns the nth placeholder in dialect d, for a query whose
+// placeholders are written in style.
+func placeholderFor(d *dialect, style placeholderStyle, n int) string {
+	switch {
+	case d.name == "sqlserver":
+		return fmt.Sprintf("@p%d", n)
+	case d.name == "oracle":
+		return fmt.Sprintf(":%d", n)
+	case d.name == "mysql", d.name == "sqlite", d.name == "snowflake", d.name == "clickhouse", d.dollarParams && style == styleQuestion:
+		return "?"
+	}
+	return fmt.Sprintf("$%d", n)
+}
+
+// concatOperands returns the operands of the string concatenation expr, like
+// a, b and c for a + b + c.
+func concatOperands(expr ast.Expr) []ast.Expr {
+	switch e := expr.(type) {
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return []ast.Expr{e}
+		}
+		return append(concatOperands(e.X), concatOperands(e.Y)...)
+	case *ast.ParenExpr:
+		return concatOperands(e.X)
+	}
+	return []ast.Expr{expr}
+}
+
+// isConstant reports whether expr is a constant.
+func isConstant(expr ast.Expr, info *types.Info) bool {
+	typ, ok := info.Types[expr]
+	return ok && typ.Value != nil
+}
+
+// isFmtSprintf reports whether fun is fmt.Sprintf.
+func isFmtSprintf(fun ast.Expr, info *types.Info) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	f, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && f.Pkg() != nil && f.Pkg().Path() == "fmt" && f.Name() == "Sprintf"
+}
@@ -0,0 +1,217 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkInsertArity reports the INSERT statements of query, including the ones
+// in CTEs, whose rows of VALUES do not have one value per column. This works
+// on the lexemes of the query, so that it does not depend on the dialect
+// having a parser.
+func checkInsertArity(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	for i, l := range lexemes {
+		if l.kind != lexWord || !strings.EqualFold(l.text, "INSERT") {
+			continue
+		}
+		numCols, rows := insertArity(lexemes[i+1:])
+		for row, numValues := range rows {
+			if numValues == numCols {
+				continue
+			}
+			short := "too few values"
+			if numValues > numCols {
+				short = "too few columns"
+			}
+			if row == 0 {
+				reportf(pass, catArity, call.Lparen, "No. of columns (%d) not equal to no. of values (%d): %s", numCols, numValues, short)
+			} else {
+				reportf(pass, catArity, call.Lparen, "No. of columns (%d) not equal to no. of values (%d) in row %d: %s", numCols, numValues, row+1, short)
+			}
+		}
+	}
+}
+
+// checkInsertColumns reports the INSERT statements of query whose column list
+// names a column more than once.
+func checkInsertColumns(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	for i, l := range lexemes {
+		if l.kind != lexWord || !strings.EqualFold(l.text, "INSERT") {
+			continue
+		}
+		start := insertTableEnd(lexemes, i+1)
+		if start == len(lexemes) || lexemes[start].text != "(" {
+			continue
+		}
+		_, end := listLen(lexemes, start)
+		if end <= start || !isColumnList(lexemes[start:end]) {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, c := range lexemes[start+1 : end-1] {
+			if c.kind != lexWord && c.kind != lexQuotedIdent {
+				continue
+			}
+			// Unquoted names are case insensitive.
+			name := strings.ToLower(c.text)
+			if c.kind == lexQuotedIdent {
+				name = c.text[1 : len(c.text)-1]
+			}
+			if seen[name] {
+				reportf(pass, catSemantics, call.Lparen, "Column %s is listed more than once in INSERT", c.text)
+				break
+			}
+			seen[name] = true
+		}
+	}
+}
+
+// checkInsertColumnList reports the INSERT statements of query which have no
+// column list, as they break as soon as a column is added to the table.
+func checkInsertColumnList(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	for i, l := range lexemes {
+		if l.kind != lexWord || !strings.EqualFold(l.text, "INSERT") {
+			continue
+		}
+		end := insertTableEnd(lexemes, i+1)
+		if end == len(lexemes) {
+			continue
+		}
+		switch next := lexemes[end]; {
+		case isValuesKeyword(lexemes, end), strings.EqualFold(next.text, "SELECT"),
+			next.text == "(" && end+1 < len(lexemes) && strings.EqualFold(lexemes[end+1].text, "SELECT"):
+			reportf(pass, catInsertColumns, call.Lparen, "INSERT without a column list: list the columns so that it does not break when the table changes")
+			return
+		}
+	}
+}
+
+// insertArity returns the no. of columns of the INSERT statement whose
+// lexemes follow the INSERT keyword, along with the no. of values in each row
+// of its VALUES. It returns no rows if there is no column list, or no VALUES.
+func insertArity(lexemes []lexeme) (int, []int) {
+	// Skip INTO and the table name up to the column list.
+	i := insertTableEnd(lexemes, 0)
+	if i == len(lexemes) || lexemes[i].text != "(" {
+		return 0, nil
+	}
+	start := i
+	numCols, i := listLen(lexemes, i)
+	if numCols == 0 || !isColumnList(lexemes[start:i]) {
+		return 0, nil
+	}
+	// Skip clauses like SQL Server's OUTPUT up to VALUES.
+	for ; i < len(lexemes); i++ {
+		l := lexemes[i]
+		if l.text == ";" || l.text == "(" || l.kind == lexWord && insertKeywords[strings.ToUpper(l.text)] {
+			break
+		}
+	}
+	if i == len(lexemes) || !isValuesKeyword(lexemes, i) {
+		return 0, nil
+	}
+	var rows []int
+	for i++; i < len(lexemes) && lexemes[i].text == "("; i++ {
+		var numValues int
+		numValues, i = listLen(lexemes, i)
+		rows = append(rows, numValues)
+		if i == len(lexemes) || lexemes[i].text != "," {
+			break
+		}
+	}
+	return numCols, rows
+}
+
+// insertTableEnd returns the index of the first lexeme after the table name of
+// an INSERT whose lexemes start at i, after the INSERT keyword.
+func insertTableEnd(lexemes []lexeme, i int) int {
+	for ; i < len(lexemes); i++ {
+		switch l := lexemes[i]; {
+		case l.kind == lexQuotedIdent, l.text == ".":
+		case l.kind != lexWord, insertKeywords[strings.ToUpper(l.text)]:
+			return i
+		}
+	}
+	return i
+}
+
+// isColumnList reports whether the parenthesized lexemes are a list of column
+// names. Anything else is left for the parser to report.
+func isColumnList(lexemes []lexeme) bool {
+	name := false
+	for _, l := range lexemes[1 : len(lexemes)-1] {
+		isName := l.kind == lexWord || l.kind == lexQuotedIdent
+		if isName && name {
+			return false
+		}
+		name = isName
+	}
+	return true
+}
+
+// insertKeywords are the keywords which end the table name of an INSERT.
+var insertKeywords = map[string]bool{
+	"DEFAULT": true, "SELECT": true, "SET": true, "TABLE": true, "VALUE": true,
+	"VALUES": true, "WITH": true,
+}
+
+// isValuesKeyword reports whether lexemes[i] starts the VALUES of an INSERT.
+// MySQL also accepts VALUE, which in Postgres can only be part of OVERRIDING
+// SYSTEM VALUE.
+func isValuesKeyword(lexemes []lexeme, i int) bool {
+	switch strings.ToUpper(lexemes[i].text) {
+	case "VALUES":
+		return true
+	case "VALUE":
+		return i+1 < len(lexemes) && lexemes[i+1].text == "("
+	}
+	return false
+}
+
+// listLen returns the no. of comma separated items of the parenthesized list
+// starting at lexemes[i], along with the index just after it. It returns 0
+// items for a subquery, or if the list is not terminated.
+func listLen(lexemes []lexeme, i int) (int, int) {
+	if i+1 < len(lexemes) && strings.EqualFold(lexemes[i+1].text, "SELECT") {
+		return 0, i
+	}
+	depth, n := 0, 0
+	for ; i < len(lexemes); i++ {
+		switch lexemes[i].text {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth == 0 {
+				if lexemes[i-1].text != "(" {
+					n++
+				}
+				return n, i + 1
+			}
+		case ",":
+			if depth == 1 {
+				n++
+			}
+		}
+	}
+	return 0, i
+}
@@ -0,0 +1,95 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Query is a query of a package which is known statically.
+type Query struct {
+	// Text is the text of the query. Actions of text/template queries are
+	// replaced by identifiers.
+	Text string
+	// Pos is the position of the query in the source, as file:line:column.
+	Pos string
+	// Placeholders is the no. of placeholders in the query.
+	Placeholders int
+	// Kind is the keyword of the statement, like SELECT or INSERT, in upper
+	// case.
+	Kind string
+	// Tables are the normalized names of the tables the query reads or
+	// writes, sorted.
+	Tables []string
+}
+
+// Queries is the fact exported for each package, listing its queries which
+// are known statically.
+type Queries struct {
+	Package string
+	Queries []Query
+}
+
+func (*Queries) AFact() {}
+
+func (q *Queries) String() string {
+	return fmt.Sprintf("%d queries", len(q.Queries))
+}
+
+// Inventory is the result of the analyzer: the queries of a package and of
+// all the packages it depends on, sorted by package path. Packages without
+// queries are left out.
+type Inventory struct {
+	Packages []*Queries
+}
+
+// add records query, run by call in dialect d, in q.
+func (q *Queries) add(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	params, _ := placeholders(query, d)
+	q.Queries = append(q.Queries, Query{
+		Text:         query,
+		Pos:          pass.Fset.Position(call.Args[0].Pos()).String(),
+		Placeholders: len(params),
+		Kind:         statementKeyword(query, d),
+		Tables:       queryTables(query, d),
+	})
+}
+
+// queryTables returns the normalized names of the tables referenced by the
+// statements of query, leaving out its CTEs.
+func queryTables(query string, d *dialect) []string {
+	seen := make(map[string]bool)
+	var tables []string
+	for _, stmt := range statements(query, d) {
+		ctes := cteNames(stmt)
+		for _, ref := range tableRefs(stmt) {
+			name := normalizeIdent(ref.name)
+			if !ctes[name] && !seen[name] {
+				seen[name] = true
+				tables = append(tables, name)
+			}
+		}
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// inventory exports q as the fact of the package of pass, and returns the
+// inventory of it and its dependencies.
+func inventory(q *Queries, pass *analysis.Pass) *Inventory {
+	if q != nil && len(q.Queries) > 0 {
+		pass.ExportPackageFact(q)
+	}
+	inv := &Inventory{}
+	for _, f := range pass.AllPackageFacts() {
+		if deps, ok := f.Fact.(*Queries); ok && len(deps.Queries) > 0 {
+			inv.Packages = append(inv.Packages, deps)
+		}
+	}
+	sort.Slice(inv.Packages, func(i, j int) bool {
+		return inv.Packages[i].Package < inv.Packages[j].Package
+	})
+	return inv
+}
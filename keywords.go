@@ -0,0 +1,143 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// The reserved keywords of each dialect which are commonly used as table or
+// column names. These are far from complete lists.
+var (
+	postgresReserved = keywordSet("ANALYSE", "ANALYZE", "CHECK", "COLUMN", "DEFAULT", "DESC", "DO",
+		"END", "GROUP", "LIMIT", "OFFSET", "ONLY", "ORDER", "TABLE", "USER", "WINDOW")
+	mysqlReserved = keywordSet("CONDITION", "DEFAULT", "DESC", "GROUP", "INDEX", "KEY", "KEYS",
+		"LIMIT", "ORDER", "RANGE", "RANK", "READ", "ROW", "ROWS", "SIGNAL", "TABLE", "USAGE")
+	sqliteReserved    = keywordSet("DEFAULT", "GROUP", "INDEX", "LIMIT", "ORDER", "TABLE")
+	sqlserverReserved = keywordSet("DEFAULT", "DESC", "FILE", "GROUP", "INDEX", "KEY", "ORDER",
+		"PERCENT", "PLAN", "PUBLIC", "TABLE", "USER")
+	oracleReserved = keywordSet("ACCESS", "COMMENT", "DATE", "DEFAULT", "FILE", "GROUP", "LEVEL",
+		"MODE", "NUMBER", "ORDER", "ROWS", "SIZE", "TABLE", "UID", "USER")
+	snowflakeReserved = keywordSet("ACCOUNT", "COLUMN", "CONNECTION", "CURRENT", "DATABASE", "GROUP",
+		"ISSUE", "ORDER", "ORGANIZATION", "ROW", "ROWS", "SAMPLE", "SCHEMA", "START", "TABLE", "VIEW")
+)
+
+// The functions of other dialects which are commonly copied into queries of
+// each dialect, along with their replacement.
+var (
+	postgresForeignFuncs = map[string]string{
+		"CHARINDEX": "strpos", "DATEADD": "an interval", "GETDATE": "now", "GROUP_CONCAT": "string_agg",
+		"IFNULL": "coalesce", "ISNULL": "coalesce", "LEN": "length", "NVL": "coalesce",
+	}
+	mysqlForeignFuncs = map[string]string{
+		"CHARINDEX": "LOCATE", "GETDATE": "NOW", "LEN": "CHAR_LENGTH", "NVL": "IFNULL",
+		"STRING_AGG": "GROUP_CONCAT",
+	}
+	sqliteForeignFuncs = map[string]string{
+		"GETDATE": "datetime", "LEN": "length", "NOW": "datetime", "NVL": "ifnull",
+	}
+	sqlserverForeignFuncs = map[string]string{
+		"GROUP_CONCAT": "STRING_AGG", "IFNULL": "ISNULL", "LENGTH": "LEN", "NOW": "GETDATE",
+		"NVL": "ISNULL",
+	}
+	oracleForeignFuncs = map[string]string{
+		"GETDATE": "SYSDATE", "GROUP_CONCAT": "LISTAGG", "IFNULL": "NVL", "ISNULL": "NVL",
+		"LEN": "LENGTH", "NOW": "SYSDATE",
+	}
+	snowflakeForeignFuncs = map[string]string{
+		"GROUP_CONCAT": "LISTAGG", "ISNULL": "NVL", "STRING_AGG": "LISTAGG",
+	}
+)
+
+func keywordSet(keywords ...string) map[string]bool {
+	set := make(map[string]bool, len(keywords))
+	for _, k := range keywords {
+		set[k] = true
+	}
+	return set
+}
+
+// tableKeywords are the keywords which are followed by a table name.
+var tableKeywords = map[string]bool{
+	"FROM": true, "INTO": true, "JOIN": true, "UPDATE": true,
+}
+
+// checkReservedIdents reports a reserved keyword of dialect d used unquoted as
+// a table name, in the column list of an INSERT, or as the target of a SET
+// assignment. It returns false if anything was reported.
+func checkReservedIdents(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) bool {
+	if len(d.reservedWords) == 0 {
+		return true
+	}
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	// columns is set inside the column list of an INSERT.
+	columns := false
+	for i, l := range lexemes {
+		if l.kind != lexWord {
+			if l.text == ")" {
+				columns = false
+			}
+			continue
+		}
+		if strings.EqualFold(l.text, "INSERT") {
+			columns = false
+			if end := insertTableEnd(lexemes, i+1); end < len(lexemes) && lexemes[end].text == "(" {
+				columns = true
+			}
+		}
+		if !d.reservedWords[strings.ToUpper(l.text)] || i == 0 {
+			continue
+		}
+		prev := lexemes[i-1]
+		next := nextLexeme(lexemes[i+1:])
+		switch {
+		case prev.kind == lexWord && tableKeywords[strings.ToUpper(prev.text)]:
+		case columns && (prev.text == "(" || prev.text == ","):
+		case next.text == "=" && (prev.text == "," || strings.EqualFold(prev.text, "SET")):
+		default:
+			continue
+		}
+		reportf(pass, catSyntax, call.Lparen, "Reserved keyword %s used as an identifier: quote it as %s", l.text, quoteIdent(l.text, d))
+		return false
+	}
+	return true
+}
+
+// checkForeignFuncs reports calls to functions which do not exist in dialect
+// d, but in another one. These usually come from copying a query between
+// projects.
+func checkForeignFuncs(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	if len(d.foreignFuncs) == 0 {
+		return
+	}
+	lexemes := lex(query, d)
+	for i, l := range lexemes {
+		if l.kind != lexWord || i+1 == len(lexemes) || lexemes[i+1].text != "(" {
+			continue
+		}
+		// Qualified names are user defined functions.
+		if i > 0 && lexemes[i-1].text == "." {
+			continue
+		}
+		if fix, ok := d.foreignFuncs[strings.ToUpper(l.text)]; ok {
+			reportf(pass, catSyntax, call.Lparen, "Function %s does not exist in %s: use %s instead", l.text, d.name, fix)
+		}
+	}
+}
+
+// quoteIdent quotes the identifier name for dialect d.
+func quoteIdent(name string, d *dialect) string {
+	switch d.name {
+	case "mysql":
+		return "`" + name + "`"
+	case "sqlserver":
+		return "[" + name + "]"
+	}
+	return `"` + name + `"`
+}
@@ -0,0 +1,61 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// maxQueryLen is the length, in bytes, above which constant queries, like
+// generated fixtures embedded in the code, are only checked lexically, so
+// that they do not hold up the build.
+const maxQueryLen = 1 << 20
+
+// checkLargeQuery checks that the no. of args passed to call matches the
+// placeholders of query, in dialect d, for the queries longer than
+// maxQueryLen, which are neither parsed nor recorded in the inventory.
+func checkLargeQuery(query string, d *dialect, call *ast.CallExpr, args argCount, pass *analysis.Pass) {
+	q, err := ParseQuery(query, d)
+	if err != nil || len(q.Names) > 0 || !args.exact() {
+		return
+	}
+	if err := ValidateArgs(q, args.min); err != nil {
+		reportf(pass, catArgCount, call.Lparen, "%v", err)
+	}
+	checkParamLimit(q.Args+len(q.Names), d, call, args, pass)
+}
+
+// checkParamLimit reports the queries binding more than the max no. of
+// params of dialect d, like generated bulk inserts, which the database
+// rejects once the no. of rows grows. n is the no. of params of the query,
+// and args the no. of args bound to them.
+func checkParamLimit(n int, d *dialect, call *ast.CallExpr, args argCount, pass *analysis.Pass) {
+	if args.min > n {
+		n = args.min
+	}
+	if d.maxParams == 0 || n <= d.maxParams {
+		return
+	}
+	reportf(pass, catArgCount, call.Lparen, "Query binds %d params, more than the %d %s allows: split it into batches", n, d.maxParams, d.name)
+}
+
+// bindCount returns the no. of values bound to params, the placeholders of a
+// query written in style, in dialect d. A $N placeholder, or a named one, can
+// be used more than once.
+func bindCount(params []placeholder, style placeholderStyle, d *dialect) int {
+	switch {
+	case d.sqliteParams:
+		n, _ := sqliteMaxIndex(params)
+		return n
+	case style == styleDollar:
+		return highestIndex(params)
+	case style == styleQuestion:
+		return len(params)
+	}
+	distinct := make(map[string]bool)
+	for _, p := range params {
+		distinct[strings.ToLower(p.text)] = true
+	}
+	return len(distinct)
+}
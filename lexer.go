@@ -0,0 +1,287 @@
+package sqlargs
+
+import (
+	"strings"
+	"sync"
+)
+
+// lexemeKind is the kind of a lexeme in a query.
+type lexemeKind int
+
+const (
+	// lexWord is a keyword or an unquoted identifier.
+	lexWord lexemeKind = iota
+	// lexQuotedIdent is an identifier quoted with "" or ``.
+	lexQuotedIdent
+	// lexString is a string literal quoted with ''.
+	lexString
+	// lexNumber is a numeric literal.
+	lexNumber
+	// lexPlaceholder is a bind parameter like $1 or ?.
+	lexPlaceholder
+	// lexPunct is an operator or a punctuation character.
+	lexPunct
+	// lexComment is a -- line comment or a /* block comment */.
+	lexComment
+)
+
+// lexeme is a single token of a query.
+type lexeme struct {
+	kind lexemeKind
+	text string
+	// pos is the byte offset of the lexeme in the query.
+	pos int
+	// open is set for a string, a quoted identifier or a block comment which
+	// is not terminated.
+	open bool
+}
+
+// lexKey is the key of the lexemes of a query in lexed.
+type lexKey struct {
+	query string
+	d     *dialect
+}
+
+// maxLexed is the no. of queries in lexed above which it is cleared, so that
+// it does not grow with the size of the code analyzed.
+const maxLexed = 1 << 14
+
+var (
+	lexedMu sync.Mutex
+	// lexed caches the lexemes of the queries by dialect, as every check lexes
+	// its query, and packages run the same constant queries many times.
+	lexed = make(map[lexKey][]lexeme)
+)
+
+// lex splits query into lexemes according to the rules of dialect d, skipping
+// whitespace. It never fails; unterminated quotes simply extend to the end of
+// the query, and are marked as open. The lexemes are shared, and must not be
+// modified.
+func lex(query string, d *dialect) []lexeme {
+	// The lexemes of large queries are not worth keeping.
+	if len(query) > maxQueryLen {
+		return lexQuery(query, d)
+	}
+	key := lexKey{query, d}
+	lexedMu.Lock()
+	lexemes, ok := lexed[key]
+	lexedMu.Unlock()
+	if ok {
+		return lexemes
+	}
+	lexemes = lexQuery(query, d)
+	lexedMu.Lock()
+	if len(lexed) >= maxLexed {
+		lexed = make(map[lexKey][]lexeme)
+	}
+	lexed[key] = lexemes
+	lexedMu.Unlock()
+	return lexemes
+}
+
+// lexQuery lexes query in dialect d, for lex.
+func lexQuery(query string, d *dialect) []lexeme {
+	var lexemes []lexeme
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+		kind := lexPunct
+		closed := true
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case c == '-' && strings.HasPrefix(query[i:], "--"), c == '#' && d.hashComments:
+			kind = lexComment
+			if i = strings.IndexByte(query[start:], '\n'); i < 0 {
+				i = len(query)
+			} else {
+				i += start
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			kind = lexComment
+			i, closed = commentEnd(query, i, d.dollarParams)
+		case c == '\'':
+			kind = lexString
+			i, closed = quoteEnd(query, i, '\'', d.backslashEscapes)
+		case (c == 'E' || c == 'e') && d.dollarParams && i+1 < len(query) && query[i+1] == '\'':
+			// A Postgres string constant with C-style escapes.
+			kind = lexString
+			i, closed = quoteEnd(query, i+1, '\'', true)
+		case c == '"' && d.doubleQuoteStrings:
+			kind = lexString
+			i, closed = quoteEnd(query, i, '"', d.backslashEscapes)
+		case c == '"' || c == '`':
+			kind = lexQuotedIdent
+			i, closed = quoteEnd(query, i, c, false)
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			// A Postgres cast, which must not be mistaken for a :name placeholder.
+			i += 2
+		case c == '$' && d.dollarParams && dollarTag(query[i:]) != "":
+			kind = lexString
+			i, closed = dollarQuoteEnd(query, i)
+		case c == '$' && d.dollarParams && i+1 < len(query) && isDigit(query[i+1]):
+			kind = lexPlaceholder
+			i = scan(query, i+1, isDigit)
+		case c == '$' && d.dollarParams && i+2 < len(query) && query[i+1] == '-' && isDigit(query[i+2]):
+			// Not valid, but lexed as a placeholder so that it can be reported.
+			kind = lexPlaceholder
+			i = scan(query, i+2, isDigit)
+		case c == '?' && d.dollarParams && i+1 < len(query) && (query[i+1] == '|' || query[i+1] == '&'):
+			// The Postgres jsonb ?| and ?& operators.
+			i += 2
+		case c == '?' && d.questionParams && i+1 < len(query) && query[i+1] == '?':
+			// sqlx rebinds ?? to a literal ?.
+			i += 2
+		case c == '?' && d.questionParams:
+			kind = lexPlaceholder
+			i++
+			if d.sqliteParams {
+				i = scan(query, i, isDigit)
+			}
+		case c == '$' && d.sqliteParams && i+1 < len(query) && isWordChar(query[i+1]):
+			kind = lexPlaceholder
+			i = scan(query, i+1, isWordChar)
+		case c == ':' && d.colonPaths && i > 0 && (isWordChar(query[i-1]) || query[i-1] == '"' || query[i-1] == ']'):
+			// A Snowflake path, like v:name.
+			i++
+		case c == ':' && d.colonParams && i+1 < len(query) && isWordChar(query[i+1]):
+			kind = lexPlaceholder
+			i = scan(query, i+1, isWordChar)
+		case c == '@' && d.atParams && i+1 < len(query) && query[i+1] == '@':
+			// @@ROWCOUNT and the like are system functions.
+			kind = lexWord
+			i = scan(query, i+2, isWordChar)
+		case c == '@' && d.atParams && i+1 < len(query) && isWordChar(query[i+1]):
+			kind = lexPlaceholder
+			i = scan(query, i+1, isWordChar)
+		case c == '{' && d.braceParams && braceParamEnd(query, i) > 0:
+			kind = lexPlaceholder
+			i = braceParamEnd(query, i)
+		case isDigit(c):
+			kind = lexNumber
+			i = scan(query, i, isNumberChar)
+		case isWordChar(c):
+			kind = lexWord
+			i = scan(query, i, isWordChar)
+		default:
+			i++
+		}
+		lexemes = append(lexemes, lexeme{kind: kind, text: query[start:i], pos: start, open: !closed})
+	}
+	// Appending to the shared lexemes must not change them.
+	return lexemes[:len(lexemes):len(lexemes)]
+}
+
+// quoteEnd returns the offset just after the quote starting at i. A doubled
+// quote character inside the quotes escapes it, and so does a backslash if
+// backslash is true. It returns false if the quote is not terminated.
+func quoteEnd(query string, i int, quote byte, backslash bool) (int, bool) {
+	for i++; i < len(query); i++ {
+		if backslash && query[i] == '\\' {
+			i++
+			continue
+		}
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+		return i + 1, true
+	}
+	return len(query), false
+}
+
+// dollarTag returns the tag of the Postgres dollar quote at the start of s,
+// like $$ or $body$. It returns "" if s does not start with a dollar quote.
+func dollarTag(s string) string {
+	if len(s) < 2 || s[0] != '$' || isDigit(s[1]) {
+		return ""
+	}
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '$':
+			return s[:i+1]
+		case !isWordChar(c):
+			return ""
+		}
+	}
+	return ""
+}
+
+// dollarQuoteEnd returns the offset just after the dollar quoted string
+// starting at i. It returns false if the string is not terminated.
+func dollarQuoteEnd(query string, i int) (int, bool) {
+	tag := dollarTag(query[i:])
+	end := strings.Index(query[i+len(tag):], tag)
+	if end < 0 {
+		return len(query), false
+	}
+	return i + len(tag) + end + len(tag), true
+}
+
+// braceParamEnd returns the offset just after the ClickHouse {name:Type}
+// parameter starting at i, or -1 if there is none. The type can have
+// parameters of its own, like in {ids:Array(UInt64)}.
+func braceParamEnd(query string, i int) int {
+	j := scan(query, i+1, isWordChar)
+	if j == i+1 || j == len(query) || query[j] != ':' {
+		return -1
+	}
+	end := strings.IndexAny(query[j:], "}{\n;'")
+	if end <= 1 || query[j+end] != '}' {
+		return -1
+	}
+	return j + end + 1
+}
+
+// commentEnd returns the offset just after the block comment starting at i.
+// Postgres allows nesting block comments. It returns false if the comment is
+// not terminated.
+func commentEnd(query string, i int, nested bool) (int, bool) {
+	depth := 0
+	for i < len(query) {
+		switch {
+		case strings.HasPrefix(query[i:], "/*"):
+			if depth == 0 || nested {
+				depth++
+			}
+			i += 2
+		case strings.HasPrefix(query[i:], "*/"):
+			i += 2
+			if depth--; depth == 0 {
+				return i, true
+			}
+		default:
+			i++
+		}
+	}
+	return len(query), false
+}
+
+// scan returns the offset of the first character from i which is not accepted
+// by the valid function.
+func scan(query string, i int, valid func(byte) bool) int {
+	for i < len(query) && valid(query[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isNumberChar(c byte) bool {
+	return isDigit(c) || c == '.' || c == 'e' || c == 'E'
+}
+
+func isWordChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
@@ -0,0 +1,92 @@
+package sqlargs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go/ast"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// dsn is the data source name of the database selected with the -dsn flag.
+var dsn string
+
+// dsnDriver is the database/sql driver selected with the -driver flag.
+var dsnDriver string
+
+// prepareTimeout bounds the time the database has to prepare a query.
+const prepareTimeout = 10 * time.Second
+
+// dsnSchemes maps the URL schemes of data source names to the names their
+// drivers register, when they differ.
+var dsnSchemes = map[string]string{
+	"postgresql": "postgres",
+	"file":       "sqlite3",
+	"sqlite":     "sqlite3",
+	"mssql":      "sqlserver",
+}
+
+var (
+	databasesMu sync.Mutex
+	// databases caches the opened databases by driver and data source name,
+	// as the analyzer runs on many packages.
+	databases = make(map[string]*sql.DB)
+)
+
+// openDatabase returns the database the queries are prepared against. The
+// driver is the -driver flag, or else the URL scheme of the data source name.
+func openDatabase() (*sql.DB, error) {
+	driver := dsnDriver
+	if driver == "" {
+		u, err := url.Parse(dsn)
+		if err != nil || u.Scheme == "" {
+			return nil, fmt.Errorf("-dsn: no driver in %q, select one with -driver", dsn)
+		}
+		driver = u.Scheme
+		if name, ok := dsnSchemes[driver]; ok {
+			driver = name
+		}
+	}
+	key := driver + "\x00" + dsn
+	databasesMu.Lock()
+	defer databasesMu.Unlock()
+	if db, ok := databases[key]; ok {
+		return db, nil
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("-dsn: %v", err)
+	}
+	// Fail once here, instead of reporting every query when the database is
+	// unreachable.
+	ctx, cancel := context.WithTimeout(context.Background(), prepareTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("-dsn: %v", err)
+	}
+	databases[key] = db
+	return db, nil
+}
+
+// checkPrepare prepares query on db, without executing it, and reports the
+// error of the database if it rejects the query. Queries with multiple
+// statements are not checked, as most databases cannot prepare them.
+func checkPrepare(query string, d *dialect, db *sql.DB, call *ast.CallExpr, pass *analysis.Pass) {
+	if multipleStatements(query, d) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), prepareTimeout)
+	defer cancel()
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		reportf(pass, catDatabase, call.Lparen, "Query rejected by the database: %s", strings.TrimSpace(err.Error()))
+		return
+	}
+	stmt.Close()
+}
@@ -0,0 +1,45 @@
+package sqlargs
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkLoopQuery reports call, which runs the constant query, when it is in a
+// for or range loop of the function enclosing it, given the stack of nodes
+// enclosing call. Running the same query once per iteration costs a round
+// trip to the database each time, where a single batched query would do.
+func checkLoopQuery(query string, d *dialect, call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	if !inLoop(stack) {
+		return
+	}
+	switch statementKeyword(query, d) {
+	case "INSERT":
+		reportf(pass, catLoopQueries, call.Lparen, "Query is run in a loop: insert the rows of all the iterations at once with a multi-row VALUES, or prepare it before the loop")
+	case "SELECT", "UPDATE", "DELETE":
+		reportf(pass, catLoopQueries, call.Lparen, "Query is run in a loop: run it once for all the iterations, e.g. with IN or = ANY($1), or prepare it before the loop")
+	default:
+		reportf(pass, catLoopQueries, call.Lparen, "Query is run in a loop: batch it, or prepare it before the loop")
+	}
+}
+
+// inLoop reports whether the last node of stack is in the body of a for or
+// range loop of the function enclosing it.
+func inLoop(stack []ast.Node) bool {
+	for i := len(stack) - 2; i >= 0; i-- {
+		switch n := stack[i].(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		case *ast.ForStmt:
+			if stack[i+1] == n.Body {
+				return true
+			}
+		case *ast.RangeStmt:
+			if stack[i+1] == n.Body {
+				return true
+			}
+		}
+	}
+	return false
+}
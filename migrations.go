@@ -0,0 +1,81 @@
+package sqlargs
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// readMigrations returns the DDL of the up migrations in dir, in the order
+// they are applied. It understands the layouts of golang-migrate, whose up
+// migrations are in *.up.sql files, and of goose and atlas, whose migrations
+// are *.sql files. goose files hold both directions, separated by
+// -- +goose Up and -- +goose Down annotations.
+func readMigrations(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".sql") || strings.HasSuffix(name, ".down.sql") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		vi, vj := migrationVersion(names[i]), migrationVersion(names[j])
+		if vi != vj {
+			return vi < vj
+		}
+		return names[i] < names[j]
+	})
+	var ddls []string
+	for _, name := range names {
+		ddl, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		ddls = append(ddls, gooseUp(string(ddl)))
+	}
+	return ddls, nil
+}
+
+// migrationVersion returns the version prefix of the name of a migration
+// file, like 42 for 42_add_users.up.sql, or 0 if there is none.
+func migrationVersion(name string) uint64 {
+	end := 0
+	for end < len(name) && isDigit(name[end]) {
+		end++
+	}
+	v, _ := strconv.ParseUint(name[:end], 10, 64)
+	return v
+}
+
+// gooseUp returns the up section of the goose migration ddl, or ddl itself if
+// it has no goose annotations.
+func gooseUp(ddl string) string {
+	if !strings.Contains(ddl, "+goose") {
+		return ddl
+	}
+	var up strings.Builder
+	inUp := false
+	for _, line := range strings.SplitAfter(ddl, "\n") {
+		if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "--") && strings.Contains(trimmed, "+goose") {
+			switch {
+			case strings.Contains(trimmed, "+goose Up"):
+				inUp = true
+			case strings.Contains(trimmed, "+goose Down"):
+				inUp = false
+			}
+			continue
+		}
+		if inUp {
+			up.WriteString(line)
+		}
+	}
+	return up.String()
+}
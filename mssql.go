@@ -0,0 +1,51 @@
+package sqlargs
+
+import (
+	"go/types"
+	"unicode"
+)
+
+// mssqlPaths are the import paths of go-mssqldb, the SQL Server driver, and
+// of the fork it moved from.
+var mssqlPaths = map[string]bool{
+	"github.com/microsoft/go-mssqldb":  true,
+	"github.com/denisenkom/go-mssqldb": true,
+}
+
+// isMSSQLType reports whether t is a type of go-mssqldb, like mssql.TVP or
+// mssql.DateTime1, which the driver converts itself.
+func isMSSQLType(t types.Type) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && mssqlPaths[n.Obj().Pkg().Path()]
+}
+
+// isReturnStatus reports whether t is *mssql.ReturnStatus, which receives the
+// return status of a stored procedure instead of being bound to a parameter.
+func isReturnStatus(t types.Type) bool {
+	ptr, ok := t.(*types.Pointer)
+	if !ok || !isMSSQLType(ptr.Elem()) {
+		return false
+	}
+	return ptr.Elem().(*types.Named).Obj().Name() == "ReturnStatus"
+}
+
+// isProcName reports whether query is only the name of a stored procedure,
+// like dbo.RenameUser or [dbo].[Rename User], which go-mssqldb runs as a
+// procedure call, binding the args to its parameters.
+func isProcName(query string) bool {
+	if query == "" {
+		return false
+	}
+	bracketed := false
+	for _, r := range query {
+		switch {
+		case bracketed:
+			bracketed = r != ']'
+		case r == '[':
+			bracketed = true
+		case unicode.IsSpace(r) || r == '\'' || r == ';' || r == '(':
+			return false
+		}
+	}
+	return !bracketed
+}
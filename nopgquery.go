@@ -0,0 +1,8 @@
+//go:build !sqlargs_pgquery
+
+package sqlargs
+
+// builtinParser is the default backend. Without pg_query, which is only built
+// with the sqlargs_pgquery tag, the queries are only checked by the lexer, so
+// that the package does not depend on a full SQL parser.
+const builtinParser = lexerOnly
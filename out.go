@@ -0,0 +1,102 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkOutArgs checks the sql.Out args of a SQL Server query against its
+// OUTPUT parameters, like @Name in EXEC dbo.GetName @ID, @Name OUTPUT. A
+// sql.Out bound to a parameter which is not OUTPUT is never set, and the value
+// of an OUTPUT parameter bound to another arg is discarded, so each must be
+// bound to the other. Pointers to sql.Out are not recognized by database/sql,
+// which fails to convert them.
+func checkOutArgs(query string, d *dialect, params []placeholder, call *ast.CallExpr, pass *analysis.Pass) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	// argNames maps the args, by index, to the lower cased name of the
+	// parameter they are bound to, and outs tells which are sql.Out.
+	argNames := make(map[int]string)
+	outs := make(map[string]bool)
+	n := 0
+	for i, arg := range call.Args[1:] {
+		name, ok := namedArg(arg, pass.TypesInfo)
+		value := arg
+		switch {
+		case !ok && isSQLType(pass.TypesInfo.TypeOf(arg), "NamedArg"):
+			// The name is not known.
+			continue
+		case ok:
+			name = strings.ToLower(name)
+			value = arg.(*ast.CallExpr).Args[1]
+		default:
+			n++
+			name = "p" + strconv.Itoa(n)
+		}
+		typ := pass.TypesInfo.TypeOf(value)
+		if ptr, ok := typ.(*types.Pointer); ok && isSQLType(ptr.Elem(), "Out") {
+			reportf(pass, catArgType, value.Pos(), "Arg of type *sql.Out cannot be bound: pass the sql.Out itself")
+			continue
+		}
+		argNames[i] = name
+		outs[name] = isSQLType(typ, "Out")
+	}
+	declared := declaredVars(query, d)
+	// first are the first occurrences of the parameters in the query, and
+	// output tells which are OUTPUT in any of them.
+	first := make(map[string]placeholder)
+	output := make(map[string]bool)
+	offsets := outputParams(query, d)
+	for _, p := range params {
+		name := strings.ToLower(p.name)
+		if declared[name] {
+			continue
+		}
+		if _, ok := first[name]; !ok {
+			first[name] = p
+		}
+		output[name] = output[name] || offsets[p.pos]
+	}
+	for i, arg := range call.Args[1:] {
+		name, ok := argNames[i]
+		if !ok {
+			continue
+		}
+		p, used := first[name]
+		switch {
+		case !used:
+		case outs[name] && !output[name]:
+			reportf(pass, catArgType, arg.Pos(), "sql.Out is bound to %s, which is not an OUTPUT parameter: its Dest is never set, add OUTPUT after it", p.text)
+		case output[name] && !outs[name]:
+			reportQuery(pass, call, catArgType, p.pos, len(p.text), "Parameter %s is OUTPUT but its arg is not a sql.Out: its value is discarded", p.text)
+		}
+	}
+}
+
+// outputParams returns the offsets of the @ parameters of query which are
+// followed by OUTPUT, or its abbreviation OUT.
+func outputParams(query string, d *dialect) map[int]bool {
+	output := make(map[int]bool)
+	lexemes := lex(query, d)
+	for i, l := range lexemes {
+		if l.kind != lexPlaceholder {
+			continue
+		}
+		next := nextLexeme(lexemes[i+1:])
+		if next.kind == lexWord && (strings.EqualFold(next.text, "OUTPUT") || strings.EqualFold(next.text, "OUT")) {
+			output[l.pos] = true
+		}
+	}
+	return output
+}
+
+// isSQLType reports whether t is the type of database/sql of the given name.
+func isSQLType(t types.Type, name string) bool {
+	n, ok := t.(*types.Named)
+	return ok && n.Obj().Pkg() != nil && n.Obj().Pkg().Path() == "database/sql" && n.Obj().Name() == name
+}
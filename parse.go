@@ -0,0 +1,139 @@
+package sqlargs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// QueryError is a problem in a query.
+type QueryError struct {
+	// Offset and Len are the range of the problem in the query, in bytes.
+	// Offset is -1 for problems of the whole query.
+	Offset, Len int
+	Msg         string
+}
+
+func (e *QueryError) Error() string {
+	return e.Msg
+}
+
+// Placeholder is a bind parameter of a query.
+type Placeholder struct {
+	// Text is the placeholder as written in the query, like $1 or :name.
+	Text string
+	// Offset is the offset of the placeholder in the query, in bytes.
+	Offset int
+	// Index is N for a $N, @pN or ?N placeholder, and 0 otherwise.
+	Index int
+	// Name is the name of a named placeholder, without its prefix.
+	Name string
+}
+
+// Placeholders are the bind parameters of a query.
+type Placeholders struct {
+	// List is every placeholder, in the order of the query.
+	List []Placeholder
+	// Args is the no. of positional args the query takes. A $N placeholder
+	// can be used more than once, so it is the highest N for Postgres.
+	Args int
+	// Names are the lower cased names of the placeholders which can also be
+	// bound by name with sql.Named, sorted.
+	Names []string
+}
+
+// ParseQuery returns the placeholders of query, counted like the analyzer
+// does for dialect d. It returns an error for an unterminated string, quoted
+// identifier or comment, unbalanced parentheses, and placeholders which d does
+// not support or which mix styles. A nil d is Permissive.
+func ParseQuery(query string, d Dialect) (Placeholders, error) {
+	rules := permissive
+	if d != nil {
+		rules = toDialect(d)
+	}
+	if err := unbalanced(query, rules); err != nil {
+		return Placeholders{}, err
+	}
+	if rules != permissive {
+		if err := foreignStyle(query, rules); err != nil {
+			return Placeholders{}, err
+		}
+	}
+	params, style := placeholders(query, rules)
+	var q Placeholders
+	for _, p := range params {
+		q.List = append(q.List, Placeholder{Text: p.text, Offset: p.pos, Index: p.index, Name: p.name})
+	}
+	names := make(map[string]bool)
+	switch {
+	case style == styleMixed:
+		for _, p := range params[1:] {
+			if p.style != params[0].style {
+				return Placeholders{}, &QueryError{Offset: p.pos, Len: len(p.text), Msg: fmt.Sprintf("Mixed placeholder styles: %s and %s", params[0].text, p.text)}
+			}
+		}
+	case rules.sqliteParams:
+		maxIndex, ok := sqliteMaxIndex(params)
+		if !ok {
+			return Placeholders{}, &QueryError{Offset: -1, Msg: "Mixed ? and ?NNN placeholders"}
+		}
+		q.Args = maxIndex
+		for _, p := range params {
+			if p.name != "" {
+				names[strings.ToLower(p.name)] = true
+			}
+		}
+	case style == styleDollar:
+		q.Args = highestIndex(params)
+	case style == styleColon:
+		for _, p := range params {
+			names[strings.ToLower(p.name)] = true
+		}
+		q.Args = len(params)
+		if isPLSQL(query) {
+			q.Args = len(names)
+		}
+	case style == styleAt:
+		declared := declaredVars(query, rules)
+		for _, p := range params {
+			name := strings.ToLower(p.name)
+			switch {
+			case declared[name]:
+			case p.index > 0:
+				if p.index > q.Args {
+					q.Args = p.index
+				}
+			default:
+				names[name] = true
+			}
+		}
+	case style == styleBrace:
+		for _, p := range params {
+			names[strings.ToLower(p.name)] = true
+		}
+	default:
+		q.Args = len(params)
+	}
+	for name := range names {
+		q.Names = append(q.Names, name)
+	}
+	sort.Strings(q.Names)
+	return q, nil
+}
+
+// ValidateArgs returns an error if n positional args do not match the
+// placeholders of q, with the message of the analyzer.
+func ValidateArgs(q Placeholders, n int) error {
+	args := argCount{n, n}
+	switch {
+	case n < q.Args:
+		var params []placeholder
+		for _, p := range q.List {
+			params = append(params, placeholder{text: p.Text, pos: p.Offset, index: p.Index, name: p.Name})
+		}
+		return fmt.Errorf("No. of args (%d) is less than no. of params (%d): %s", n, q.Args, unboundDetail(params, q.Args, args))
+	case n > q.Args:
+		return fmt.Errorf("No. of args (%d) is more than no. of params (%d): %s", n, q.Args, surplusDetail(q.Args, n))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package sqlargs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Parser is a backend validating the syntax of queries, beyond the checks of
+// the lexer. The pg_query backend is built with the sqlargs_pgquery tag, and
+// the vitess one with the sqlargs_vitess tag.
+type Parser interface {
+	// Parse returns the syntax error of query, written in dialect d, or nil.
+	// It is only called for valid constant queries whose placeholders are all
+	// of one style, and should return nil for dialects it does not know.
+	Parse(query string, d Dialect) error
+}
+
+// lexerOnly is the name of the backend which does not parse the queries, so
+// they are only checked by the lexer.
+const lexerOnly = "lexer"
+
+// parsers are the backends which can be selected by name.
+var parsers = map[string]Parser{lexerOnly: nil}
+
+// defaultParser is the backend used when none is selected.
+const defaultParser = builtinParser
+
+// builtin adds the built-in backend p as name. It is called to initialize the
+// variables of the backends, so that they are registered before the flags.
+func builtin(name string, p Parser) Parser {
+	parsers[name] = p
+	return p
+}
+
+// RegisterParser adds p to the backends which can be selected with -parser
+// or Options, as name. It must be called before the analyzer runs, like from
+// an init function.
+func RegisterParser(name string, p Parser) error {
+	if name == "" || strings.ContainsAny(name, " \t,") {
+		return fmt.Errorf("invalid parser name %q", name)
+	}
+	if _, ok := parsers[name]; ok {
+		return fmt.Errorf("parser %s is already registered", name)
+	}
+	parsers[name] = p
+	return nil
+}
+
+// parserFlag is a flag.Value selecting a backend by name.
+type parserFlag struct {
+	name *string
+}
+
+func (f parserFlag) String() string {
+	if f.name == nil {
+		return ""
+	}
+	return *f.name
+}
+
+func (f parserFlag) Set(name string) error {
+	if _, ok := parsers[name]; !ok && name != "" {
+		return fmt.Errorf("unknown parser %q, must be one of %s", name, parserNames())
+	}
+	*f.name = name
+	return nil
+}
+
+func parserNames() string {
+	var names []string
+	for name := range parsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// queryParser returns the backend selected in cfg, along with its name.
+func (cfg *config) queryParser() (string, Parser) {
+	name := cfg.parser
+	if name == "" {
+		name = defaultParser
+	}
+	return name, parsers[name]
+}
@@ -0,0 +1,30 @@
+//go:build sqlargs_pgquery
+
+package sqlargs
+
+import (
+	pg_query "github.com/lfittl/pg_query_go"
+)
+
+// builtinParser is the default backend. With the sqlargs_pgquery tag, the
+// queries are parsed with pg_query.
+const builtinParser = "pg_query"
+
+var _ = builtin(builtinParser, pgQuery{})
+
+// pgQuery is the backend parsing the queries with the Postgres parser, for the
+// dialects with its grammar.
+type pgQuery struct{}
+
+func (pgQuery) Parse(query string, d Dialect) error {
+	rules := toDialect(d)
+	if !rules.pgGrammar || unparsedStatements[statementKeyword(query, rules)] {
+		return nil
+	}
+	// The Postgres parser only understands $N placeholders.
+	if _, style := placeholders(query, rules); style != styleDollar && style != styleNone {
+		return nil
+	}
+	_, err := pg_query.Parse(query)
+	return err
+}
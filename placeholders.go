@@ -0,0 +1,837 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// placeholderStyle is the syntax used for the bind parameters of a query.
+type placeholderStyle int
+
+const (
+	// styleNone is used for queries without placeholders.
+	styleNone placeholderStyle = iota
+	// styleDollar is the Postgres style: $1, $2, ...
+	styleDollar
+	// styleQuestion is the MySQL/SQLite style: ?, ?, ...
+	styleQuestion
+	// styleColon is the Oracle style: :name or :1.
+	styleColon
+	// styleAt is the SQL Server style: @p1 or @name.
+	styleAt
+	// styleBrace is the ClickHouse server-side style: {name:Type}.
+	styleBrace
+	// styleMixed is used for queries with placeholders of different styles.
+	styleMixed
+)
+
+// placeholder is a bind parameter in a query.
+type placeholder struct {
+	text string
+	// pos is the byte offset of the placeholder in the query.
+	pos int
+	// index is N for a $N, @pN or ?N placeholder, and 0 otherwise.
+	index int
+	// name is the name of a :name, @name or $name placeholder, without the
+	// prefix.
+	name string
+	// style is the style the placeholder is written in.
+	style placeholderStyle
+}
+
+// placeholders returns all the bind parameters of query in dialect d, along
+// with the style they are written in. The style is styleMixed if more than one
+// style is used, except for SQLite which allows mixing them.
+func placeholders(query string, d *dialect) ([]placeholder, placeholderStyle) {
+	// Queries without any placeholder character, which are common, are not
+	// lexed at all.
+	if !strings.ContainsAny(query, "$?:@") {
+		return nil, styleNone
+	}
+	var params []placeholder
+	style := styleNone
+	lexemes := lex(query, d)
+	if d.dollarParams && d.questionParams {
+		lexemes = jsonbOperators(lexemes)
+	}
+	for _, l := range lexemes {
+		if l.kind != lexPlaceholder {
+			continue
+		}
+		p := placeholder{text: l.text, pos: l.pos}
+		switch l.text[0] {
+		case '?':
+			p.index, _ = strconv.Atoi(l.text[1:])
+			p.style = styleQuestion
+		case ':':
+			p.name = l.text[1:]
+			p.style = styleColon
+		case '@':
+			p.name = l.text[1:]
+			p.index = msPositional(p.name)
+			p.style = styleAt
+		case '{':
+			p.name = l.text[1:strings.IndexByte(l.text, ':')]
+			p.style = styleBrace
+		default:
+			if c := l.text[1]; isDigit(c) || c == '-' {
+				p.index, _ = strconv.Atoi(l.text[1:])
+			} else {
+				// A SQLite $name placeholder.
+				p.name = l.text[1:]
+			}
+			p.style = styleDollar
+		}
+		switch {
+		case style == styleNone:
+			style = p.style
+		case style != p.style && !d.sqliteParams:
+			style = styleMixed
+		}
+		params = append(params, p)
+	}
+	return params, style
+}
+
+// jsonbOperators returns lexemes with the ? placeholders which are Postgres
+// jsonb ? operators turned into punctuation. A ? is an operator if it is
+// followed by a string literal, like in data ? 'key', or if it is between two
+// operands, like in data ? col. The lexemes of lex are shared, so they are
+// copied before being changed.
+func jsonbOperators(lexemes []lexeme) []lexeme {
+	copied := false
+	for i, l := range lexemes {
+		if l.kind != lexPlaceholder || l.text != "?" {
+			continue
+		}
+		prev, next := prevLexeme(lexemes[:i]), nextLexeme(lexemes[i+1:])
+		if next.kind == lexString || endsOperand(prev) && startsOperand(next) {
+			if !copied {
+				lexemes, copied = append([]lexeme(nil), lexemes...), true
+			}
+			lexemes[i].kind = lexPunct
+		}
+	}
+	return lexemes
+}
+
+// valueKeywords are the keywords which can be directly followed by a value.
+var valueKeywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "AS": true, "BETWEEN": true, "BY": true,
+	"CASE": true, "DISTINCT": true, "ELSE": true, "FROM": true, "HAVING": true,
+	"IN": true, "IS": true, "LIKE": true, "ILIKE": true, "LIMIT": true, "NOT": true,
+	"OFFSET": true, "ON": true, "OR": true, "RETURNING": true, "SELECT": true,
+	"SET": true, "SIMILAR": true, "THEN": true, "TO": true, "VALUES": true,
+	"WHEN": true, "WHERE": true,
+}
+
+// endsOperand reports whether l can be the end of an operand.
+func endsOperand(l lexeme) bool {
+	switch l.kind {
+	case lexWord:
+		return !valueKeywords[strings.ToUpper(l.text)]
+	case lexQuotedIdent, lexString, lexNumber:
+		return true
+	case lexPunct:
+		return l.text == ")" || l.text == "]"
+	}
+	return false
+}
+
+// startsOperand reports whether l can be the start of an operand.
+func startsOperand(l lexeme) bool {
+	switch l.kind {
+	case lexWord, lexQuotedIdent, lexString, lexNumber, lexPlaceholder:
+		return true
+	case lexPunct:
+		return l.text == "("
+	}
+	return false
+}
+
+// prevLexeme returns the last lexeme which is not a comment. It returns a
+// comment if there is none.
+func prevLexeme(lexemes []lexeme) lexeme {
+	for i := len(lexemes) - 1; i >= 0; i-- {
+		if lexemes[i].kind != lexComment {
+			return lexemes[i]
+		}
+	}
+	return lexeme{kind: lexComment}
+}
+
+// nextLexeme returns the first lexeme which is not a comment. It returns a
+// comment if there is none.
+func nextLexeme(lexemes []lexeme) lexeme {
+	for _, l := range lexemes {
+		if l.kind != lexComment {
+			return l
+		}
+	}
+	return lexeme{kind: lexComment}
+}
+
+// checkForeignStyle reports $N or ? placeholders in a query written in dialect d
+// which does not support them. These either fail at runtime, or silently bind
+// the wrong values. It returns false if anything was reported.
+func checkForeignStyle(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) bool {
+	if err := foreignStyle(query, d); err != nil {
+		reportQuery(pass, call, catPlaceholderStyle, err.Offset, err.Len, "%s", err.Msg)
+		return false
+	}
+	return true
+}
+
+var (
+	bothStylesMu sync.Mutex
+	// bothStylesOf are the dialects returned by bothStyles, which are built
+	// once, so that the lexemes of their queries are cached too.
+	bothStylesOf = make(map[*dialect]*dialect)
+)
+
+// bothStyles returns dialect d recognizing both $N and ? placeholders, with
+// the quoting rules of d.
+func bothStyles(d *dialect) *dialect {
+	bothStylesMu.Lock()
+	defer bothStylesMu.Unlock()
+	both, ok := bothStylesOf[d]
+	if !ok {
+		copied := *d
+		copied.dollarParams, copied.questionParams = true, true
+		both = &copied
+		bothStylesOf[d] = both
+	}
+	return both
+}
+
+// foreignStyle returns the error of the first $N or ? placeholder in query
+// which dialect d does not support, or nil if there is none.
+func foreignStyle(query string, d *dialect) *QueryError {
+	params, _ := placeholders(query, bothStyles(d))
+	for _, p := range params {
+		var supported bool
+		switch p.style {
+		case styleDollar:
+			supported = d.dollarParams || d.sqliteParams
+		case styleQuestion:
+			supported = d.questionParams
+		default:
+			continue
+		}
+		if !supported {
+			return &QueryError{Offset: p.pos, Len: len(p.text), Msg: fmt.Sprintf("Placeholder %s is not valid for %s queries", p.text, d.name)}
+		}
+	}
+	return nil
+}
+
+// checkMixedStyles reports the first two placeholders of different styles in
+// a query using styleMixed.
+func checkMixedStyles(params []placeholder, call *ast.CallExpr, pass *analysis.Pass) {
+	for _, p := range params[1:] {
+		if p.style != params[0].style {
+			reportQuery(pass, call, catPlaceholderStyle, p.pos, len(p.text), "Mixed placeholder styles: %s and %s", params[0].text, p.text)
+			return
+		}
+	}
+}
+
+// highestIndex returns the highest index of the numbered placeholders in params.
+func highestIndex(params []placeholder) int {
+	highest := 0
+	for _, p := range params {
+		if p.index > highest {
+			highest = p.index
+		}
+	}
+	return highest
+}
+
+// checkIndices reports numbered placeholders with an index below 1, like $0,
+// which usually come from an off-by-one while generating queries.
+func checkIndices(params []placeholder, call *ast.CallExpr, pass *analysis.Pass) {
+	for _, p := range params {
+		numbered := p.style == styleDollar && p.name == "" || p.style == styleQuestion && p.text != "?"
+		if numbered && p.index < 1 {
+			reportQuery(pass, call, catPlaceholderStyle, p.pos, len(p.text), "Invalid placeholder %s: indices start at 1", p.text)
+		}
+	}
+}
+
+// maxMissing is the max no. of missing placeholders listed in a diagnostic.
+const maxMissing = 5
+
+// checkNumbering reports gaps in the numbering of $N placeholders, which
+// usually come from a bad edit of the query.
+func checkNumbering(params []placeholder, call *ast.CallExpr, pass *analysis.Pass) {
+	used := make(map[int]bool)
+	maxIndex := 0
+	var highest placeholder
+	for _, p := range params {
+		if p.style != styleDollar || p.index < 1 {
+			continue
+		}
+		used[p.index] = true
+		if p.index > maxIndex {
+			maxIndex = p.index
+			highest = p
+		}
+	}
+	var missing []string
+	for i := 1; i < maxIndex; i++ {
+		if used[i] {
+			continue
+		}
+		if len(missing) == maxMissing {
+			missing = append(missing, "...")
+			break
+		}
+		missing = append(missing, "$"+strconv.Itoa(i))
+	}
+	if len(missing) > 0 {
+		diag := queryDiagnostic(call, catPlaceholderStyle, highest.pos, len(highest.text), "Gap in placeholder numbering: $%d is used but not %s", maxIndex, strings.Join(missing, ", "))
+		if fix, ok := renumberFix(params, used, call); ok {
+			diag.SuggestedFixes = []analysis.SuggestedFix{fix}
+		}
+		pass.Report(diag)
+	}
+}
+
+// renumberFix returns the fix renumbering the $N placeholders of params, whose
+// indices are used, to $1..$N in the order of their indices. It returns false
+// if the query is not a string literal in call.
+func renumberFix(params []placeholder, used map[int]bool, call *ast.CallExpr) (analysis.SuggestedFix, bool) {
+	var indices []int
+	for index := range used {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+	renumbered := make(map[int]int)
+	for i, index := range indices {
+		renumbered[index] = i + 1
+	}
+	fix := analysis.SuggestedFix{Message: fmt.Sprintf("Renumber the placeholders $1..$%d", len(indices))}
+	for _, p := range params {
+		if p.style != styleDollar || p.index < 1 || renumbered[p.index] == p.index {
+			continue
+		}
+		pos, ok := queryOffsetPos(call.Args[0], p.pos)
+		end, endOK := queryOffsetPos(call.Args[0], p.pos+len(p.text))
+		if !ok || !endOK {
+			return analysis.SuggestedFix{}, false
+		}
+		fix.TextEdits = append(fix.TextEdits, analysis.TextEdit{Pos: pos, End: end, NewText: []byte("$" + strconv.Itoa(renumbered[p.index]))})
+	}
+	return fix, true
+}
+
+// checkPositionalArgs checks that there is exactly one arg for each of the n
+// positional placeholders of a query. If there are too few args, the first
+// placeholder of params without an arg is reported. params can be nil if they
+// are not bound in order.
+func checkPositionalArgs(n int, params []placeholder, call *ast.CallExpr, args argCount, pass *analysis.Pass) {
+	switch {
+	case args.lessThan(n):
+		diag := analysis.Diagnostic{Pos: call.Lparen, Category: catArgCount, Message: fmt.Sprintf("No. of args (%v) is less than no. of params (%d): %s", args, n, unboundDetail(params, n, args))}
+		if p, ok := firstUnbound(params, args.max); ok {
+			diag.Pos, diag.End = queryRange(call, p.pos, len(p.text))
+			if last := lastUnbound(params, args.max); last.pos != p.pos {
+				pos, end := queryRange(call, last.pos, len(last.text))
+				diag.Related = append(diag.Related, analysis.RelatedInformation{Pos: pos, End: end, Message: fmt.Sprintf("Last placeholder without an arg: %s", last.text)})
+			}
+		}
+		// A gap in the numbering is fixed by renumbering the placeholders
+		// instead, and an unspread slice by spreading it.
+		if listedArgs(call, args) && contiguous(params, n) && len(unspreadSlices(call, pass.TypesInfo)) == 0 {
+			padding := strings.Repeat(", /* TODO */ nil", n-args.max)
+			end := call.Args[len(call.Args)-1].End()
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Add %d nil args", n-args.max),
+				TextEdits: []analysis.TextEdit{{Pos: end, End: end, NewText: []byte(padding)}},
+			}}
+		}
+		pass.Report(diag)
+	case args.min > n:
+		diag := analysis.Diagnostic{Pos: call.Lparen, Category: catArgCount, Message: fmt.Sprintf("No. of args (%v) is more than no. of params (%d)", args, n)}
+		if args.exact() {
+			diag.Message += ": " + surplusDetail(n, args.min)
+		}
+		if listedArgs(call, args) {
+			for i, arg := range call.Args[1+n:] {
+				diag.Related = append(diag.Related, analysis.RelatedInformation{Pos: arg.Pos(), End: arg.End(), Message: fmt.Sprintf("Arg %d has no placeholder", n+i+1)})
+			}
+		}
+		// The args of an unspread slice are not known until it is spread.
+		if listedArgs(call, args) && len(unspreadSlices(call, pass.TypesInfo)) == 0 {
+			diag.SuggestedFixes = []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Remove the last %d args", args.min-n),
+				TextEdits: []analysis.TextEdit{{Pos: call.Args[n].End(), End: call.Args[len(call.Args)-1].End()}},
+			}}
+		}
+		pass.Report(diag)
+	}
+}
+
+// unboundDetail describes which of the n placeholders of params have no arg,
+// for a diagnostic.
+func unboundDetail(params []placeholder, n int, args argCount) string {
+	if last := lastUnbound(params, args.max); last.index == n && n > 0 {
+		return fmt.Sprintf("the query references %s but %s", last.text, passedArgs(args))
+	}
+	return fmt.Sprintf("the query has %d placeholders but %s", n, passedArgs(args))
+}
+
+// surplusDetail describes the args without a placeholder when n placeholders
+// are bound to count args, for a diagnostic.
+func surplusDetail(n, count int) string {
+	if count == n+1 {
+		return fmt.Sprintf("arg %d has no placeholder", count)
+	}
+	return fmt.Sprintf("args %d to %d have no placeholder", n+1, count)
+}
+
+// passedArgs describes the no. of args passed, for a diagnostic.
+func passedArgs(args argCount) string {
+	switch {
+	case !args.exact():
+		return fmt.Sprintf("at most %d args are passed", args.max)
+	case args.max == 0:
+		return "no args are passed"
+	case args.max == 1:
+		return "only 1 arg is passed"
+	default:
+		return fmt.Sprintf("only %d args are passed", args.max)
+	}
+}
+
+// listedArgs reports whether the args of call are all listed in it, rather
+// than spread from a slice.
+func listedArgs(call *ast.CallExpr, args argCount) bool {
+	return !call.Ellipsis.IsValid() && args.exact() && len(call.Args)-1 == args.min
+}
+
+// contiguous reports whether the numbered placeholders of params, if any, use
+// every index from 1 to n.
+func contiguous(params []placeholder, n int) bool {
+	used := make(map[int]bool)
+	for _, p := range params {
+		if p.index > 0 {
+			used[p.index] = true
+		}
+	}
+	return len(used) == 0 || len(used) == n
+}
+
+// lastUnbound returns the last of params which is bound to an arg after the
+// first max ones, in the order of firstUnbound.
+func lastUnbound(params []placeholder, max int) placeholder {
+	var last placeholder
+	position, highest := 0, 0
+	for _, p := range params {
+		n := p.index
+		if n == 0 {
+			position++
+			n = position
+		}
+		if n > max && n > highest {
+			last, highest = p, n
+		}
+	}
+	return last
+}
+
+// firstUnbound returns the first of params which is bound to an arg after the
+// first max ones. Numbered placeholders are bound by index, and the others by
+// position.
+func firstUnbound(params []placeholder, max int) (placeholder, bool) {
+	position := 0
+	for _, p := range params {
+		n := p.index
+		if n == 0 {
+			position++
+			n = position
+		}
+		if n > max {
+			return p, true
+		}
+	}
+	return placeholder{}, false
+}
+
+// checkQuotedPlaceholders reports string literals which only contain a $N or ?
+// placeholder, like '$1', when the arg meant for it is passed. As the
+// placeholder is not bound, the arg is unused. It returns false if anything
+// was reported.
+func checkQuotedPlaceholders(query string, d *dialect, params []placeholder, call *ast.CallExpr, args argCount, pass *analysis.Pass) bool {
+	highest := highestIndex(params)
+	// quoted is the no. of quoted ? placeholders so far.
+	quoted := 0
+	for _, l := range lex(query, d) {
+		if l.kind != lexString || l.text[0] != '\'' || len(l.text) < 3 {
+			continue
+		}
+		text := l.text[1 : len(l.text)-1]
+		inner := lex(text, d)
+		if len(inner) != 1 || inner[0].kind != lexPlaceholder || inner[0].text != text {
+			continue
+		}
+		var n int
+		switch {
+		case text == "?":
+			quoted++
+			n = quoted
+			for _, p := range params {
+				if p.pos < l.pos {
+					n++
+				}
+			}
+			if args.min <= len(params) {
+				continue
+			}
+		case text[0] == '$' && isDigit(text[1]):
+			n, _ = strconv.Atoi(text[1:])
+			if n <= highest || args.min < n {
+				continue
+			}
+		default:
+			continue
+		}
+		reportQuery(pass, call, catPlaceholderStyle, l.pos, len(l.text), "Placeholder %s appears inside quotes: arg %d will be unused", text, n)
+		return false
+	}
+	return true
+}
+
+// reportUnboundSet reports a query with less args than its n positional
+// params, if the first placeholder without an arg is in a SET clause. This is
+// usually an assignment which was added without its arg, so the diagnostic
+// points it out. It returns true if anything was reported.
+func reportUnboundSet(query string, d *dialect, params []placeholder, n int, call *ast.CallExpr, args argCount, pass *analysis.Pass) bool {
+	if !args.lessThan(n) {
+		return false
+	}
+	var unbound placeholder
+	if params[0].style == styleQuestion {
+		unbound = params[args.max]
+	} else {
+		for _, p := range params {
+			if p.index > args.max {
+				unbound = p
+				break
+			}
+		}
+	}
+	assignment := setAssignment(query, d, unbound.pos)
+	if assignment == "" {
+		return false
+	}
+	reportf(pass, catArgCount, call.Lparen, "No. of args (%v) is less than no. of params (%d): SET %s has no arg", args, n, assignment)
+	return true
+}
+
+// setAssignment returns the text of the assignment of a SET clause of query
+// containing the byte offset pos, or "" if pos is not in a SET clause.
+func setAssignment(query string, d *dialect, pos int) string {
+	// depth is the nesting of parentheses, and setDepth the one of the
+	// current SET clause, or -1.
+	depth, setDepth := 0, -1
+	start := 0
+	for _, l := range lex(query, d) {
+		// end is set if l ends the SET clause, and not just an assignment.
+		end := false
+		switch {
+		case l.text == "(":
+			depth++
+			continue
+		case l.text == ")":
+			depth--
+			if setDepth < 0 || depth >= setDepth {
+				continue
+			}
+			end = true
+		case setDepth < 0 || depth != setDepth:
+			if l.kind == lexWord && strings.EqualFold(l.text, "SET") {
+				setDepth, start = depth, l.pos+len(l.text)
+			}
+			continue
+		case l.text == ",":
+		case l.text == ";", l.kind == lexWord && setClauseEnds[strings.ToUpper(l.text)]:
+			end = true
+		default:
+			continue
+		}
+		if start <= pos && pos < l.pos {
+			return strings.TrimSpace(query[start:l.pos])
+		}
+		if end {
+			setDepth = -1
+		} else {
+			start = l.pos + 1
+		}
+	}
+	if setDepth >= 0 && start <= pos {
+		return strings.TrimSpace(query[start:])
+	}
+	return ""
+}
+
+// setClauseEnds are the keywords which can follow a SET clause.
+var setClauseEnds = map[string]bool{
+	"FROM": true, "OUTPUT": true, "RETURNING": true, "WHERE": true,
+}
+
+// checkLimitArgs reports the args bound to LIMIT or OFFSET placeholders which
+// do not have an integer type. Several drivers fail to bind strings or floats
+// there at runtime.
+func checkLimitArgs(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	if call.Ellipsis.IsValid() {
+		return
+	}
+	args := call.Args[1:]
+	lexemes := lex(query, d)
+	n := 0
+	for i, l := range lexemes {
+		if l.kind != lexPlaceholder {
+			continue
+		}
+		if l.text == "?" {
+			n++
+		} else {
+			n, _ = strconv.Atoi(l.text[1:])
+		}
+		if n < 1 || n > len(args) {
+			continue
+		}
+		prev := prevLexeme(lexemes[:i])
+		clause := strings.ToUpper(prev.text)
+		if prev.kind != lexWord || clause != "LIMIT" && clause != "OFFSET" {
+			continue
+		}
+		typ := pass.TypesInfo.TypeOf(args[n-1])
+		if typ == nil {
+			continue
+		}
+		basic, ok := typ.Underlying().(*types.Basic)
+		if !ok || basic.Info()&(types.IsString|types.IsFloat) == 0 {
+			continue
+		}
+		reportf(pass, catArgType, args[n-1].Pos(), "Arg %d is used in %s but has type %s: it should be an integer", n, clause, types.TypeString(typ, types.RelativeTo(pass.Pkg)))
+	}
+}
+
+// checkColonArgs checks the args of a query with Oracle style bind variables.
+// If the args are passed with sql.Named, every bind variable has to have a
+// corresponding arg. Otherwise they are bound by position, which means one arg
+// per occurrence in SQL statements, and one arg per distinct name in PL/SQL
+// blocks.
+func checkColonArgs(query string, params []placeholder, call *ast.CallExpr, args argCount, pass *analysis.Pass) {
+	names := make(map[string]bool)
+	var distinct []placeholder
+	for _, p := range params {
+		// Bind variable names are case insensitive.
+		name := strings.ToLower(p.name)
+		if !names[name] {
+			names[name] = true
+			distinct = append(distinct, p)
+		}
+	}
+
+	if named, ok := namedArgs(call, pass.TypesInfo); ok && len(named) > 0 {
+		if len(named) != len(call.Args)-1 {
+			reportf(pass, catArgCount, call.Lparen, "Named and positional args cannot be mixed")
+			return
+		}
+		for _, p := range distinct {
+			if !named[strings.ToLower(p.name)] {
+				reportQuery(pass, call, catArgCount, p.pos, len(p.text), "No arg for bind variable %s", p.text)
+			}
+		}
+		checkUnusedNames(named, names, call, pass)
+		return
+	}
+
+	n, bound := len(params), params
+	if isPLSQL(query) {
+		n, bound = len(distinct), distinct
+	}
+	checkPositionalArgs(n, bound, call, args, pass)
+}
+
+// checkAtArgs checks the args of a query with SQL Server style parameters.
+// Positional args are bound to @p1, @p2, ... and named parameters have to
+// be passed with sql.Named. Variables declared inside the query itself are not
+// parameters.
+func checkAtArgs(query string, d *dialect, params []placeholder, call *ast.CallExpr, args argCount, pass *analysis.Pass) {
+	declared := declaredVars(query, d)
+	named, ok := namedArgs(call, pass.TypesInfo)
+	if !ok {
+		return
+	}
+	maxIndex := 0
+	reported := make(map[string]bool)
+	used := make(map[string]bool)
+	for _, p := range params {
+		name := strings.ToLower(p.name)
+		used[name] = true
+		switch {
+		case declared[name] || named[name]:
+		case p.index > 0:
+			if p.index > maxIndex {
+				maxIndex = p.index
+			}
+		case !reported[name]:
+			reported[name] = true
+			reportQuery(pass, call, catArgCount, p.pos, len(p.text), "No arg for parameter %s", p.text)
+		}
+	}
+	// Positional args are the ones not passed with sql.Named. The args are
+	// not spread, so their count is exact.
+	positional := argCount{args.min - len(named), args.max - len(named)}
+	if positional.lessThan(maxIndex) {
+		reportf(pass, catArgCount, call.Lparen, "No. of args (%v) is less than no. of params (%d): the query references @p%d but %s", positional, maxIndex, maxIndex, passedArgs(positional))
+	}
+	checkUnusedNames(named, used, call, pass)
+}
+
+// checkBraceArgs checks the args of a query with ClickHouse {name:Type}
+// parameters, which are only bound by name, with sql.Named or clickhouse-go's
+// Named.
+func checkBraceArgs(params []placeholder, call *ast.CallExpr, pass *analysis.Pass) {
+	named, ok := namedArgs(call, pass.TypesInfo)
+	if !ok {
+		return
+	}
+	if len(named) != len(call.Args)-1 {
+		reportf(pass, catArgCount, call.Lparen, "Positional args cannot be bound to {name:Type} parameters: pass them with clickhouse.Named")
+		return
+	}
+	used := make(map[string]bool)
+	for _, p := range params {
+		name := strings.ToLower(p.name)
+		if !named[name] && !used[name] {
+			reportQuery(pass, call, catArgCount, p.pos, len(p.text), "No arg for parameter %s", p.text)
+		}
+		used[name] = true
+	}
+	checkUnusedNames(named, used, call, pass)
+}
+
+// declaredVars returns the lower cased names of the variables declared with
+// DECLARE in a SQL Server query.
+func declaredVars(query string, d *dialect) map[string]bool {
+	declared := make(map[string]bool)
+	inDeclare, expectVar := false, false
+	for _, l := range lex(query, d) {
+		switch {
+		case l.kind == lexWord && strings.EqualFold(l.text, "DECLARE"):
+			inDeclare, expectVar = true, true
+		case l.text == ";":
+			inDeclare, expectVar = false, false
+		case inDeclare && l.text == ",":
+			expectVar = true
+		case expectVar && l.kind == lexPlaceholder:
+			declared[strings.ToLower(l.text[1:])] = true
+			expectVar = false
+		default:
+			expectVar = false
+		}
+	}
+	return declared
+}
+
+// msPositional returns N if name is of the form pN, and 0 otherwise.
+func msPositional(name string) int {
+	if len(name) < 2 || name[0] != 'p' && name[0] != 'P' {
+		return 0
+	}
+	n, err := strconv.Atoi(name[1:])
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// checkSQLiteArgs checks the args of a query with SQLite style parameters. A ?
+// takes the index after the largest one used so far, ?NNN takes index NNN,
+// and named parameters take the next index on their first occurrence. Named
+// parameters can also be bound by name with sql.Named.
+func checkSQLiteArgs(params []placeholder, call *ast.CallExpr, args argCount, pass *analysis.Pass) {
+	maxIndex, ok := sqliteMaxIndex(params)
+	if !ok {
+		reportf(pass, catPlaceholderStyle, call.Lparen, "Mixed ? and ?NNN placeholders")
+		return
+	}
+
+	if named, ok := namedArgs(call, pass.TypesInfo); ok && len(named) > 0 {
+		reported := make(map[string]bool)
+		used := make(map[string]bool)
+		for _, p := range params {
+			used[strings.ToLower(p.name)] = true
+			if p.name != "" && !named[strings.ToLower(p.name)] && !reported[p.text] {
+				reported[p.text] = true
+				reportQuery(pass, call, catArgCount, p.pos, len(p.text), "No arg for parameter %s", p.text)
+			}
+		}
+		checkUnusedNames(named, used, call, pass)
+		return
+	}
+	checkPositionalArgs(maxIndex, nil, call, args, pass)
+}
+
+// sqliteMaxIndex returns the highest index SQLite assigns to the parameters
+// params. It returns false if ? and ?NNN are mixed, which SQLite allows but
+// which is bound to go wrong.
+func sqliteMaxIndex(params []placeholder) (int, bool) {
+	maxIndex := 0
+	var plain, numbered bool
+	indices := make(map[string]int)
+	for _, p := range params {
+		switch {
+		case p.text == "?":
+			plain = true
+			maxIndex++
+		case p.text[0] == '?':
+			numbered = true
+			if p.index > maxIndex {
+				maxIndex = p.index
+			}
+		case indices[p.text] == 0:
+			maxIndex++
+			indices[p.text] = maxIndex
+		}
+	}
+	return maxIndex, !plain || !numbered
+}
+
+// checkUnusedNames reports the names of the args passed with sql.Named which
+// are not in used, the lower cased names of the parameters of the query.
+func checkUnusedNames(named, used map[string]bool, call *ast.CallExpr, pass *analysis.Pass) {
+	var unused []string
+	for name := range named {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) == 0 {
+		return
+	}
+	sort.Strings(unused)
+	reportf(pass, catArgCount, call.Lparen, "Named args not used by the query: %s", strings.Join(unused, ", "))
+}
+
+// isPLSQL reports whether query is an anonymous PL/SQL block.
+func isPLSQL(query string) bool {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return false
+	}
+	first := strings.ToUpper(fields[0])
+	return first == "BEGIN" || first == "DECLARE"
+}
@@ -0,0 +1,114 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// reportQuery reports a diagnostic of category about the query text of length
+// n at the byte offset of the query of call. The diagnostic is placed on that
+// text if the query is a string literal in the call, and on the call
+// otherwise.
+func reportQuery(pass *analysis.Pass, call *ast.CallExpr, category string, offset, n int, format string, args ...interface{}) {
+	pass.Report(queryDiagnostic(call, category, offset, n, format, args...))
+}
+
+// queryDiagnostic returns the diagnostic reported by reportQuery, so that it
+// can be completed before it is reported.
+func queryDiagnostic(call *ast.CallExpr, category string, offset, n int, format string, args ...interface{}) analysis.Diagnostic {
+	pos, end := queryRange(call, offset, n)
+	return analysis.Diagnostic{Pos: pos, End: end, Category: category, Message: fmt.Sprintf(format, args...)}
+}
+
+// queryRange returns the range of the query text of length n at the byte
+// offset of the query of call, or the position of the call if the query is
+// not a string literal in the call.
+func queryRange(call *ast.CallExpr, offset, n int) (token.Pos, token.Pos) {
+	pos, ok := queryOffsetPos(call.Args[0], offset)
+	if !ok {
+		return call.Lparen, token.NoPos
+	}
+	end, _ := queryOffsetPos(call.Args[0], offset+n)
+	return pos, end
+}
+
+// queryOffsetPos returns the position of the byte at offset in the value of
+// the string literal expr. It returns false if expr is not a string literal.
+func queryOffsetPos(expr ast.Expr, offset int) (token.Pos, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING || len(lit.Value) < 2 {
+		return token.NoPos, false
+	}
+	src := lit.Value[1 : len(lit.Value)-1]
+	if lit.Value[0] == '`' {
+		// Carriage returns are dropped from raw strings.
+		if strings.Contains(src, "\r") || offset > len(src) {
+			return token.NoPos, false
+		}
+		return lit.Pos() + 1 + token.Pos(offset), true
+	}
+	// Map the offset in the value to the one in the source, escape by escape.
+	decoded := 0
+	for s := src; ; {
+		if decoded >= offset {
+			return lit.Pos() + 1 + token.Pos(len(src)-len(s)), decoded == offset
+		}
+		if s == "" {
+			return token.NoPos, false
+		}
+		value, multibyte, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return token.NoPos, false
+		}
+		// Byte escapes, like \xff, decode to a single byte.
+		if multibyte {
+			decoded += utf8.RuneLen(value)
+		} else {
+			decoded++
+		}
+		s = tail
+	}
+}
+
+// factPos returns the position of pos, a file:line:column as recorded in the
+// facts, in the files of pass or of the packages it depends on. It returns
+// token.NoPos if the file is not in the file set of pass.
+func factPos(pass *analysis.Pass, pos string) token.Pos {
+	rest, col, ok := cutNumber(pos)
+	if !ok {
+		return token.NoPos
+	}
+	name, line, ok := cutNumber(rest)
+	if !ok || line < 1 {
+		return token.NoPos
+	}
+	found := token.NoPos
+	pass.Fset.Iterate(func(f *token.File) bool {
+		if f.Name() != name || line > f.LineCount() {
+			return true
+		}
+		found = f.LineStart(line)
+		// The files of export data only have lines.
+		if col > 1 && f.Offset(found)+col-1 < f.Size() {
+			found += token.Pos(col - 1)
+		}
+		return false
+	})
+	return found
+}
+
+// cutNumber returns pos without its last :number, and that number.
+func cutNumber(pos string) (string, int, bool) {
+	i := strings.LastIndexByte(pos, ':')
+	if i < 0 {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(pos[i+1:])
+	return pos[:i], n, err == nil
+}
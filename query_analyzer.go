@@ -1,77 +1,333 @@
 package sqlargs
 
 import (
+	"errors"
+	"fmt"
 	"go/ast"
+	"strings"
 
-	pg_query "github.com/lfittl/pg_query_go"
-	nodes "github.com/lfittl/pg_query_go/nodes"
 	"golang.org/x/tools/go/analysis"
 )
 
-func analyzeQuery(query string, call *ast.CallExpr, pass *analysis.Pass) {
-	tree, err := pg_query.Parse(query)
-	if err != nil {
-		pass.Reportf(call.Lparen, "Invalid query: %v", err)
-		return
+// analyzeQuery checks query, written in dialect d, against the args passed to call.
+// Each problem is reported on its own, but a problem which explains another,
+// like a quoted placeholder explaining a surplus arg, is reported alone. valid
+// is cleared if a syntax error was already reported, in which case the lists
+// of the query are not counted and it is not validated with the query parser.
+// parsed records the results of the query parser in the pass.
+func analyzeQuery(cfg *config, query string, call *ast.CallExpr, args argCount, d *dialect, valid bool, parsed parseMemo, pass *analysis.Pass) {
+	parse := valid
+	// count is cleared when a reported placeholder makes the no. of args
+	// mismatch.
+	count := true
+	if d != permissive && !checkForeignStyle(query, d, call, pass) {
+		count, parse = false, false
 	}
-	// Analyze the parse tree for semantic errors.
-	if len(tree.Statements) == 0 {
-		return
+	if !d.multiStatements && args.min > 0 && !isPLSQL(query) && multipleStatements(query, d) {
+		reportf(pass, catArgCount, call.Lparen, "Multiple statements with args: most drivers cannot bind args to them")
+	}
+	params, style := placeholders(query, d)
+	if len(params) > 0 && !checkDDLPlaceholders(query, d, call, pass) {
+		count = false
+	}
+	checkIndices(params, call, pass)
+	if style != styleMixed {
+		checkParamLimit(bindCount(params, style, d), d, call, args, pass)
 	}
-	rawStmt, ok := tree.Statements[0].(nodes.RawStmt)
-	if !ok {
+	if style == styleDollar || style == styleQuestion || style == styleNone {
+		if !checkQuotedPlaceholders(query, d, params, call, args, pass) {
+			count = false
+		}
+	}
+	switch {
+	case style == styleMixed:
+		checkMixedStyles(params, call, pass)
+	case d.sqliteParams:
+		checkSQLiteArgs(params, call, args, pass)
+	case style == styleQuestion:
+		if count && !reportUnboundSet(query, d, params, len(params), call, args, pass) {
+			checkPositionalArgs(len(params), params, call, args, pass)
+		}
+		checkLimitArgs(query, d, call, pass)
+		checkCastArgs(query, d, call, pass)
+	case style == styleColon:
+		checkColonArgs(query, params, call, args, pass)
+	case style == styleAt:
+		checkAtArgs(query, d, params, call, args, pass)
+		if d.name == "sqlserver" {
+			checkOutArgs(query, d, params, call, pass)
+		}
+	case style == styleBrace:
+		checkBraceArgs(params, call, pass)
+	case style == styleNone:
+		if count {
+			checkPositionalArgs(0, nil, call, args, pass)
+		}
+	case style == styleDollar:
+		checkNumbering(params, call, pass)
+		// A $N placeholder can be used more than once, so the no. of args is
+		// the highest N.
+		if n := highestIndex(params); count && !reportUnboundSet(query, d, params, n, call, args, pass) {
+			checkPositionalArgs(n, params, call, args, pass)
+		}
+		checkLimitArgs(query, d, call, pass)
+		checkCastArgs(query, d, call, pass)
+	}
+	if valid {
+		checkInsertArity(query, d, call, pass)
+	}
+	checkOrdinals(query, d, call, pass)
+	if cfg.groupBy {
+		checkGroupBy(query, d, call, pass)
+	}
+	if parse && style != styleMixed {
+		checkSyntax(cfg, query, d, call, parsed, pass)
+	}
+}
+
+// checkSyntax reports query if the parser of cfg rejects it.
+func checkSyntax(cfg *config, query string, d *dialect, call *ast.CallExpr, parsed parseMemo, pass *analysis.Pass) {
+	name, parser := cfg.queryParser()
+	if parser == nil {
 		return
 	}
-	switch stmt := rawStmt.Stmt.(type) {
-	// 1. For insert statements, the no. of columns(if present) should be equal to no. of values.
-	case nodes.InsertStmt:
-		numCols := len(stmt.Cols.Items)
-		if numCols == 0 {
-			return
+	err := parsed.parse(name, parser, query, d)
+	var panicked *parserPanic
+	switch {
+	case errors.As(err, &panicked):
+		// The placeholders were still counted by the lexer.
+		if cfg.strict {
+			reportf(pass, catStrict, call.Lparen, "Query could not be fully parsed: %v", err)
+		}
+	case err != nil:
+		reportf(pass, catSyntax, call.Lparen, "Invalid query: %v", err)
+	}
+}
+
+// checkConstantQuery runs the lexical checks on a query which is a constant
+// in the source. These catch typos which are unlikely in generated queries.
+// It returns whether the query can be analyzed any further, which is not the
+// case if it contains fmt verbs or is unbalanced, and whether it should be
+// parsed, which is not the case if a syntax error was already reported.
+func checkConstantQuery(cfg *config, query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) (analyze, parse bool) {
+	checkNullComparisons(query, d, call, pass)
+	checkInsertColumns(query, d, call, pass)
+	checkForeignFuncs(query, d, call, pass)
+	if cfg.requireWhere {
+		checkFullTableWrites(query, d, call, pass)
+	}
+	if cfg.selectStar {
+		checkSelectStar(query, d, call, pass)
+	}
+	if cfg.insertColumns {
+		checkInsertColumnList(query, d, call, pass)
+	}
+	if !checkFmtVerbs(query, d, call, pass) || !checkBalance(query, d, call, pass) {
+		return false, false
+	}
+	// A reserved keyword used as a column can look like a trailing comma, as
+	// in (id, order), so the commas are only checked without one.
+	return true, checkReservedIdents(query, d, call, pass) && checkCommas(query, d, call, pass)
+}
+
+// checkNullComparisons reports comparisons with NULL using =, != or <>, which
+// are never true. Assignments of NULL in SET clauses are fine.
+func checkNullComparisons(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	lexemes := lex(query, d)
+	for i, l := range lexemes {
+		next := nextLexeme(lexemes[i+1:])
+		if next.kind != lexWord || !strings.EqualFold(next.text, "NULL") || i == 0 {
+			continue
 		}
-		selStmt, ok := stmt.SelectStmt.(nodes.SelectStmt)
-		if !ok {
-			return
+		var op, fix string
+		prev := lexemes[i-1]
+		adjacent := prev.pos+len(prev.text) == l.pos
+		switch {
+		case l.text == "=" && adjacent && prev.text == "!":
+			op, fix = "!=", "IS NOT NULL"
+		case l.text == "=" && adjacent && (prev.text == "<" || prev.text == ">"):
+			continue
+		case l.text == "=":
+			op, fix = "=", "IS NULL"
+		case l.text == ">" && adjacent && prev.text == "<":
+			op, fix = "<>", "IS NOT NULL"
+		default:
+			continue
 		}
-		if len(selStmt.ValuesLists) == 0 {
-			return
+		if op == "=" && setAssignment(query, d, l.pos) != "" {
+			continue
+		}
+		reportf(pass, catSemantics, call.Lparen, "Comparison %s NULL is never true: use %s", op, fix)
+	}
+}
+
+// openLexemes describes the kinds of lexemes which can be left open.
+var openLexemes = map[lexemeKind]string{
+	lexString:      "string literal",
+	lexQuotedIdent: "quoted identifier",
+	lexComment:     "comment",
+}
+
+// checkBalance reports a constant query with an unterminated string, quoted
+// identifier or comment, or with unbalanced parentheses. It returns false if
+// anything was reported.
+func checkBalance(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) bool {
+	err := unbalanced(query, d)
+	switch {
+	case err == nil:
+		return true
+	case err.Offset < 0:
+		reportf(pass, catSyntax, call.Lparen, "%s", err.Msg)
+	default:
+		reportQuery(pass, call, catSyntax, err.Offset, err.Len, "%s", err.Msg)
+	}
+	return false
+}
+
+// unbalanced returns the error of the first unterminated string, quoted
+// identifier or comment, or unbalanced parenthesis of query, or nil if there
+// is none.
+func unbalanced(query string, d *dialect) *QueryError {
+	depth := 0
+	for _, l := range lex(query, d) {
+		switch {
+		case l.open:
+			return &QueryError{Offset: l.pos, Len: len(l.text), Msg: fmt.Sprintf("Unterminated %s: %s", openLexemes[l.kind], l.text)}
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			if depth--; depth < 0 {
+				return &QueryError{Offset: l.pos, Len: 1, Msg: fmt.Sprintf("Unbalanced parentheses: ) at offset %d has no matching (", l.pos)}
+			}
 		}
-		numValues := len(selStmt.ValuesLists[0])
-		if numCols != numValues {
-			pass.Reportf(call.Lparen, "No. of columns (%d) not equal to no. of values (%d)", numCols, numValues)
+	}
+	if depth > 0 {
+		return &QueryError{Offset: -1, Msg: fmt.Sprintf("Unbalanced parentheses: %d ( not closed", depth)}
+	}
+	return nil
+}
+
+// clauseKeywords are the reserved keywords starting a clause, which cannot
+// follow a comma.
+var clauseKeywords = map[string]bool{
+	"EXCEPT": true, "FROM": true, "GROUP": true, "HAVING": true, "INTERSECT": true,
+	"INTO": true, "LIMIT": true, "OFFSET": true, "ORDER": true, "RETURNING": true,
+	"UNION": true, "VALUES": true, "WHERE": true, "WINDOW": true,
+}
+
+// checkCommas reports a constant query with a doubled comma, like in
+// VALUES ($1,, $2), or a trailing one, like in SELECT a, b, FROM t. These are
+// usually left over from removing a column. It returns false if anything was
+// reported.
+func checkCommas(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) bool {
+	lexemes := lex(query, d)
+	for i, l := range lexemes {
+		if l.text != "," {
+			continue
 		}
-		numParams := numParams(selStmt.ValuesLists[0])
-		args := len(call.Args[1:])
-		// A safe check is to just check if args are less than no. of params. If this is true,
-		// then there has to be an error somewhere. On the contrary, if there are less params
-		// found than args, then it just means we haven't parsed the query well enough and there are
-		// other parts of the query which use the other arguments.
-		if args < numParams {
-			pass.Reportf(call.Lparen, "No. of args (%d) is less than no. of params (%d)", args, numParams)
+		next := nextLexeme(lexemes[i+1:])
+		switch {
+		case next.text == ",":
+			reportQuery(pass, call, catSyntax, l.pos, 1, "Doubled comma at offset %d", l.pos)
+			return false
+		case next.text == ")", next.kind == lexComment:
+			reportQuery(pass, call, catSyntax, l.pos, 1, "Trailing comma at offset %d", l.pos)
+			return false
+		case next.kind == lexWord && clauseKeywords[strings.ToUpper(next.text)]:
+			reportQuery(pass, call, catSyntax, l.pos, 1, "Trailing comma before %s", next.text)
+			return false
 		}
 	}
+	return true
 }
 
-//numParams returns the count of unique paramters.
-func numParams(params []nodes.Node) int {
-	num := 0
-	// posMap is used to keep track of unique positional parameters.
-	posMap := make(map[int]bool)
-	for _, p := range params {
-		switch t := p.(type) {
-		case nodes.ParamRef:
-			if !posMap[t.Number] {
-				num++
-				posMap[t.Number] = true
+// fmtVerbs are the fmt verbs which are usually used to build queries.
+var fmtVerbs = map[string]bool{"%s": true, "%d": true, "%v": true, "%q": true}
+
+// checkFmtVerbs reports a constant query which contains a fmt verb, like
+// WHERE name = '%s', as it was most likely meant to go through fmt.Sprintf.
+// It returns false if anything was reported.
+func checkFmtVerbs(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) bool {
+	lexemes := lex(query, d)
+	for i, l := range lexemes {
+		var verb string
+		switch l.kind {
+		case lexString, lexQuotedIdent:
+			// Only match a verb which is the whole literal, so that LIKE
+			// patterns like '%s%' are not reported.
+			if len(l.text) > 2 {
+				verb = l.text[1 : len(l.text)-1]
 			}
-		case nodes.TypeCast:
-			if pRef, ok := t.Arg.(nodes.ParamRef); ok {
-				if !posMap[pRef.Number] {
-					num++
-					posMap[pRef.Number] = true
-				}
+		case lexPunct:
+			if l.text == "%" && i+1 < len(lexemes) && lexemes[i+1].pos == l.pos+1 {
+				verb = "%" + lexemes[i+1].text
 			}
 		}
+		if fmtVerbs[verb] {
+			reportf(pass, catSyntax, call.Lparen, "Query contains fmt verb %s: use a placeholder or fmt.Sprintf", verb)
+			return false
+		}
+	}
+	return true
+}
+
+// multipleStatements reports whether query has more than one statement
+// separated by a semicolon.
+func multipleStatements(query string, d *dialect) bool {
+	ended := false
+	for _, l := range lex(query, d) {
+		switch {
+		case l.kind == lexComment:
+		case l.text == ";":
+			ended = true
+		case ended:
+			return true
+		}
+	}
+	return false
+}
+
+// hasReturning reports whether query returns rows with a top level RETURNING
+// clause. Oracle's RETURNING ... INTO, which binds the rows to out args, is
+// not counted.
+func hasReturning(query string, d *dialect) bool {
+	depth := 0
+	returning := false
+	for _, l := range lex(query, d) {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth != 0 || l.kind != lexWord:
+		case strings.EqualFold(l.text, "RETURNING"):
+			returning = true
+		case returning && strings.EqualFold(l.text, "INTO"):
+			return false
+		}
+	}
+	return returning
+}
+
+// unparsedStatements are the statements which are newer than the Postgres
+// parser, so only their placeholders are checked by the pg_query backend.
+var unparsedStatements = map[string]bool{
+	// Added in Postgres 11.
+	"CALL": true,
+	// Added in Postgres 15.
+	"MERGE": true,
+}
+
+// statementKeyword returns the first keyword of query in upper case, or "" if
+// there is none.
+func statementKeyword(query string, d *dialect) string {
+	for _, l := range lex(query, d) {
+		switch {
+		case l.kind == lexWord:
+			return strings.ToUpper(l.text)
+		case l.kind != lexComment && l.text != "(":
+			return ""
+		}
 	}
-	return num
+	return ""
 }
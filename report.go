@@ -0,0 +1,87 @@
+package sqlargs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// inventoryDir is the directory selected with the -inventory flag, where the
+// queries of each package are written.
+var inventoryDir string
+
+// writeInventory writes the queries of q to dir.
+func writeInventory(dir string, q *Queries) error {
+	data, err := json.MarshalIndent(q, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("-inventory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, cacheKey(q.Package)+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("-inventory: %v", err)
+	}
+	return nil
+}
+
+// reportedQuery is a query of the inventory report.
+type reportedQuery struct {
+	Package string
+	Query
+}
+
+// InventoryReport writes to w the queries written to the -inventory directory
+// dir, sorted by package and position, in format json or csv. Each query has
+// its position, kind of statement, the tables it touches and its no. of
+// placeholders, for auditing the access patterns of a codebase.
+func InventoryReport(dir, format string, w io.Writer) error {
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unknown format %q, must be json or csv", format)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no queries in %s: run sqlargs -inventory=%s first", dir, dir)
+	}
+	var queries []reportedQuery
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var q Queries
+		if err := json.Unmarshal(data, &q); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		for _, query := range q.Queries {
+			queries = append(queries, reportedQuery{Package: q.Package, Query: query})
+		}
+	}
+	sort.SliceStable(queries, func(i, j int) bool {
+		if queries[i].Package != queries[j].Package {
+			return queries[i].Package < queries[j].Package
+		}
+		return queries[i].Pos < queries[j].Pos
+	})
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "\t")
+		return enc.Encode(queries)
+	}
+	out := csv.NewWriter(w)
+	out.Write([]string{"package", "position", "kind", "tables", "placeholders", "query"})
+	for _, q := range queries {
+		out.Write([]string{q.Package, q.Pos, q.Kind, strings.Join(q.Tables, " "), strconv.Itoa(q.Placeholders), q.Text})
+	}
+	out.Flush()
+	return out.Error()
+}
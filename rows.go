@@ -0,0 +1,329 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// localVar returns the variable the result of call, like rows, is assigned
+// to, given the stack of nodes enclosing it, along with the assignment and the
+// position until which the variable holds it, which is the end of body or the
+// next assignment of the variable. It returns false if the result is not
+// assigned to a local variable, or if it escapes the function, which is when
+// it is returned, also as a named result by a bare return, stored, like in a
+// field, or passed to a function, which may close it. Other uses, like _ = rows
+// or rows != nil, do not make it escape.
+func localVar(call *ast.CallExpr, stack []ast.Node, body *ast.BlockStmt, info *types.Info) (types.Object, *ast.AssignStmt, token.Pos, bool) {
+	if body == nil || len(stack) < 2 {
+		return nil, nil, token.NoPos, false
+	}
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 || assign.Rhs[0] != call || len(assign.Lhs) == 0 {
+		return nil, nil, token.NoPos, false
+	}
+	ident, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil, nil, token.NoPos, false
+	}
+	obj := info.ObjectOf(ident)
+	// Package level variables can be closed anywhere.
+	if obj == nil || obj.Parent() == obj.Pkg().Scope() {
+		return nil, nil, token.NoPos, false
+	}
+	end := assignedUntil(obj, assign, body, info)
+	isVar := func(expr ast.Expr) bool {
+		for {
+			paren, ok := expr.(*ast.ParenExpr)
+			if !ok {
+				break
+			}
+			expr = paren.X
+		}
+		ident, ok := expr.(*ast.Ident)
+		return ok && info.ObjectOf(ident) == obj
+	}
+	escapes := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		if escapes || n == nil || n.Pos() <= assign.End() || n.Pos() >= end {
+			return !escapes
+		}
+		var uses []ast.Expr
+		switch n := n.(type) {
+		case *ast.ReturnStmt:
+			uses = n.Results
+		case *ast.CallExpr:
+			uses = n.Args
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				if i < len(n.Lhs) {
+					if ident, ok := n.Lhs[i].(*ast.Ident); ok && ident.Name == "_" {
+						continue
+					}
+				}
+				uses = append(uses, rhs)
+			}
+		case *ast.ValueSpec:
+			uses = n.Values
+		case *ast.CompositeLit:
+			uses = n.Elts
+		case *ast.KeyValueExpr:
+			uses = []ast.Expr{n.Value}
+		case *ast.SendStmt:
+			uses = []ast.Expr{n.Value}
+		case *ast.UnaryExpr:
+			if n.Op == token.AND {
+				uses = []ast.Expr{n.X}
+			}
+		}
+		for _, use := range uses {
+			escapes = escapes || isVar(use)
+		}
+		return !escapes
+	})
+	if !escapes && isNamedResult(obj, stack, info) {
+		ast.Inspect(body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncLit:
+				return false
+			case *ast.ReturnStmt:
+				escapes = escapes || len(n.Results) == 0 && n.Pos() > assign.End() && n.Pos() < end
+			}
+			return !escapes
+		})
+	}
+	return obj, assign, end, !escapes
+}
+
+// isNamedResult reports whether obj is a named result of the function
+// enclosing the stack, which its bare returns return.
+func isNamedResult(obj types.Object, stack []ast.Node, info *types.Info) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		var results *ast.FieldList
+		switch f := stack[i].(type) {
+		case *ast.FuncDecl:
+			results = f.Type.Results
+		case *ast.FuncLit:
+			results = f.Type.Results
+		default:
+			continue
+		}
+		if results == nil {
+			return false
+		}
+		for _, field := range results.List {
+			for _, name := range field.Names {
+				if info.ObjectOf(name) == obj {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// assignedUntil returns the position of the next assignment of obj in body
+// after assign, or the end of body.
+func assignedUntil(obj types.Object, assign *ast.AssignStmt, body *ast.BlockStmt, info *types.Info) token.Pos {
+	end := body.End()
+	ast.Inspect(body, func(n ast.Node) bool {
+		a, ok := n.(*ast.AssignStmt)
+		if !ok || a.Pos() <= assign.Pos() || a.Pos() >= end {
+			return true
+		}
+		for _, lhs := range a.Lhs {
+			if ident, ok := lhs.(*ast.Ident); ok && info.ObjectOf(ident) == obj {
+				end = a.Pos()
+			}
+		}
+		return true
+	})
+	return end
+}
+
+// methodCalls returns the calls of the method name on obj in body, between
+// pos and end, along with whether each of them is deferred.
+func methodCalls(obj types.Object, name string, body *ast.BlockStmt, pos, end token.Pos, info *types.Info) (calls []*ast.CallExpr, deferred []bool) {
+	var defers []*ast.DeferStmt
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.End() <= pos || n.Pos() >= end {
+			return n == nil || n.Pos() < end
+		}
+		switch n := n.(type) {
+		case *ast.DeferStmt:
+			defers = append(defers, n)
+		case *ast.CallExpr:
+			sel, ok := n.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != name {
+				return true
+			}
+			if ident, ok := sel.X.(*ast.Ident); ok && info.ObjectOf(ident) == obj {
+				isDeferred := false
+				for _, d := range defers {
+					if d.Pos() <= n.Pos() && n.End() <= d.End() {
+						isDeferred = true
+					}
+				}
+				calls = append(calls, n)
+				deferred = append(deferred, isDeferred)
+			}
+		}
+		return true
+	})
+	return calls, deferred
+}
+
+// checkRows checks the use of the rows returned by call, a Query of sql.DB or
+// sql.Tx, when they are assigned to a variable which does not
+// escape the function.
+func checkRows(call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	if blank := blankRows(call, stack); blank != nil {
+		reportf(pass, catRows, blank.Pos(), "Rows are assigned to _: they are never closed, which leaks their connection, use Exec, or Scan and close the rows")
+		return
+	}
+	body := enclosingBody(stack)
+	obj, assign, end, ok := localVar(call, stack, body, pass.TypesInfo)
+	if !ok {
+		return
+	}
+	checkRowsClose(obj, assign, end, body, pass)
+	checkRowsErr(obj, assign, end, body, pass)
+	checkDeferInLoop(obj, assign, end, body, pass)
+}
+
+// blankRows returns the blank identifier the rows returned by call are
+// assigned to, given the stack of nodes enclosing it, or nil.
+func blankRows(call *ast.CallExpr, stack []ast.Node) *ast.Ident {
+	if len(stack) < 2 {
+		return nil
+	}
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 || assign.Rhs[0] != call || len(assign.Lhs) == 0 {
+		return nil
+	}
+	if ident, ok := assign.Lhs[0].(*ast.Ident); ok && ident.Name == "_" {
+		return ident
+	}
+	return nil
+}
+
+// checkRowsClose reports the rows obj, assigned by assign and held until end,
+// which are not closed, as they hold their connection until they are. The
+// rows are closed on all paths by a deferred Close. An explicit Close must not
+// be preceded by a return, other than the one of the error check of the
+// query.
+func checkRowsClose(obj types.Object, assign *ast.AssignStmt, end token.Pos, body *ast.BlockStmt, pass *analysis.Pass) {
+	closes, deferred := methodCalls(obj, "Close", body, assign.End(), end, pass.TypesInfo)
+	if len(closes) == 0 {
+		reportf(pass, catRows, assign.Lhs[0].Pos(), "Rows are never closed: defer %s.Close() after checking the error, as they hold a connection until they are", obj.Name())
+		return
+	}
+	for _, isDeferred := range deferred {
+		if isDeferred {
+			return
+		}
+	}
+	var errObj types.Object
+	if len(assign.Lhs) == 2 {
+		if ident, ok := assign.Lhs[1].(*ast.Ident); ok {
+			errObj = pass.TypesInfo.ObjectOf(ident)
+		}
+	}
+	first := closes[0].Pos()
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.End() <= assign.End() || n.Pos() >= first {
+			return n == nil || n.Pos() < first
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			// The rows are nil when the query fails.
+			if errObj != nil && mentions(n.Cond, errObj, pass.TypesInfo) {
+				return false
+			}
+		case *ast.ReturnStmt:
+			// return rows.Close() closes them.
+			if n.End() > first {
+				return false
+			}
+			reportf(pass, catRows, n.Pos(), "Rows are not closed on this path: defer %s.Close() after checking the error", obj.Name())
+		}
+		return true
+	})
+}
+
+// checkRowsErr reports the loops over the rows obj, assigned by assign and
+// held until end, when rows.Err is never called, as rows.Next returns false
+// both at the end of the rows and on errors.
+func checkRowsErr(obj types.Object, assign *ast.AssignStmt, end token.Pos, body *ast.BlockStmt, pass *analysis.Pass) {
+	if errs, _ := methodCalls(obj, "Err", body, assign.End(), end, pass.TypesInfo); len(errs) > 0 {
+		return
+	}
+	nexts, _ := methodCalls(obj, "Next", body, assign.End(), end, pass.TypesInfo)
+	ast.Inspect(body, func(n ast.Node) bool {
+		loop, ok := n.(*ast.ForStmt)
+		if !ok || loop.Cond == nil {
+			return true
+		}
+		for _, next := range nexts {
+			if loop.Cond == ast.Expr(next) {
+				reportf(pass, catRows, loop.For, "Iteration errors are never checked: check %s.Err() after the loop, as %s.Next() also returns false on errors", obj.Name(), obj.Name())
+			}
+		}
+		return true
+	})
+}
+
+// checkDeferInLoop reports the deferred Close of the rows obj, assigned by
+// assign and held until end, in a loop running the query, as the rows of
+// every iteration are only closed when the function returns.
+func checkDeferInLoop(obj types.Object, assign *ast.AssignStmt, end token.Pos, body *ast.BlockStmt, pass *analysis.Pass) {
+	var loops []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			return true
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ForStmt, *ast.RangeStmt:
+			if n.Pos() < assign.Pos() && assign.End() <= n.End() {
+				loops = append(loops, n)
+			}
+		case *ast.DeferStmt:
+			if n.Pos() <= assign.End() || n.Pos() >= end || len(loops) == 0 {
+				return false
+			}
+			sel, ok := n.Call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Close" {
+				return false
+			}
+			if ident, ok := sel.X.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+				for _, loop := range loops {
+					if loop.Pos() < n.Pos() && n.End() <= loop.End() {
+						reportf(pass, catRows, n.Defer, "%s.Close() is deferred in a loop: the rows of every iteration stay open until the function returns, move the body of the loop into a function, or close the rows at the end of each iteration", obj.Name())
+						break
+					}
+				}
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// mentions reports whether expr refers to obj.
+func mentions(expr ast.Expr, obj types.Object, info *types.Info) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && info.ObjectOf(ident) == obj {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
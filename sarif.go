@@ -0,0 +1,267 @@
+package sqlargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// categoryDocs describe the categories, as the rules of the SARIF reports.
+var categoryDocs = map[string]string{
+	catArgCount:         "Args which do not match the placeholders of the query.",
+	catArgType:          "Args whose Go type cannot be bound as intended.",
+	catArity:            "Lists of different lengths, like the columns and the values of an INSERT.",
+	catDatabase:         "Queries rejected by the database of -dsn.",
+	catDirective:        "Invalid //sqlargs: comments.",
+	catMethod:           "Queries run with the wrong method, like a SELECT run with Exec.",
+	catPlaceholderStyle: "Placeholders which are invalid, written in the style of another dialect, or used in DDL.",
+	catSchema:           "Queries which do not match the schema.",
+	catSemantics:        "Valid queries which do not do what is meant, like comparisons with NULL.",
+	catSyntax:           "Invalid queries.",
+	catInjection:        "Values interpolated into queries.",
+	catCustom:           "Findings of custom checks.",
+	catRows:             "Rows which are not closed, or whose iteration errors are not checked.",
+	catStmt:             "Prepared statements which are not closed.",
+	catTx:               "Transactions which are neither committed nor rolled back.",
+	catMock:             "go-sqlmock expectations which do not match the queries of the code.",
+	catStrict:           "Queries and args which cannot be determined statically, reported with -strict.",
+	catRequireConst:     "Queries which are not constants, reported with -require-const-queries.",
+	catRequireWhere:     "UPDATE and DELETE statements without a WHERE clause, reported with -require-where.",
+	catGroupBy:          "Selected columns which are neither aggregated nor in GROUP BY, reported with -group-by.",
+	catSelectStar:       "Queries selecting *, reported with -select-star.",
+	catInsertColumns:    "INSERT statements without a column list, reported with -insert-columns.",
+	catLoopQueries:      "Queries run in loops, reported with -loop-queries.",
+	catErrNoRows:        "Errors of QueryRow handled without checking for sql.ErrNoRows, reported with -err-no-rows.",
+	catUncheckedExec:    "Exec calls whose result and error are discarded, reported with -unchecked-exec.",
+	catContextMethods:   "Calls which do not use the context of the function, reported with -context-methods.",
+	catSwappedArgs:      "Args which look swapped, reported with -swapped-args.",
+	catDuplicateArgs:    "Args passed for placeholders of different columns, reported with -duplicate-args.",
+	catUnusedQueries:    "Query constants which are never used, reported with -unused-queries.",
+	catDuplicateQueries: "Queries which are defined more than once, reported with -duplicate-queries.",
+}
+
+// jsonDiagnostic is a diagnostic in the -json output of the analyzer.
+type jsonDiagnostic struct {
+	Category string `json:"category"`
+	Posn     string `json:"posn"`
+	End      string `json:"end"`
+	Message  string `json:"message"`
+}
+
+// The SARIF 2.1.0 log, with the properties the report uses.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn,omitempty"`
+		EndLine     int `json:"endLine,omitempty"`
+		EndColumn   int `json:"endColumn,omitempty"`
+	}
+)
+
+// SARIF converts the -json output of the analyzers, read from r, to a SARIF
+// 2.1.0 log written to w, for GitHub code scanning and other SARIF consumers.
+// The categories are the rules of the results, and the regions of the
+// findings inside queries are the exact ranges of the query text. Paths are
+// written relative to the current directory.
+func SARIF(r io.Reader, w io.Writer) error {
+	diagnostics, err := readJSONDiagnostics(r)
+	if err != nil {
+		return err
+	}
+	cwd, _ := os.Getwd()
+	results := []sarifResult{}
+	for _, d := range diagnostics {
+		results = append(results, sarifResultOf(d, cwd))
+	}
+	var rules []sarifRule
+	for _, c := range categories {
+		rules = append(rules, sarifRule{ID: c, ShortDescription: sarifMessage{categoryDocs[c]}})
+	}
+	log := sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "sqlargs", InformationURI: "https://github.com/agnivade/sqlargs", Rules: rules}},
+			Results: results,
+		}},
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// readJSONDiagnostics returns the diagnostics of the -json output of the
+// analyzers read from r, sorted by position.
+func readJSONDiagnostics(r io.Reader) ([]jsonDiagnostic, error) {
+	var diagnostics []jsonDiagnostic
+	dec := json.NewDecoder(r)
+	for {
+		// The output is an object per package for go vet, and one for all
+		// of them otherwise, mapping packages to analyzers to diagnostics.
+		var tree map[string]map[string]json.RawMessage
+		if err := dec.Decode(&tree); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading the -json output: %v", err)
+		}
+		for _, analyzers := range tree {
+			for _, raw := range analyzers {
+				var ds []jsonDiagnostic
+				// The analyzers which failed have an error instead.
+				if json.Unmarshal(raw, &ds) != nil {
+					continue
+				}
+				diagnostics = append(diagnostics, ds...)
+			}
+		}
+	}
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i].position(), diagnostics[j].position()
+		if a.file != b.file {
+			return a.file < b.file
+		}
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.col < b.col
+	})
+	return diagnostics, nil
+}
+
+// jsonPosition is a position of the -json output.
+type jsonPosition struct {
+	file      string
+	line, col int
+}
+
+func (d jsonDiagnostic) position() jsonPosition {
+	file, line, col := splitPosn(d.Posn)
+	return jsonPosition{file, line, col}
+}
+
+// end returns the end of d, or false if it has none.
+func (d jsonDiagnostic) end() (jsonPosition, bool) {
+	file, line, col := splitPosn(d.End)
+	return jsonPosition{file, line, col}, file != "" && line > 0
+}
+
+// severity returns the severity of d, set with -severity, and its message
+// without it.
+func (d jsonDiagnostic) severity() (string, string) {
+	// The severities of -severity are prefixes of the messages.
+	for _, severity := range []string{sevWarning, sevInfo} {
+		if m, ok := strings.CutPrefix(d.Message, severity+": "); ok {
+			return severity, m
+		}
+	}
+	return sevError, d.Message
+}
+
+// rule returns the category of d.
+func (d jsonDiagnostic) rule() string {
+	if d.Category == "" {
+		return catSyntax
+	}
+	return d.Category
+}
+
+// relPath returns file relative to cwd, if it is inside it, with slashes.
+func relPath(cwd, file string) string {
+	if rel, err := filepath.Rel(cwd, file); err == nil && !strings.HasPrefix(rel, "..") {
+		file = rel
+	}
+	return filepath.ToSlash(file)
+}
+
+// sarifLevels are the SARIF levels of the severities.
+var sarifLevels = map[string]string{sevError: "error", sevWarning: "warning", sevInfo: "note"}
+
+// sarifResultOf returns the SARIF result of d, with its path relative to cwd.
+func sarifResultOf(d jsonDiagnostic, cwd string) sarifResult {
+	severity, message := d.severity()
+	pos := d.position()
+	region := sarifRegion{StartLine: pos.line, StartColumn: pos.col}
+	if end, ok := d.end(); ok {
+		region.EndLine, region.EndColumn = end.line, end.col
+	}
+	return sarifResult{
+		RuleID:  d.rule(),
+		Level:   sarifLevels[severity],
+		Message: sarifMessage{message},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: relPath(cwd, pos.file)},
+			Region:           region,
+		}}},
+	}
+}
+
+// splitPosn splits a position written as file:line:column. The column, or the
+// line and the column, may be missing.
+func splitPosn(posn string) (file string, line, col int) {
+	file = posn
+	var nums []int
+	for i := 0; i < 2; i++ {
+		colon := strings.LastIndexByte(file, ':')
+		if colon < 0 {
+			break
+		}
+		n, err := strconv.Atoi(file[colon+1:])
+		if err != nil {
+			break
+		}
+		nums = append([]int{n}, nums...)
+		file = file[:colon]
+	}
+	switch len(nums) {
+	case 2:
+		line, col = nums[0], nums[1]
+	case 1:
+		line = nums[0]
+	}
+	return file, line, col
+}
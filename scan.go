@@ -0,0 +1,241 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// resultColumns returns the no. of columns of the rows returned by query. It
+// returns false if it cannot be determined statically, like for SELECT *.
+func resultColumns(query string, d *dialect) (int, bool) {
+	items, ok := resultList(query, d)
+	if !ok || hasStar(items) {
+		return 0, false
+	}
+	return len(items), true
+}
+
+// resultNames returns the lexemes naming the columns returned by query, which
+// are either their alias or the column they select. The columns whose name is
+// up to the database, like expressions without an alias, are left out. It
+// returns false if the columns are not known.
+func resultNames(query string, d *dialect) ([]lexeme, bool) {
+	items, ok := resultList(query, d)
+	if !ok || hasStar(items) {
+		return nil, false
+	}
+	var names []lexeme
+	for _, item := range items {
+		switch n := len(item); {
+		case n >= 2 && strings.EqualFold(item[n-2].text, "AS"):
+			names = append(names, item[n-1])
+		case bareColumn(item) != "":
+			names = append(names, item[n-1])
+		}
+	}
+	return names, true
+}
+
+// resultList returns the items of the select list, or RETURNING clause, of
+// query.
+func resultList(query string, d *dialect) ([][]lexeme, bool) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	var items [][]lexeme
+	ok := false
+	switch mainKeyword(lexemes) {
+	case "SELECT":
+		items, ok = selectList(lexemes)
+	case "INSERT", "UPDATE", "DELETE":
+		items, ok = returningList(lexemes)
+	}
+	return items, ok
+}
+
+// returningList returns the items of the top level RETURNING clause in
+// lexemes, each item being its lexemes. It returns false if there is none.
+func returningList(lexemes []lexeme) ([][]lexeme, bool) {
+	depth := 0
+	start := -1
+	var items [][]lexeme
+	for i, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth != 0:
+		case start < 0:
+			if l.kind == lexWord && strings.EqualFold(l.text, "RETURNING") {
+				start = i + 1
+			}
+		case l.text == ",":
+			items = append(items, lexemes[start:i])
+			start = i + 1
+		case l.text == ";", l.kind == lexWord && strings.EqualFold(l.text, "INTO"):
+			return append(items, lexemes[start:i]), true
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	return append(items, lexemes[start:]), true
+}
+
+// isSelect reports whether query is a SELECT returning rows. SELECT ... INTO,
+// which creates a table in Postgres and sets variables in MySQL, is not
+// counted.
+func isSelect(query string, d *dialect) bool {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	if mainKeyword(lexemes) != "SELECT" {
+		return false
+	}
+	depth := 0
+	for _, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth == 0 && l.kind == lexWord && strings.EqualFold(l.text, "INTO"):
+			return false
+		}
+	}
+	return true
+}
+
+// writeWithoutRows returns the keyword of query if it is an INSERT, UPDATE or
+// DELETE which returns no rows, as it has no RETURNING or OUTPUT clause.
+func writeWithoutRows(query string, d *dialect) (string, bool) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	keyword := mainKeyword(lexemes)
+	if keyword != "INSERT" && keyword != "UPDATE" && keyword != "DELETE" {
+		return "", false
+	}
+	depth := 0
+	for _, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth == 0 && l.kind == lexWord && (strings.EqualFold(l.text, "RETURNING") || strings.EqualFold(l.text, "OUTPUT")):
+			return "", false
+		}
+	}
+	return keyword, true
+}
+
+// mainKeyword returns the first top level keyword of the first statement in
+// lexemes which is not part of a WITH clause, in upper case.
+func mainKeyword(lexemes []lexeme) string {
+	depth := 0
+	for _, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth == 0 && l.text == ";":
+			return ""
+		case depth != 0 || l.kind != lexWord:
+		default:
+			switch keyword := strings.ToUpper(l.text); keyword {
+			case "SELECT", "INSERT", "UPDATE", "DELETE", "MERGE", "VALUES", "CALL":
+				return keyword
+			}
+		}
+	}
+	return ""
+}
+
+// scanCalls returns the calls of the method scan, like Scan, made on the result
+// of call, given the stack of nodes enclosing it. These are either chained,
+// like in db.QueryRow(...).Scan(&a, &b), or made on the variable the result is
+// assigned to, like in rows, err := db.Query(...) followed by rows.Scan(&a)
+// in the enclosing function body.
+func scanCalls(call *ast.CallExpr, scan string, stack []ast.Node, body *ast.BlockStmt, info *types.Info) []*ast.CallExpr {
+	if len(stack) < 3 {
+		return nil
+	}
+	switch parent := stack[len(stack)-2].(type) {
+	case *ast.SelectorExpr:
+		c, ok := stack[len(stack)-3].(*ast.CallExpr)
+		if !ok || parent.X != call || parent.Sel.Name != scan || c.Fun != parent {
+			return nil
+		}
+		return []*ast.CallExpr{c}
+	case *ast.AssignStmt:
+		if body == nil || len(parent.Rhs) != 1 || parent.Rhs[0] != call || len(parent.Lhs) == 0 {
+			return nil
+		}
+		ident, ok := parent.Lhs[0].(*ast.Ident)
+		if !ok {
+			return nil
+		}
+		obj := info.ObjectOf(ident)
+		if obj == nil {
+			return nil
+		}
+		return scansOf(obj, scan, parent, body, info)
+	}
+	return nil
+}
+
+// scansOf returns the calls of the method scan made on the variable obj in body
+// after it is assigned by assign, and before it is assigned again.
+func scansOf(obj types.Object, scan string, assign *ast.AssignStmt, body *ast.BlockStmt, info *types.Info) []*ast.CallExpr {
+	end := assignedUntil(obj, assign, body, info)
+	var scans []*ast.CallExpr
+	ast.Inspect(body, func(n ast.Node) bool {
+		c, ok := n.(*ast.CallExpr)
+		if !ok || c.Pos() <= assign.End() || c.Pos() >= end {
+			return true
+		}
+		sel, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != scan {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok && info.ObjectOf(ident) == obj {
+			scans = append(scans, c)
+		}
+		return true
+	})
+	return scans
+}
+
+// checkScan reports the Scans of the rows returned by call whose no. of
+// destinations does not match the no. of columns selected by query.
+func checkScan(query string, d *dialect, call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	scans := scanCalls(call, "Scan", stack, enclosingBody(stack), pass.TypesInfo)
+	if len(scans) == 0 {
+		return
+	}
+	numCols, ok := resultColumns(query, d)
+	if !ok {
+		return
+	}
+	for _, scan := range scans {
+		if scan.Ellipsis.IsValid() || numCols == len(scan.Args) {
+			continue
+		}
+		reportf(pass, catArity, scan.Lparen, "No. of Scan destinations (%d) not equal to no. of columns (%d)", len(scan.Args), numCols)
+	}
+}
@@ -0,0 +1,417 @@
+package sqlargs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// schema is the set of tables created by a DDL file.
+type schema struct {
+	// tables maps the normalized names of the tables to their definition.
+	tables map[string]*table
+}
+
+// table is a table of a schema.
+type table struct {
+	name string
+	// columns maps the normalized names of the columns to their definition.
+	columns map[string]*column
+}
+
+// column is a column of a table.
+type column struct {
+	name string
+	// typ is the lower cased SQL type of the column, like varchar(10).
+	typ string
+	// notNull is set for NOT NULL and PRIMARY KEY columns.
+	notNull bool
+	// hasDefault is set for columns which get a value when they are not
+	// inserted, like columns with a DEFAULT, or serial and generated ones.
+	hasDefault bool
+}
+
+var (
+	schemasMu sync.Mutex
+	// schemas caches the loaded schemas by path and dialect name, as the
+	// analyzer runs on many packages.
+	schemas = make(map[string]*schema)
+)
+
+// loadSchema returns the schema created by the DDL files at paths, written in
+// dialect d. If a path is a directory, the schema is built by replaying the up
+// migrations in it.
+func loadSchema(paths []string, d *dialect) (*schema, error) {
+	key := strings.Join(paths, "\x00") + "\x00" + d.name
+	schemasMu.Lock()
+	defer schemasMu.Unlock()
+	if s, ok := schemas[key]; ok {
+		return s, nil
+	}
+	var ddls []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading schema: %v", err)
+		}
+		if info.IsDir() {
+			var migrations []string
+			migrations, err = readMigrations(path)
+			ddls = append(ddls, migrations...)
+		} else {
+			var ddl []byte
+			ddl, err = os.ReadFile(path)
+			ddls = append(ddls, string(ddl))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading schema: %v", err)
+		}
+	}
+	// The parsed schema is cached by its content, as it is loaded again by
+	// each go vet process.
+	contentKey := cacheKey(append([]string{d.name}, ddls...)...)
+	s, ok := readCachedSchema(contentKey)
+	if !ok {
+		s = newSchema()
+		for _, ddl := range ddls {
+			s.apply(ddl, d)
+		}
+		writeCachedSchema(contentKey, s)
+	}
+	schemas[key] = s
+	return s, nil
+}
+
+func newSchema() *schema {
+	return &schema{tables: make(map[string]*table)}
+}
+
+// normalizeIdent returns the name under which the identifier lexeme l is
+// looked up. Unquoted identifiers are case insensitive.
+func normalizeIdent(l lexeme) string {
+	if l.kind == lexQuotedIdent {
+		return l.text[1 : len(l.text)-1]
+	}
+	return strings.ToLower(l.text)
+}
+
+// lookup returns the table named by the possibly schema qualified name whose
+// last lexeme is l.
+func (s *schema) lookup(l lexeme) *table {
+	return s.tables[normalizeIdent(l)]
+}
+
+// apply applies the CREATE TABLE, ALTER TABLE and DROP TABLE statements of
+// ddl to s. Statements it does not understand are ignored.
+func (s *schema) apply(ddl string, d *dialect) {
+	for _, stmt := range statements(ddl, d) {
+		if len(stmt) < 3 {
+			continue
+		}
+		switch strings.ToUpper(stmt[0].text) {
+		case "CREATE":
+			s.create(stmt)
+		case "ALTER":
+			s.alter(stmt)
+		case "DROP":
+			s.drop(stmt)
+		}
+	}
+}
+
+// create applies a CREATE statement to s.
+func (s *schema) create(stmt []lexeme) {
+	i := 1
+	for i < len(stmt) && stmt[i].kind == lexWord && !strings.EqualFold(stmt[i].text, "TABLE") {
+		// Modifiers like TEMPORARY or UNLOGGED.
+		i++
+	}
+	if i == len(stmt) {
+		return
+	}
+	i = skipWords(stmt, i+1, "IF", "NOT", "EXISTS")
+	name, i := qualifiedName(stmt, i)
+	if name == nil || i == len(stmt) || stmt[i].text != "(" {
+		return
+	}
+	t := &table{name: name.text, columns: make(map[string]*column)}
+	for _, def := range splitList(stmt, i) {
+		if c := columnDef(def); c != nil {
+			t.columns[normalizeIdent(def[0])] = c
+		}
+	}
+	s.tables[normalizeIdent(*name)] = t
+}
+
+// alter applies an ALTER TABLE statement to s.
+func (s *schema) alter(stmt []lexeme) {
+	if !strings.EqualFold(stmt[1].text, "TABLE") {
+		return
+	}
+	i := skipWords(stmt, 2, "IF", "EXISTS")
+	i = skipWords(stmt, i, "ONLY")
+	name, i := qualifiedName(stmt, i)
+	if name == nil {
+		return
+	}
+	t := s.lookup(*name)
+	if t == nil {
+		return
+	}
+	// Split the comma separated actions.
+	var actions [][]lexeme
+	depth, start := 0, i
+	for j := i; j <= len(stmt); j++ {
+		if j == len(stmt) || depth == 0 && stmt[j].text == "," {
+			actions = append(actions, stmt[start:j])
+			start = j + 1
+			continue
+		}
+		switch stmt[j].text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+	}
+	for _, action := range actions {
+		s.alterAction(t, *name, action)
+	}
+}
+
+// alterAction applies an action of an ALTER TABLE statement to table t, named
+// by the lexeme name.
+func (s *schema) alterAction(t *table, name lexeme, action []lexeme) {
+	if len(action) < 2 {
+		return
+	}
+	switch strings.ToUpper(action[0].text) {
+	case "ADD":
+		j := skipWords(action, 1, "COLUMN")
+		j = skipWords(action, j, "IF", "NOT", "EXISTS")
+		if def := action[j:]; len(def) > 0 {
+			if c := columnDef(def); c != nil {
+				t.columns[normalizeIdent(def[0])] = c
+			}
+		}
+	case "DROP":
+		j := skipWords(action, 1, "COLUMN")
+		j = skipWords(action, j, "IF", "EXISTS")
+		if j < len(action) && !tableConstraints[strings.ToUpper(action[j].text)] {
+			delete(t.columns, normalizeIdent(action[j]))
+		}
+	case "RENAME":
+		j := skipWords(action, 1, "COLUMN")
+		if j+1 < len(action) && strings.EqualFold(action[j].text, "TO") {
+			// RENAME TO renames the table itself.
+			delete(s.tables, normalizeIdent(name))
+			t.name = action[j+1].text
+			s.tables[normalizeIdent(action[j+1])] = t
+			return
+		}
+		if j+2 < len(action) && strings.EqualFold(action[j+1].text, "TO") {
+			if c := t.columns[normalizeIdent(action[j])]; c != nil {
+				delete(t.columns, normalizeIdent(action[j]))
+				c.name = action[j+2].text
+				t.columns[normalizeIdent(action[j+2])] = c
+			}
+		}
+	case "ALTER":
+		j := skipWords(action, 1, "COLUMN")
+		if j+2 >= len(action) {
+			return
+		}
+		c := t.columns[normalizeIdent(action[j])]
+		if c == nil {
+			return
+		}
+		switch op := strings.ToUpper(action[j+1].text) + " " + strings.ToUpper(action[j+2].text); {
+		case op == "SET NOT":
+			c.notNull = true
+		case op == "DROP NOT":
+			c.notNull = false
+		case op == "SET DEFAULT":
+			c.hasDefault = true
+		case op == "DROP DEFAULT":
+			c.hasDefault = false
+		case strings.HasPrefix(op, "TYPE "), strings.HasPrefix(op, "SET DATA"):
+			rest := action[j+2:]
+			if strings.EqualFold(action[j+1].text, "SET") {
+				rest = action[j+4:]
+			}
+			var typ []string
+			for _, l := range rest {
+				if l.kind == lexWord && (strings.EqualFold(l.text, "USING") || strings.EqualFold(l.text, "COLLATE")) {
+					break
+				}
+				typ = append(typ, strings.ToLower(l.text))
+			}
+			c.typ = joinType(typ)
+		}
+	}
+}
+
+// drop applies a DROP TABLE statement to s.
+func (s *schema) drop(stmt []lexeme) {
+	if !strings.EqualFold(stmt[1].text, "TABLE") {
+		return
+	}
+	i := skipWords(stmt, 2, "IF", "EXISTS")
+	for i < len(stmt) {
+		name, next := qualifiedName(stmt, i)
+		if name == nil {
+			return
+		}
+		delete(s.tables, normalizeIdent(*name))
+		if next == len(stmt) || stmt[next].text != "," {
+			return
+		}
+		i = next + 1
+	}
+}
+
+// statements splits the lexemes of query into statements, dropping comments
+// and the semicolons separating them.
+func statements(query string, d *dialect) [][]lexeme {
+	var stmts [][]lexeme
+	var stmt []lexeme
+	for _, l := range lex(query, d) {
+		switch {
+		case l.kind == lexComment:
+		case l.text == ";":
+			if len(stmt) > 0 {
+				stmts = append(stmts, stmt)
+			}
+			stmt = nil
+		default:
+			stmt = append(stmt, l)
+		}
+	}
+	if len(stmt) > 0 {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// skipWords returns the index of the first lexeme from i which is not one of
+// the keywords words, in order.
+func skipWords(lexemes []lexeme, i int, words ...string) int {
+	for _, w := range words {
+		if i < len(lexemes) && strings.EqualFold(lexemes[i].text, w) {
+			i++
+		}
+	}
+	return i
+}
+
+// qualifiedName returns the last lexeme of the possibly qualified name, like
+// public.users, starting at lexemes[i], along with the index just after it. It
+// returns nil if there is no name at i.
+func qualifiedName(lexemes []lexeme, i int) (*lexeme, int) {
+	var name *lexeme
+	for i < len(lexemes) && (lexemes[i].kind == lexWord || lexemes[i].kind == lexQuotedIdent) {
+		name = &lexemes[i]
+		i++
+		if i == len(lexemes) || lexemes[i].text != "." {
+			break
+		}
+		i++
+	}
+	return name, i
+}
+
+// splitList returns the comma separated items of the parenthesized list
+// starting at lexemes[i].
+func splitList(lexemes []lexeme, i int) [][]lexeme {
+	var items [][]lexeme
+	depth := 0
+	start := i + 1
+	for ; i < len(lexemes); i++ {
+		switch lexemes[i].text {
+		case "(":
+			depth++
+		case ")":
+			if depth--; depth == 0 {
+				if i > start {
+					items = append(items, lexemes[start:i])
+				}
+				return items
+			}
+		case ",":
+			if depth == 1 {
+				items = append(items, lexemes[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return items
+}
+
+// tableConstraints are the keywords starting a table constraint rather than a
+// column definition.
+var tableConstraints = keywordSet("CHECK", "CONSTRAINT", "EXCLUDE", "FOREIGN", "FULLTEXT",
+	"INDEX", "KEY", "LIKE", "PRIMARY", "SPATIAL", "UNIQUE")
+
+// columnOptions are the keywords which end the type of a column definition.
+var columnOptions = keywordSet("AUTO_INCREMENT", "AUTOINCREMENT", "CHECK", "COLLATE", "COMMENT",
+	"CONSTRAINT", "DEFAULT", "GENERATED", "IDENTITY", "NOT", "NULL", "PRIMARY", "REFERENCES", "UNIQUE")
+
+// serialTypes are the types of columns which get a value from a sequence.
+var serialTypes = keywordSet("BIGSERIAL", "SERIAL", "SERIAL2", "SERIAL4", "SERIAL8", "SMALLSERIAL")
+
+// columnDef returns the column defined by the lexemes of an item of a CREATE
+// TABLE, or nil if it is a table constraint.
+func columnDef(def []lexeme) *column {
+	if len(def) == 0 || def[0].kind == lexWord && tableConstraints[strings.ToUpper(def[0].text)] {
+		return nil
+	}
+	if def[0].kind != lexWord && def[0].kind != lexQuotedIdent {
+		return nil
+	}
+	c := &column{name: def[0].text}
+	i := 1
+	var typ []string
+	depth := 0
+	for ; i < len(def); i++ {
+		l := def[i]
+		if depth == 0 && l.kind == lexWord && columnOptions[strings.ToUpper(l.text)] {
+			break
+		}
+		switch l.text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		}
+		typ = append(typ, strings.ToLower(l.text))
+	}
+	c.typ = joinType(typ)
+	c.hasDefault = serialTypes[strings.ToUpper(c.typ)]
+	for ; i < len(def); i++ {
+		switch strings.ToUpper(def[i].text) {
+		case "NOT":
+			if i+1 < len(def) && strings.EqualFold(def[i+1].text, "NULL") {
+				c.notNull = true
+			}
+		case "PRIMARY":
+			c.notNull = true
+		case "DEFAULT", "GENERATED", "IDENTITY", "AUTO_INCREMENT", "AUTOINCREMENT":
+			c.hasDefault = true
+		}
+	}
+	return c
+}
+
+// joinType joins the lexemes of a type, like double precision or
+// varchar(10), back into its text.
+func joinType(parts []string) string {
+	var typ strings.Builder
+	for i, p := range parts {
+		if i > 0 && p != "(" && p != ")" && p != "," && p != "[" && p != "]" && parts[i-1] != "(" && parts[i-1] != "," && parts[i-1] != "[" {
+			typ.WriteByte(' ')
+		}
+		typ.WriteString(p)
+	}
+	return typ.String()
+}
@@ -0,0 +1,464 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// tableRef is a reference to a table in a statement.
+type tableRef struct {
+	// name is the last lexeme of the table name.
+	name lexeme
+	// alias is the normalized alias of the table, or its name.
+	alias string
+	// keyword is the upper cased keyword preceding the reference, like FROM.
+	keyword string
+}
+
+// checkSchema reports the tables and columns referenced by query which do not
+// exist in s. Columns are checked when they are qualified, like u.name, in the
+// column list of an INSERT, as the target of a SET assignment, and in the
+// select list of a statement using a single table. If u is not nil, the
+// columns used by query and the unknown references are recorded in it.
+func checkSchema(query string, d *dialect, s *schema, u *usage, call *ast.CallExpr, pass *analysis.Pass) {
+	reportUnknown := func(l lexeme, format string, args ...interface{}) {
+		reportQuery(pass, call, catSchema, l.pos, len(l.text), format, args...)
+		if u != nil {
+			u.unknown(pass.Fset.Position(call.Lparen), fmt.Sprintf(format, args...))
+		}
+	}
+	// n is the no. of ? placeholders of the previous statements.
+	n := 0
+	for _, stmt := range statements(query, d) {
+		ctes := cteNames(stmt)
+		refs := tableRefs(stmt)
+		tables := make(map[string]*table)
+		var known []*table
+		for _, ref := range refs {
+			if ctes[normalizeIdent(ref.name)] {
+				continue
+			}
+			t := s.lookup(ref.name)
+			if t == nil {
+				reportUnknown(ref.name, "Unknown table %s at offset %d", ref.name.text, ref.name.pos)
+				continue
+			}
+			tables[ref.alias] = t
+			known = append(known, t)
+		}
+		report := func(t *table, l lexeme) {
+			if l.kind == lexWord || l.kind == lexQuotedIdent {
+				if t.columns[normalizeIdent(l)] == nil {
+					reportUnknown(l, "Unknown column %s of table %s at offset %d", l.text, t.name, l.pos)
+				}
+			}
+		}
+		// Qualified columns.
+		for i := 0; i+2 < len(stmt); i++ {
+			if stmt[i+1].text != "." || stmt[i+2].text == "*" || i+3 < len(stmt) && stmt[i+3].text == "." {
+				continue
+			}
+			if i > 0 && stmt[i-1].text == "." {
+				continue
+			}
+			if t, ok := tables[normalizeIdent(stmt[i])]; ok && stmt[i].kind != lexPlaceholder {
+				report(t, stmt[i+2])
+			}
+		}
+		target := writeTarget(refs, tables)
+		if target != nil {
+			for _, l := range insertColumnList(stmt) {
+				report(target, l)
+			}
+			if missing := missingColumns(stmt, target); len(missing) > 0 {
+				reportf(pass, catSchema, call.Lparen, "INSERT into %s does not set the NOT NULL columns without a default: %s", target.name, strings.Join(missing, ", "))
+			}
+			for _, l := range setTargets(stmt) {
+				report(target, l)
+			}
+		}
+		if len(refs) == 1 && len(known) == 1 && mainKeyword(stmt) == "SELECT" {
+			items, _ := selectList(stmt)
+			for _, item := range items {
+				if column := bareColumn(item); column != "" && !strings.Contains(column, ".") {
+					report(known[0], item[0])
+				}
+			}
+		}
+		if u != nil {
+			u.use(stmt, known)
+		}
+		n = checkColumnTypes(stmt, n, tables, known, target, call, pass)
+	}
+}
+
+// comparisonOperators are the operators whose operands are expected to have
+// the same type.
+var comparisonOperators = map[string]bool{"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true}
+
+// textMismatches are the kinds of Go args which are unlikely to be meant for
+// a text column.
+var textMismatches = map[goKind]bool{kindInt: true, kindFloat: true, kindBool: true, kindTime: true}
+
+// columnMismatches maps the SQL types of columns to the kinds of Go args which
+// are unlikely to be meant for them. Types are keyed by their first word.
+var columnMismatches = func() map[string]map[goKind]bool {
+	m := map[string]map[goKind]bool{
+		"serial": integerCast, "bigserial": integerCast, "smallserial": integerCast,
+		"text": textMismatches, "varchar": textMismatches, "char": textMismatches,
+		"character": textMismatches, "citext": textMismatches, "nvarchar": textMismatches,
+		"datetime": timestampCast, "datetime2": timestampCast,
+	}
+	for typ, kinds := range castMismatches {
+		m[typ] = kinds
+	}
+	return m
+}()
+
+// checkColumnTypes reports the args of call whose Go type is unlikely to match
+// the type of the column they are compared to, assigned with SET, or inserted
+// into. n is the no. of ? placeholders of the previous statements, and the
+// no. including stmt is returned.
+func checkColumnTypes(stmt []lexeme, n int, tables map[string]*table, known []*table, target *table, call *ast.CallExpr, pass *analysis.Pass) int {
+	// args maps the indices of the placeholders of stmt to their arg.
+	args := make(map[int]ast.Expr)
+	for i, l := range stmt {
+		if l.kind != lexPlaceholder {
+			continue
+		}
+		var index int
+		if l.text == "?" {
+			n++
+			index = n
+		} else {
+			index, _ = strconv.Atoi(l.text[1:])
+		}
+		if index >= 1 && index < len(call.Args) {
+			args[i] = call.Args[index]
+		}
+	}
+	if len(args) == 0 || call.Ellipsis.IsValid() {
+		return n
+	}
+	check := func(arg ast.Expr, c *column) {
+		if c == nil {
+			return
+		}
+		typ := pass.TypesInfo.TypeOf(arg)
+		if typ == nil || !columnMismatches[columnTypeKey(c.typ)][goKindOf(typ)] {
+			return
+		}
+		reportf(pass, catSchema, arg.Pos(), "Arg of type %s is used for column %s of type %s", types.TypeString(typ, types.RelativeTo(pass.Pkg)), c.name, c.typ)
+	}
+	// set holds the offsets of the columns assigned by SET.
+	set := make(map[int]bool)
+	for _, l := range setTargets(stmt) {
+		set[l.pos] = true
+	}
+	for i, arg := range args {
+		if i > 1 && comparisonOperators[stmt[i-1].text] {
+			// c = $1, or the SET assignment c = $1.
+			if set[stmt[i-2].pos] && target != nil {
+				check(arg, target.columns[normalizeIdent(stmt[i-2])])
+			} else {
+				check(arg, columnBefore(stmt, i-1, tables, known))
+			}
+		}
+		if i+2 < len(stmt) && comparisonOperators[stmt[i+1].text] {
+			// $1 = c
+			check(arg, columnAfter(stmt, i+2, tables, known))
+		}
+	}
+	if target != nil {
+		columns := insertColumnList(stmt)
+		for i := range stmt {
+			if !isValuesKeyword(stmt, i) {
+				continue
+			}
+			for j := i + 1; j < len(stmt) && stmt[j].text == "("; {
+				for k, item := range splitList(stmt, j) {
+					if len(item) != 1 || k >= len(columns) {
+						continue
+					}
+					for idx, arg := range args {
+						if stmt[idx].pos == item[0].pos {
+							check(arg, target.columns[normalizeIdent(columns[k])])
+						}
+					}
+				}
+				_, j = listLen(stmt, j)
+				if j >= len(stmt) || stmt[j].text != "," {
+					break
+				}
+				j++
+			}
+			break
+		}
+	}
+	return n
+}
+
+// columnBefore returns the column the expression ending at stmt[i-1] refers
+// to, or nil if it is not a known column.
+func columnBefore(stmt []lexeme, i int, tables map[string]*table, known []*table) *column {
+	if i < 1 {
+		return nil
+	}
+	name := stmt[i-1]
+	if i >= 3 && stmt[i-2].text == "." {
+		return qualifiedColumn(stmt[i-3], name, tables)
+	}
+	return bareColumnOf(name, known)
+}
+
+// columnAfter returns the column the expression starting at stmt[i] refers
+// to, or nil if it is not a known column.
+func columnAfter(stmt []lexeme, i int, tables map[string]*table, known []*table) *column {
+	if i+2 < len(stmt) && stmt[i+1].text == "." {
+		if i+3 < len(stmt) && (stmt[i+3].text == "." || stmt[i+3].text == "(") {
+			return nil
+		}
+		return qualifiedColumn(stmt[i], stmt[i+2], tables)
+	}
+	if i+1 < len(stmt) && (stmt[i+1].text == "(" || stmt[i+1].text == ".") {
+		// A function call.
+		return nil
+	}
+	return bareColumnOf(stmt[i], known)
+}
+
+// qualifiedColumn returns the column name of the table aliased alias.
+func qualifiedColumn(alias, name lexeme, tables map[string]*table) *column {
+	if t, ok := tables[normalizeIdent(alias)]; ok && t != nil {
+		return t.columns[normalizeIdent(name)]
+	}
+	return nil
+}
+
+// bareColumnOf returns the unqualified column name, if exactly one of the
+// known tables has it.
+func bareColumnOf(name lexeme, known []*table) *column {
+	if name.kind != lexWord && name.kind != lexQuotedIdent {
+		return nil
+	}
+	var found *column
+	for _, t := range known {
+		if c := t.columns[normalizeIdent(name)]; c != nil {
+			if found != nil && found != c {
+				return nil
+			}
+			found = c
+		}
+	}
+	return found
+}
+
+// columnTypeKey returns the first word of the SQL type typ, like varchar for
+// varchar(255) or double for double precision.
+func columnTypeKey(typ string) string {
+	if i := strings.IndexAny(typ, "( "); i >= 0 {
+		return typ[:i]
+	}
+	return typ
+}
+
+// cteNames returns the normalized names of the CTEs of a statement.
+func cteNames(stmt []lexeme) map[string]bool {
+	names := make(map[string]bool)
+	for i := 1; i+2 < len(stmt); i++ {
+		prev := strings.ToUpper(stmt[i-1].text)
+		if prev != "WITH" && prev != "RECURSIVE" && prev != "," {
+			continue
+		}
+		j := i + 1
+		if stmt[j].text == "(" {
+			// A column list.
+			_, j = listLen(stmt, j)
+		}
+		if j+1 < len(stmt) && strings.EqualFold(stmt[j].text, "AS") && (stmt[j+1].text == "(" || j+2 < len(stmt) && stmt[j+2].text == "(") {
+			names[normalizeIdent(stmt[i])] = true
+		}
+	}
+	return names
+}
+
+// tableRefKeywords are the keywords which are followed by a table reference.
+var tableRefKeywords = keywordSet("FROM", "INTO", "JOIN", "UPDATE")
+
+// aliasStops are the keywords which can follow a table reference, and so are
+// not its alias.
+var aliasStops = keywordSet("CROSS", "DEFAULT", "DO", "EXCEPT", "FETCH", "FOR", "FULL", "GROUP",
+	"HAVING", "INNER", "INTERSECT", "JOIN", "LATERAL", "LEFT", "LIMIT", "NATURAL", "OFFSET", "ON",
+	"ORDER", "OUTER", "OUTPUT", "OVERRIDING", "RETURNING", "RIGHT", "SELECT", "SET", "UNION", "USING",
+	"VALUES", "WHERE", "WINDOW")
+
+// tableRefs returns the tables referenced by a statement after FROM, JOIN,
+// INTO and UPDATE, including the comma separated ones of a FROM list. FROM
+// inside function calls, like EXTRACT(YEAR FROM c), is not a table reference.
+func tableRefs(stmt []lexeme) []tableRef {
+	var refs []tableRef
+	// query tracks for each level of parentheses whether it holds a query.
+	query := []bool{true}
+	isSelectInto := mainKeyword(stmt) == "SELECT"
+	for i := 0; i < len(stmt); i++ {
+		l := stmt[i]
+		switch {
+		case l.text == "(":
+			next := ""
+			if i+1 < len(stmt) {
+				next = strings.ToUpper(stmt[i+1].text)
+			}
+			query = append(query, next == "SELECT" || next == "WITH" || next == "INSERT" || next == "UPDATE" || next == "DELETE")
+			continue
+		case l.text == ")":
+			if len(query) > 1 {
+				query = query[:len(query)-1]
+			}
+			continue
+		case l.kind != lexWord || !query[len(query)-1]:
+			continue
+		}
+		keyword := strings.ToUpper(l.text)
+		if !tableRefKeywords[keyword] || keyword == "INTO" && isSelectInto {
+			continue
+		}
+		if i > 0 {
+			// DO UPDATE, FOR UPDATE and IS DISTINCT FROM.
+			switch strings.ToUpper(stmt[i-1].text) {
+			case "DO", "FOR", "KEY", "DISTINCT":
+				continue
+			}
+		}
+		for {
+			ref, next, ok := tableRefAt(stmt, i+1, keyword)
+			if !ok {
+				break
+			}
+			refs = append(refs, ref)
+			i = next - 1
+			if keyword != "FROM" || next >= len(stmt) || stmt[next].text != "," {
+				break
+			}
+			i = next
+		}
+	}
+	return refs
+}
+
+// tableRefAt returns the table reference starting at stmt[i], along with the
+// index just after it. It returns false if there is none, like for a subquery
+// or a function call.
+func tableRefAt(stmt []lexeme, i int, keyword string) (tableRef, int, bool) {
+	i = skipWords(stmt, i, "ONLY")
+	name, i := qualifiedName(stmt, i)
+	if name == nil || i < len(stmt) && stmt[i].text == "(" && keyword != "INTO" {
+		return tableRef{}, i, false
+	}
+	if name.kind == lexWord && (aliasStops[strings.ToUpper(name.text)] || strings.EqualFold(name.text, "LATERAL")) {
+		return tableRef{}, i, false
+	}
+	ref := tableRef{name: *name, alias: normalizeIdent(*name), keyword: keyword}
+	i = skipWords(stmt, i, "AS")
+	if i < len(stmt) && (stmt[i].kind == lexWord && !aliasStops[strings.ToUpper(stmt[i].text)] || stmt[i].kind == lexQuotedIdent) {
+		ref.alias = normalizeIdent(stmt[i])
+		i++
+	}
+	return ref, i, true
+}
+
+// writeTarget returns the table written by an INSERT or UPDATE statement, or
+// nil if it is not known.
+func writeTarget(refs []tableRef, tables map[string]*table) *table {
+	for _, ref := range refs {
+		if ref.keyword == "INTO" || ref.keyword == "UPDATE" {
+			return tables[ref.alias]
+		}
+	}
+	return nil
+}
+
+// insertColumnList returns the names in the column list of an INSERT
+// statement.
+func insertColumnList(stmt []lexeme) []lexeme {
+	for i, l := range stmt {
+		if l.kind != lexWord || !strings.EqualFold(l.text, "INSERT") {
+			continue
+		}
+		start := insertTableEnd(stmt, i+1)
+		if start == len(stmt) || stmt[start].text != "(" {
+			return nil
+		}
+		_, end := listLen(stmt, start)
+		if end <= start || !isColumnList(stmt[start:end]) {
+			return nil
+		}
+		var names []lexeme
+		for _, c := range stmt[start+1 : end-1] {
+			if c.kind == lexWord || c.kind == lexQuotedIdent {
+				names = append(names, c)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// missingColumns returns the sorted names of the NOT NULL columns without a
+// default of table t which an INSERT statement does not set. INSERTs without a
+// column list set every column, except for INSERT ... DEFAULT VALUES.
+func missingColumns(stmt []lexeme, t *table) []string {
+	if mainKeyword(stmt) != "INSERT" {
+		return nil
+	}
+	columns := insertColumnList(stmt)
+	if columns == nil {
+		defaults := false
+		for i := 0; i+1 < len(stmt); i++ {
+			if strings.EqualFold(stmt[i].text, "DEFAULT") && strings.EqualFold(stmt[i+1].text, "VALUES") {
+				defaults = true
+			}
+		}
+		if !defaults {
+			return nil
+		}
+	}
+	set := make(map[string]bool)
+	for _, l := range columns {
+		set[normalizeIdent(l)] = true
+	}
+	var missing []string
+	for name, c := range t.columns {
+		if c.notNull && !c.hasDefault && !set[name] {
+			missing = append(missing, c.name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// setTargets returns the columns assigned by the SET clauses of a statement.
+func setTargets(stmt []lexeme) []lexeme {
+	var targets []lexeme
+	inSet := false
+	for i, l := range stmt {
+		switch {
+		case l.kind == lexWord && strings.EqualFold(l.text, "SET"):
+			inSet = true
+		case l.kind == lexWord && setClauseEnds[strings.ToUpper(l.text)]:
+			inSet = false
+		}
+		if !inSet || i == 0 || i+1 == len(stmt) || stmt[i+1].text != "=" {
+			continue
+		}
+		if prev := stmt[i-1].text; prev == "," || strings.EqualFold(prev, "SET") {
+			targets = append(targets, l)
+		}
+	}
+	return targets
+}
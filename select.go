@@ -0,0 +1,275 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// selectList returns the items of the select list of the first top level
+// SELECT in lexemes, each item being its lexemes. It returns false if there
+// is no such SELECT.
+func selectList(lexemes []lexeme) ([][]lexeme, bool) {
+	depth := 0
+	start := -1
+	for i, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth == 0 && l.kind == lexWord && strings.EqualFold(l.text, "SELECT"):
+			start = i + 1
+		}
+		if start >= 0 {
+			break
+		}
+	}
+	if start < 0 {
+		return nil, false
+	}
+	start = skipSelectModifiers(lexemes, start)
+	var items [][]lexeme
+	itemStart := start
+	depth = 0
+	for i := start; i < len(lexemes); i++ {
+		l := lexemes[i]
+		switch {
+		case l.text == "(":
+			depth++
+			continue
+		case l.text == ")":
+			if depth--; depth >= 0 {
+				continue
+			}
+		case depth != 0:
+			continue
+		case l.text == ",":
+			items = append(items, lexemes[itemStart:i])
+			itemStart = i + 1
+			continue
+		case l.text != ";" && (l.kind != lexWord || !selectListEnds[strings.ToUpper(l.text)]):
+			continue
+		}
+		// l ends the select list.
+		return append(items, lexemes[itemStart:i]), true
+	}
+	return append(items, lexemes[itemStart:]), true
+}
+
+// selectListEnds are the keywords which can follow a select list.
+var selectListEnds = map[string]bool{
+	"EXCEPT": true, "FETCH": true, "FOR": true, "FROM": true, "GROUP": true,
+	"HAVING": true, "INTERSECT": true, "INTO": true, "LIMIT": true, "OFFSET": true,
+	"ORDER": true, "UNION": true, "WHERE": true, "WINDOW": true,
+}
+
+// skipSelectModifiers returns the index of the first lexeme from i which is
+// not a modifier of the select list, like DISTINCT ON (...) or TOP 10.
+func skipSelectModifiers(lexemes []lexeme, i int) int {
+	for i < len(lexemes) {
+		switch strings.ToUpper(lexemes[i].text) {
+		case "ALL", "DISTINCTROW", "STRAIGHT_JOIN", "SQL_CALC_FOUND_ROWS", "SQL_NO_CACHE":
+			i++
+		case "DISTINCT":
+			i++
+			if i+1 < len(lexemes) && strings.EqualFold(lexemes[i].text, "ON") && lexemes[i+1].text == "(" {
+				_, i = listLen(lexemes, i+1)
+			}
+		case "TOP":
+			i += 2
+			if i < len(lexemes) && strings.EqualFold(lexemes[i].text, "PERCENT") {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// hasStar reports whether a select list selects all the columns of a table.
+func hasStar(items [][]lexeme) bool {
+	for _, item := range items {
+		if len(item) > 0 && item[len(item)-1].text == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSelectStar reports a query whose select list has a *, like SELECT * or
+// SELECT t.*, as the columns it returns change with the schema.
+func checkSelectStar(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	if items, ok := selectList(lexemes); ok && hasStar(items) {
+		reportf(pass, catSelectStar, call.Lparen, "Query selects *: list the columns so that Scan does not break when the table changes")
+	}
+}
+
+// checkOrdinals reports ORDER BY and GROUP BY ordinals, like ORDER BY 3, which
+// are higher than the no. of columns of the select list. These break silently
+// when a column is removed.
+func checkOrdinals(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	items, ok := selectList(lexemes)
+	if !ok || hasStar(items) {
+		return
+	}
+	depth := 0
+	clause := ""
+	for i, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth != 0:
+		case l.kind == lexWord && i+1 < len(lexemes) && strings.EqualFold(lexemes[i+1].text, "BY"):
+			clause = strings.ToUpper(l.text)
+		case l.kind == lexWord && selectListEnds[strings.ToUpper(l.text)]:
+			clause = ""
+		case l.kind == lexNumber && (clause == "ORDER" || clause == "GROUP"):
+			prev := lexemes[i-1].text
+			if prev != "," && !strings.EqualFold(prev, "BY") {
+				continue
+			}
+			n, err := strconv.Atoi(l.text)
+			if err != nil || n <= len(items) {
+				continue
+			}
+			reportf(pass, catArity, call.Lparen, "%s BY %d is out of range: the select list has %d columns", clause, n, len(items))
+		}
+	}
+}
+
+// aggregateFuncs are the common aggregate functions.
+var aggregateFuncs = keywordSet("ARRAY_AGG", "AVG", "BOOL_AND", "BOOL_OR", "COUNT",
+	"GROUP_CONCAT", "JSON_AGG", "JSONB_AGG", "LISTAGG", "MAX", "MIN", "STRING_AGG", "SUM")
+
+// checkGroupBy reports the bare columns of a select list which also has
+// aggregates, if they are not in the GROUP BY clause.
+func checkGroupBy(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var lexemes []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind != lexComment {
+			lexemes = append(lexemes, l)
+		}
+	}
+	items, ok := selectList(lexemes)
+	if !ok {
+		return
+	}
+	aggregated := false
+	var columns []string
+	for _, item := range items {
+		for i, l := range item {
+			if l.kind == lexWord && strings.EqualFold(l.text, "OVER") {
+				// Window functions are computed after grouping, so
+				// leave these queries alone.
+				return
+			}
+			if l.kind == lexWord && aggregateFuncs[strings.ToUpper(l.text)] && i+1 < len(item) && item[i+1].text == "(" {
+				aggregated = true
+			}
+		}
+		if column := bareColumn(item); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	if !aggregated || len(columns) == 0 {
+		return
+	}
+	grouped := groupByItems(lexemes)
+	for i, column := range columns {
+		if grouped[strings.ToLower(column)] || grouped[strconv.Itoa(i+1)] {
+			continue
+		}
+		if dot := strings.LastIndexByte(column, '.'); dot >= 0 && grouped[strings.ToLower(column[dot+1:])] {
+			continue
+		}
+		reportf(pass, catGroupBy, call.Lparen, "Column %s must be in GROUP BY or used in an aggregate function", column)
+		return
+	}
+}
+
+// bareColumn returns the column of a select list item which is only a column
+// reference, like c1, t.c1 or t.c1 AS c, and "" otherwise.
+func bareColumn(item []lexeme) string {
+	end := 0
+	for end < len(item) {
+		l := item[end]
+		if l.kind != lexWord && l.kind != lexQuotedIdent {
+			return ""
+		}
+		end++
+		if end == len(item) || item[end].text != "." {
+			break
+		}
+		end++
+	}
+	if end == 0 {
+		return ""
+	}
+	switch rest := item[end:]; {
+	case len(rest) == 0:
+	case len(rest) == 1 && rest[0].kind == lexWord,
+		len(rest) == 2 && strings.EqualFold(rest[0].text, "AS"):
+		// An alias.
+	default:
+		return ""
+	}
+	var column strings.Builder
+	for _, l := range item[:end] {
+		column.WriteString(l.text)
+	}
+	return column.String()
+}
+
+// groupByItems returns the lower cased items of the top level GROUP BY clause
+// in lexemes which are simple column references or ordinals.
+func groupByItems(lexemes []lexeme) map[string]bool {
+	items := make(map[string]bool)
+	depth := 0
+	grouping := false
+	var item strings.Builder
+	flush := func() {
+		if item.Len() > 0 {
+			items[strings.ToLower(item.String())] = true
+			item.Reset()
+		}
+	}
+	for i, l := range lexemes {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth != 0:
+		case l.kind == lexWord && strings.EqualFold(l.text, "GROUP") && i+1 < len(lexemes) && strings.EqualFold(lexemes[i+1].text, "BY"):
+			grouping = true
+		case !grouping || strings.EqualFold(l.text, "BY"):
+		case l.text == ",":
+			flush()
+		case l.kind == lexWord && selectListEnds[strings.ToUpper(l.text)], l.text == ";":
+			flush()
+			grouping = false
+		default:
+			item.WriteString(l.text)
+		}
+	}
+	flush()
+	return items
+}
@@ -1,8 +1,11 @@
 package sqlargs
 
 import (
+	"database/sql"
+	"flag"
 	"go/ast"
 	"go/constant"
+	"go/token"
 	"go/types"
 
 	"golang.org/x/tools/go/analysis"
@@ -19,96 +22,383 @@ and reports any mismatches.
 This is a common occurence when updating a sql query to add/remove
 a column.`
 
-var Analyzer = &analysis.Analyzer{
-	Name:             "sqlargs",
-	Doc:              Doc,
-	Run:              run,
-	Requires:         []*analysis.Analyzer{inspect.Analyzer},
-	RunDespiteErrors: true,
+// Analyzer runs all the checks. Its result is the *Inventory of the queries.
+var Analyzer = newAnalyzer("sqlargs", Doc, extract, categories...)
+
+func init() {
+	for _, a := range analyzers {
+		registerFlags(&a.Flags)
+	}
+}
+
+// registerFlags registers the flags of the analyzers in fs. The analyzers
+// share the flags, as they share the extraction of the queries.
+func registerFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&flagConfig.strict, "strict", false, "report queries whose value cannot be determined statically")
+	fs.BoolVar(&flagConfig.requireWhere, "require-where", false, "report UPDATE and DELETE statements without a WHERE clause")
+	fs.BoolVar(&flagConfig.groupBy, "group-by", false, "report selected columns which are neither aggregated nor in GROUP BY")
+	fs.BoolVar(&flagConfig.selectStar, "select-star", false, "report queries selecting * instead of listing the columns")
+	fs.BoolVar(&flagConfig.insertColumns, "insert-columns", false, "report INSERT statements without a column list")
+	fs.BoolVar(&flagConfig.swappedArgs, "swapped-args", false, "report variables passed for two placeholders whose names are the columns of each other")
+	fs.BoolVar(&flagConfig.sqlConstants, "sql-constants", false, "check the syntax of the string constants starting with a SELECT, INSERT, UPDATE, DELETE or WITH statement, even if they are never run")
+	fs.BoolVar(&flagConfig.duplicateArgs, "duplicate-args", false, "report variables passed for two placeholders of columns of different names")
+	fs.BoolVar(&flagConfig.unusedQueries, "unused-queries", false, "report string constants holding queries which are never used")
+	fs.BoolVar(&flagConfig.duplicateQueries, "duplicate-queries", false, "report constant queries which are defined more than once, in the package or its dependencies")
+	fs.BoolVar(&flagConfig.ddl, "ddl", false, "check the syntax of CREATE TABLE, CREATE INDEX and ALTER TABLE statements run with Exec")
+	fs.BoolVar(&flagConfig.contextMethods, "context-methods", false, "report Exec, Query and QueryRow calls in functions with a context.Context param, instead of their Context variants")
+	fs.BoolVar(&flagConfig.uncheckedExec, "unchecked-exec", false, "report Exec calls whose result and error are both discarded")
+	fs.BoolVar(&flagConfig.errNoRows, "err-no-rows", false, "report errors of QueryRow handled as failures without checking for sql.ErrNoRows")
+	fs.BoolVar(&flagConfig.loopQueries, "loop-queries", false, "report constant queries run in loops, which could be batched")
+	fs.BoolVar(&flagConfig.requireConst, "require-const-queries", false, "report queries which are not compile-time constants")
+	fs.Var(sanitizersFlag{flagConfig.sanitizers}, "sanitizers", "comma separated functions, as pkgpath.Func or pkgpath.Type.Method, whose results are safe to interpolate into queries")
+	fs.Var(listFlag{&flagConfig.imports}, "imports", "comma separated packages running queries, like in-house wrappers of database/sql, whose importers are analyzed")
+	fs.StringVar(&flagConfig.schemaFile, "schema", "", "DDL file, or directory of migrations, with the schema the queries are checked against")
+	fs.StringVar(&cacheDir, "cache", cacheDir, "directory caching parsed schemas and queries between runs, or empty to disable it")
+	fs.StringVar(&driftDir, "drift", "", "directory where the columns used by each package are written, for sqlargs drift")
+	fs.StringVar(&inventoryDir, "inventory", "", "directory where the queries of each package are written, for sqlargs report")
+	fs.StringVar(&statsDir, "stats", "", "directory where the statistics of each package are written, for sqlargs stats")
+	fs.StringVar(&dsn, "dsn", "", "data source name of a database the constant queries are prepared against")
+	fs.StringVar(&dsnDriver, "driver", "", "database/sql driver of -dsn, by default its URL scheme")
+	fs.StringVar(&baselineFile, "baseline", "", "file listing the existing findings, which are not reported")
+	fs.BoolVar(&writeBaseline, "write-baseline", false, "append the findings to the -baseline file instead of reporting them")
+	fs.Var(confidenceFlag{&minConfidence}, "min-confidence", "confidence below which findings are not reported: low, medium or high")
+	fs.Var(categoriesFlag{&flagConfig.only}, "only", "comma separated categories of the diagnostics which are the only ones reported, like argcount,syntax")
+	fs.Var(categoriesFlag{&flagConfig.disabled}, "disable", "comma separated categories of the diagnostics which are not reported, like selectstar")
+	fs.Var(severityFlag{}, "severity", "comma separated category=severity pairs, where severity is error, warning, info or off")
+	fs.Var(dialectFlag{&flagConfig.dialect}, "dialect", "SQL dialect of the queries: "+dialectNames())
+	fs.BoolVar(&flagConfig.skipGenerated, "skip-generated", false, "skip the files with a // Code generated ... DO NOT EDIT. header")
+	fs.BoolVar(&flagConfig.skipTests, "skip-tests", false, "skip the _test.go files")
+	fs.Var(globsFlag{&flagConfig.exclude}, "exclude", "comma separated globs of files which are not analyzed, where ** matches any directories")
+	fs.Var(skipTypeErrorsFlag{}, "skip-type-errors", "skip the packages with type errors, instead of analyzing them with incomplete type info")
+	fs.Var(parserFlag{&flagConfig.parser}, "parser", "backend validating the syntax of the queries, by default "+defaultParser+": "+parserNames())
 }
 
-func run(pass *analysis.Pass) (interface{}, error) {
-	// We ignore packages that do not import database/sql.
-	hasImport := false
-	for _, imp := range pass.Pkg.Imports() {
-		if imp.Path() == "database/sql" {
-			hasImport = true
-			break
+func (cfg *config) run(pass *analysis.Pass) (interface{}, error) {
+	found := &findings{}
+	pass = cfg.withDisabled(found.collect(pass))
+
+	// We ignore packages that do not import a package running queries.
+	if !cfg.importsSQL(pass.Pkg) {
+		found.inventory = inventory(nil, pass)
+		return found, nil
+	}
+	pass = withSeverities(pass)
+	b, err := newBaseline()
+	if err != nil {
+		return nil, err
+	}
+	if b != nil {
+		pass = b.wrap(pass)
+	}
+	pass = withSuppressions(pass)
+	score := &scorer{level: confHigh}
+	pass = score.wrap(pass)
+	queries := &Queries{Package: pass.Pkg.Path()}
+	directives := readDialectDirectives(pass)
+
+	// Without -schema, the schema and engine of a sqlc configuration are used.
+	var conf *sqlcConfig
+	if cfg.schemaFile == "" {
+		var err error
+		if conf, err = findSQLCConfig(pass); err != nil {
+			return nil, err
 		}
 	}
-	if !hasImport {
-		return nil, nil
+
+	// An explicitly selected dialect takes precedence over the configured
+	// and the detected one.
+	d := cfg.dialect
+	if d == permissive && conf != nil && conf.dialect != nil {
+		d = conf.dialect
+	}
+	if d == permissive {
+		if detected := detectDialect(pass); detected != nil {
+			d = detected
+			pass.ExportPackageFact(&driverDialect{Name: detected.name})
+		}
 	}
 
+	var s *schema
+	if cfg.schemaFile != "" || conf != nil && len(conf.schemas) > 0 {
+		paths := []string{cfg.schemaFile}
+		if cfg.schemaFile == "" {
+			paths = conf.schemas
+		}
+		var err error
+		if s, err = loadSchema(paths, d); err != nil {
+			return nil, err
+		}
+	}
+	var u *usage
+	if driftDir != "" && s != nil {
+		u = newUsage(s)
+	}
+
+	var db *sql.DB
+	if dsn != "" {
+		var err error
+		if db, err = openDatabase(); err != nil {
+			return nil, err
+		}
+	}
+
+	skipped := cfg.skippedFiles(pass)
+	// dynamic is the no. of queries which cannot be determined statically.
+	dynamic := 0
+	stmts := newStmtUses()
+	defs := newQueryDefs()
+	parsed := make(parseMemo)
+	// called are the constants run by the calls, which the //sqlargs:sql
+	// directives do not check again.
+	called := make(map[*types.Const]bool)
 	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
 	// We filter only function calls.
 	nodeFilter := []ast.Node{
 		(*ast.CallExpr)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		if skipped[stack[0].(*ast.File)] {
+			return false
+		}
 		call := n.(*ast.CallExpr)
+		score.level = confHigh
+		if isBigQueryCall(call, pass.TypesInfo) {
+			checkBigQuery(call, stack, pass)
+			return true
+		}
+		if isPrepareCall(call, pass.TypesInfo) {
+			checkStmtClose(call, stack, pass)
+			stmts.prepare(call, stack, directives.dialect(pass.Fset, call, d), pass.TypesInfo)
+			return true
+		}
+		if stmts.rebind(call, stack, pass.TypesInfo) || stmts.exec(call, pass.TypesInfo) {
+			return true
+		}
+		if isBeginCall(call, pass.TypesInfo) {
+			checkTxEnd(call, stack, pass)
+			return true
+		}
 		// Now we need to find expressions like these in the source code.
 		// db.Exec(`INSERT INTO <> (foo, bar) VALUES ($1, $2)`, param1, param2)
-
-		// A CallExpr has 2 parts - Fun and Args.
-		// A Fun can either be an Ident (Fun()) or a SelectorExpr (foo.Fun()).
-		// Since we are looking for patterns like db.Exec, we need to filter only SelectorExpr
-		// We will ignore dot imported functions.
-		sel, ok := call.Fun.(*ast.SelectorExpr)
+		orig := call
+		call, method, ok := cfg.queryCall(call, pass.TypesInfo)
 		if !ok {
-			return
-		}
-
-		// A SelectorExpr(db.Exec) has 2 parts - X (db) and Sel (Exec/Query/QueryRow).
-		// Now that we are inside the SelectorExpr, we need to verify 2 things -
-		// 1. The function name is Exec, Query or QueryRow; because that is what we are interested in.
-		// 2. The type of the selector is sql.DB, sql.Tx or sql.Stmt.
-		// TODO: Also do the Context couterparts.
-		if !isProperSelExpr(sel, pass.TypesInfo) {
-			return
+			checkContextArg(orig, pass)
+			return true
 		}
 		// Length of args has to be minimum of 1 because we only take Exec, Query or QueryRow;
 		// all of which have atleast 1 argument. But still writing a sanity check.
 		if len(call.Args) == 0 {
-			return
+			return true
+		}
+		call = withoutDriverOptions(call, pass.TypesInfo)
+		d := directives.dialect(pass.Fset, call, d)
+		if d == permissive {
+			score.lower(confMedium)
+		}
+
+		for _, arg := range unspreadSlices(call, pass.TypesInfo) {
+			diag := analysis.Diagnostic{Pos: arg.Pos(), Category: catArgType, Message: "Slice passed without ...: it will be bound as a single arg"}
+			// Only a slice which is the sole arg can be spread.
+			if len(call.Args) == 2 {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Spread the slice",
+					TextEdits: []analysis.TextEdit{{Pos: arg.End(), End: arg.End(), NewText: []byte("...")}},
+				}}
+			}
+			pass.Report(diag)
+		}
+		if call.Ellipsis == token.NoPos {
+			checkStructArgs(call, pass)
+			checkCollectionArgs(call, d, pass)
+			checkErrorArgs(call, pass)
 		}
 
+		body := enclosingBody(stack)
 		arg0 := call.Args[0]
-		typ, ok := pass.TypesInfo.Types[arg0]
-		if !ok || typ.Value == nil {
-			return
+		var query string
+		parse := true
+		if typ, ok := pass.TypesInfo.Types[arg0]; ok && typ.Value != nil {
+			query = constant.StringVal(typ.Value)
+			if len(query) > maxQueryLen {
+				checkLargeQuery(query, d, call, numArgs(call, body, pass.TypesInfo), pass)
+				return true
+			}
+			queries.add(query, d, call, pass)
+			cfg.visit(query, true, d, call, method, pass)
+			if cfg.duplicateQueries {
+				defs.add(query, d, arg0, pass)
+			}
+			if cfg.loopQueries {
+				checkLoopQuery(query, d, call, stack, pass)
+			}
+			// The parameters of the procedure are not known.
+			if d.name == "sqlserver" && isProcName(query) {
+				return true
+			}
+			var analyze bool
+			if analyze, parse = checkConstantQuery(cfg, query, d, call, pass); !analyze {
+				return true
+			}
+			if cfg.ddl && parse && method == "Exec" {
+				checkDDL(query, d, call, pass)
+			}
+			if s != nil {
+				checkSchema(query, d, s, u, call, pass)
+			}
+			if db != nil {
+				checkPrepare(query, d, db, call, pass)
+			}
+		} else {
+			if cfg.requireConst {
+				reportf(pass, catRequireConst, arg0.Pos(), "Query is not a constant: only constant queries are allowed")
+			}
+			checkInjection(call, d, cfg.sanitizers, body, pass)
+			if query, ok = templateQuery(arg0, body, pass); !ok {
+				dynamic++
+				if cfg.strict {
+					reportf(pass, catStrict, arg0.Pos(), "Unverifiable query: value cannot be determined statically")
+				}
+				return true
+			}
+			score.lower(confLow)
+			queries.add(query, d, call, pass)
+			cfg.visit(query, false, d, call, method, pass)
+		}
+		if method == "Exec" && hasReturning(query, d) {
+			reportf(pass, catMethod, call.Lparen, "Query has a RETURNING clause but is run with Exec: the returned rows are discarded, use QueryRow or Query")
+		}
+		if method == "Exec" && isSelect(query, d) {
+			reportf(pass, catMethod, call.Lparen, "SELECT is run with Exec: the selected rows are discarded, use QueryRow or Query")
+		}
+		if method == "Query" || method == "QueryRow" {
+			if keyword, ok := writeWithoutRows(query, d); ok {
+				reportf(pass, catMethod, call.Lparen, "%s without RETURNING is run with %s: it returns no rows, use Exec", keyword, method)
+			}
+		}
+		args := numArgs(call, body, pass.TypesInfo)
+		if call.Ellipsis.IsValid() {
+			score.lower(confMedium)
+		}
+		if cfg.strict && !args.exact() {
+			reportf(pass, catStrict, call.Args[len(call.Args)-1].Pos(), "Unverifiable args: no. of args is %v", args)
+		}
+		// The constants of dependencies are only parsed once, by the package
+		// declaring them.
+		parser, _ := cfg.queryParser()
+		c := queryConst(arg0, pass.TypesInfo)
+		if c != nil {
+			called[c] = true
+		}
+		importVerified(c, query, d, parser, parsed, pass)
+		analyzeQuery(cfg, query, call, args, d, parse, parsed, pass)
+		exportVerified(c, query, d, parser, parsed, pass)
+		if cfg.duplicateArgs {
+			// Duplicates are sometimes intended.
+			score.within(confMedium, func() { checkDuplicateArgs(query, d, call, pass) })
+		}
+		if cfg.swappedArgs {
+			// The names of the args are only a heuristic.
+			score.within(confLow, func() { checkSwappedArgs(query, d, call, pass) })
+		}
+		// The rows of other funcs are not known to be sql.Rows.
+		sel, isSel := orig.Fun.(*ast.SelectorExpr)
+		native := call == orig || isSel && isProperSelExpr(sel, pass.TypesInfo)
+		if native && (method == "QueryRow" || method == "Query") {
+			checkScan(query, d, orig, stack, pass)
+		}
+		if call == orig && method != "" && cfg.contextMethods {
+			checkContextMethod(call, method, stack, pass)
+		}
+		if native && method == "Exec" && cfg.uncheckedExec {
+			checkDiscardedExec(orig, stack, pass)
+		}
+		if native && method == "QueryRow" && cfg.errNoRows {
+			checkErrNoRows(orig, stack, pass)
+		}
+		if native && method == "Query" {
+			checkRows(orig, stack, pass)
+		}
+		if _, ok := sqlxQueries[method]; ok {
+			checkStructDest(query, d, call, orig, method, stack, pass)
 		}
-		analyzeQuery(constant.StringVal(typ.Value), call, pass)
+		return true
 	})
 
-	return nil, nil
+	// The findings of the checks below are not on a call, so they must not
+	// keep the confidence left by the last one.
+	score.level = confHigh
+	checkSQLMock(queries, skipped, inspect, pass)
+	checkSQLDirectives(cfg, d, directives, called, skipped, parsed, pass)
+	stmts.check(pass)
+	if cfg.duplicateQueries {
+		defs.check(pass)
+	}
+	if cfg.unusedQueries {
+		checkUnusedQueries(d, skipped, pass)
+	}
+
+	if u != nil {
+		if err := u.write(driftDir, pass.Pkg.Path()); err != nil {
+			return nil, err
+		}
+	}
+	if b != nil {
+		if err := b.write(); err != nil {
+			return nil, err
+		}
+	}
+	if inventoryDir != "" && len(queries.Queries) > 0 {
+		if err := writeInventory(inventoryDir, queries); err != nil {
+			return nil, err
+		}
+	}
+	found.sort()
+	if statsDir != "" && hasStats(queries, dynamic) {
+		if err := writeStats(statsDir, queries, dynamic, found); err != nil {
+			return nil, err
+		}
+	}
+	found.inventory = inventory(queries, pass)
+	return found, nil
 }
 
 func isProperSelExpr(sel *ast.SelectorExpr, typesInfo *types.Info) bool {
-	// Only accept function calls for Exec, QueryRow and Query
-	fnName := sel.Sel.Name
-	if fnName != "Exec" &&
-		fnName != "QueryRow" &&
-		fnName != "Query" {
+	// Only accept function calls for Exec, QueryRow and Query, and their
+	// Context variants.
+	switch sel.Sel.Name {
+	case "Exec", "QueryRow", "Query", "ExecContext", "QueryRowContext", "QueryContext":
+	default:
 		return false
 	}
-	// Get the type info of X of the selector.
-	typ, ok := typesInfo.Types[sel.X]
-	if !ok {
+	// The method is looked up in the method set of X, so that the methods of
+	// a sql.DB or sql.Tx promoted through any no. of embedded structs and
+	// pointers, like s.Exec for a repo embedding a base repo which embeds a
+	// *sql.DB, are accepted too.
+	selection, ok := typesInfo.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
 		return false
 	}
-	ptr, ok := typ.Type.(*types.Pointer)
+	fn, ok := selection.Obj().(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "database/sql" {
+		return false
+	}
+	ptr, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Pointer)
 	if !ok {
 		return false
 	}
-	n := ptr.Elem().(*types.Named)
-	if n.Obj().Pkg().Path() != "database/sql" {
+	n, ok := ptr.Elem().(*types.Named)
+	if !ok {
 		return false
 	}
 	name := n.Obj().Name()
-	// Only accept sql.DB, sql.Tx or sql.Stmt types.
-	if name != "DB" && name != "Tx" && name != "Stmt" {
+	// Only accept sql.DB or sql.Tx types. The methods of sql.Stmt only take
+	// the args, as its query is prepared.
+	if name != "DB" && name != "Tx" {
 		return false
 	}
 	return true
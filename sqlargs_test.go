@@ -1,9 +1,21 @@
 package sqlargs_test
 
 import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/agnivade/sqlargs"
+	"github.com/agnivade/sqlargs/golangci"
+	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/analysistest"
 )
 
@@ -11,3 +23,1160 @@ func TestQueries(t *testing.T) {
 	testdata := analysistest.TestData()
 	analysistest.Run(t, testdata, sqlargs.Analyzer, "a") // loads testdata/src/a/a.go.
 }
+
+func TestStrict(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("strict", "true")
+	defer sqlargs.Analyzer.Flags.Set("strict", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "strict")
+}
+
+func TestRequireWhere(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("require-where", "true")
+	defer sqlargs.Analyzer.Flags.Set("require-where", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "where")
+}
+
+func TestRequireConst(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("require-const-queries", "true")
+	defer sqlargs.Analyzer.Flags.Set("require-const-queries", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "constqueries")
+}
+
+func TestBaseline(t *testing.T) {
+	testdata := analysistest.TestData()
+	sqlargs.Analyzer.Flags.Set("baseline", filepath.Join(testdata, "src", "baseline", "sqlargs.baseline"))
+	defer sqlargs.Analyzer.Flags.Set("baseline", "")
+
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "baseline")
+}
+
+// ignoreErrors is an analysistest.Testing ignoring the unmatched expectations
+// of a run.
+type ignoreErrors struct{}
+
+func (ignoreErrors) Errorf(format string, args ...interface{}) {}
+
+func TestWriteBaseline(t *testing.T) {
+	testdata := analysistest.TestData()
+	file := filepath.Join(t.TempDir(), "sqlargs.baseline")
+	sqlargs.Analyzer.Flags.Set("baseline", file)
+	sqlargs.Analyzer.Flags.Set("write-baseline", "true")
+	defer sqlargs.Analyzer.Flags.Set("baseline", "")
+	defer sqlargs.Analyzer.Flags.Set("write-baseline", "false")
+
+	for _, r := range analysistest.Run(ignoreErrors{}, testdata, sqlargs.Analyzer, "baseline") {
+		if len(r.Diagnostics) != 0 {
+			t.Errorf("got %d diagnostics, want none", len(r.Diagnostics))
+		}
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.Abs(filepath.Join(testdata, "src", "baseline", "baseline.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("got %d findings, want 5:\n%s", len(lines), data)
+	}
+	for _, line := range lines {
+		fields := strings.SplitN(line, ": ", 2)
+		if filepath.Join(filepath.Dir(file), filepath.FromSlash(fields[0])) != want || !strings.HasPrefix(fields[1], "argcount: No. of args") {
+			t.Errorf("unexpected finding %q", line)
+		}
+	}
+}
+
+func TestDialectDirectives(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "directives")
+}
+
+func TestSQLDirectives(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sqldirective")
+}
+
+func TestSuppressions(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "suppress")
+}
+
+func TestMinConfidence(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("min-confidence", "high")
+	sqlargs.Analyzer.Flags.Set("duplicate-args", "true")
+	sqlargs.Analyzer.Flags.Set("swapped-args", "true")
+	defer func() {
+		sqlargs.Analyzer.Flags.Set("min-confidence", "low")
+		sqlargs.Analyzer.Flags.Set("duplicate-args", "false")
+		sqlargs.Analyzer.Flags.Set("swapped-args", "false")
+	}()
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "confidence")
+}
+
+func TestSkipGenerated(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("skip-generated", "true")
+	defer sqlargs.Analyzer.Flags.Set("skip-generated", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "generated")
+}
+
+func TestWrapperImports(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{ExtraFuncs: []string{"wrapper/db.Exec"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "wrapper")
+}
+
+func TestSkipTests(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("skip-tests", "true")
+	defer sqlargs.Analyzer.Flags.Set("skip-tests", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "skiptests")
+}
+
+func TestExclude(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Exclude: []string{"exclude/legacy/**", "**/legacy_*.go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "exclude", "exclude/legacy")
+}
+
+func TestDiagnosticOrder(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sqlargs.Analyzer, "a")
+	for _, result := range results {
+		for i := 1; i < len(result.Diagnostics); i++ {
+			prev, d := result.Diagnostics[i-1], result.Diagnostics[i]
+			if d.Pos < prev.Pos || d.Pos == prev.Pos && d.Category < prev.Category {
+				t.Errorf("diagnostic %q is reported after %q", d.Message, prev.Message)
+			}
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sqlargs.Analyzer, "inventory")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	inv := results[0].Result.(*sqlargs.Inventory)
+	var got []string
+	for _, pkg := range inv.Packages {
+		for _, q := range pkg.Queries {
+			got = append(got, fmt.Sprintf("%s %s %d %s", pkg.Package, q.Kind, q.Placeholders, q.Text))
+			if !strings.Contains(q.Pos, "inventory") {
+				t.Errorf("query %q is at %s, want a file of the inventory packages", q.Text, q.Pos)
+			}
+		}
+	}
+	want := []string{
+		"inventory SELECT 1 SELECT name FROM users WHERE id = $1",
+		"inventory/store INSERT 2 INSERT INTO users (id, name) VALUES ($1, $2)",
+		"inventory/store DELETE 1 DELETE FROM template_action WHERE id = $1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got queries:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestInventoryReport(t *testing.T) {
+	dir := t.TempDir()
+	sqlargs.Analyzer.Flags.Set("inventory", dir)
+	defer sqlargs.Analyzer.Flags.Set("inventory", "")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "inventory")
+	var report strings.Builder
+	if err := sqlargs.InventoryReport(dir, "csv", &report); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	if len(lines) != 4 || lines[0] != "package,position,kind,tables,placeholders,query" {
+		t.Fatalf("got report:\n%s", report.String())
+	}
+	for _, want := range []string{
+		",SELECT,users,1,SELECT name FROM users WHERE id = $1",
+		",DELETE,template_action,1,DELETE FROM template_action WHERE id = $1",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report does not contain %q:\n%s", want, report.String())
+		}
+	}
+	if err := sqlargs.InventoryReport(dir, "xml", &report); err == nil {
+		t.Error("InventoryReport in xml: want an error")
+	}
+}
+
+func TestRows(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "rows")
+}
+
+func TestStmt(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "stmt")
+}
+
+func TestTx(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "tx")
+}
+
+func TestEmbedded(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "embedded")
+}
+
+func TestParamLimit(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "paramlimit")
+}
+
+func TestStmtArgs(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sqlargs.Analyzer, "stmtargs")
+	want := []string{
+		"stmtargs.go:16:21: Statement prepared here",
+		"stmtargs.go:38:12: Statement update run with 1 args",
+		"stmtargs.go:33:12: Statement update run with 2 args",
+		"stmtargs.go:43:15: Statement prepared here",
+		"stmtargs.go:16:21: Statement prepared here",
+		"stmtargs.go:16:21: Statement prepared here",
+	}
+	if got := relatedPositions(results); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("related information is %q, want %q", got, want)
+	}
+}
+
+func TestSQLMock(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sqlargs.Analyzer, "sqlmock")
+	want := []string{"users.go:14:10: Query matched by the expectation"}
+	if got := relatedPositions(results); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("related information is %q, want %q", got, want)
+	}
+}
+
+// relatedPositions returns the related information of the diagnostics of
+// results, as file:line:column: message, with the base name of the file.
+func relatedPositions(results []*analysistest.Result) []string {
+	var related []string
+	for _, r := range results {
+		for _, d := range r.Diagnostics {
+			for _, info := range d.Related {
+				posn := r.Pass.Fset.Position(info.Pos)
+				related = append(related, fmt.Sprintf("%s:%d:%d: %s", filepath.Base(posn.Filename), posn.Line, posn.Column, info.Message))
+			}
+		}
+	}
+	return related
+}
+
+func TestStats(t *testing.T) {
+	dir := t.TempDir()
+	sqlargs.Analyzer.Flags.Set("stats", dir)
+	defer sqlargs.Analyzer.Flags.Set("stats", "")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "stats")
+	var report strings.Builder
+	if err := sqlargs.StatsReport(dir, &report); err != nil {
+		t.Fatal(err)
+	}
+	want := "Packages scanned: 1\nQueries analyzed: 2\nQueries skipped as dynamic: 1\nCoverage: 66.7%\nFindings: 1\n\targcount: 1\n"
+	if report.String() != want {
+		t.Errorf("got report:\n%s\nwant:\n%s", report.String(), want)
+	}
+}
+
+func TestNewAnalyzer(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{
+		Dialect:        "mysql",
+		ExtraFuncs:     []string{"options.store.exec", "options.queryRow"},
+		DisabledChecks: []string{"semantics"},
+		SelectStar:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "options")
+}
+
+// trino is a dialect registered by TestRegisterDialect.
+type trino struct{}
+
+func (trino) Name() string { return "trino" }
+
+func (trino) Rules() sqlargs.DialectRules {
+	return sqlargs.DialectRules{QuestionParams: true, ReservedWords: []string{"UNNEST"}}
+}
+
+func TestRegisterDialect(t *testing.T) {
+	if err := sqlargs.RegisterDialect(trino{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlargs.RegisterDialect(trino{}); err == nil {
+		t.Error("RegisterDialect of trino twice: got no error")
+	}
+	d, ok := sqlargs.LookupDialect("trino")
+	if !ok {
+		t.Fatal("LookupDialect(trino): not found")
+	}
+	q, err := sqlargs.ParseQuery(`SELECT c1 FROM t WHERE c2 = ? AND c3 = '?'`, d)
+	if err != nil || q.Args != 1 {
+		t.Errorf("ParseQuery: got %d args and error %v, want 1", q.Args, err)
+	}
+	if r := sqlargs.Postgres.Rules(); !r.DollarParams || r.QuestionParams {
+		t.Errorf("Postgres.Rules(): got %+v", r)
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Dialect: "trino"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "customdialect")
+}
+
+// upsertParser is a backend registered by TestRegisterParser, rejecting UPSERT
+// statements.
+type upsertParser struct{}
+
+func (upsertParser) Parse(query string, d sqlargs.Dialect) error {
+	if strings.HasPrefix(query, "UPSERT") {
+		return errors.New("UPSERT is not supported")
+	}
+	return nil
+}
+
+func TestRegisterParser(t *testing.T) {
+	if err := sqlargs.RegisterParser("upsert", upsertParser{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlargs.RegisterParser("lexer", upsertParser{}); err == nil {
+		t.Error("RegisterParser of lexer: got no error")
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Dialect: "postgres", Parser: "upsert"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "parser")
+}
+
+// countingParser is a backend registered by TestParseMemo, counting the
+// queries it parses.
+type countingParser struct {
+	n *int
+}
+
+func (p countingParser) Parse(query string, d sqlargs.Dialect) error {
+	*p.n++
+	return nil
+}
+
+func TestParseMemo(t *testing.T) {
+	prev := sqlargs.Analyzer.Flags.Lookup("cache").Value.String()
+	sqlargs.Analyzer.Flags.Set("cache", "")
+	defer sqlargs.Analyzer.Flags.Set("cache", prev)
+
+	var n int
+	if err := sqlargs.RegisterParser("counting", countingParser{&n}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Dialect: "postgres", Parser: "counting"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "memo")
+	// The query run three times is only parsed once.
+	if n != 2 {
+		t.Errorf("parsed %d queries, want 2", n)
+	}
+}
+
+func TestVerifiedFacts(t *testing.T) {
+	prev := sqlargs.Analyzer.Flags.Lookup("cache").Value.String()
+	sqlargs.Analyzer.Flags.Set("cache", "")
+	defer sqlargs.Analyzer.Flags.Set("cache", prev)
+
+	var n int
+	if err := sqlargs.RegisterParser("verifying", countingParser{&n}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Dialect: "postgres", Parser: "verifying"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "verified")
+	// The constant of verified/queries is only parsed by its own package.
+	if n != 1 {
+		t.Errorf("parsed %d queries, want 1", n)
+	}
+}
+
+// largeTestdata writes a package with a query of several megabytes, like a
+// generated fixture, to a new testdata directory, and returns it.
+func largeTestdata(tb testing.TB) string {
+	dir := tb.TempDir()
+	query := "INSERT INTO t (c1, c2) VALUES " + strings.Repeat("($1, $2), ", 300000) + "($1, $2)"
+	src := fmt.Sprintf("package large\n\nimport \"database/sql\"\n\nconst fixture = %q\n\nfunc load(db *sql.DB) {\n\tdb.Exec(fixture, 1) // want `No. of args \\(1\\) is less than no. of params \\(2\\)`\n}\n", query)
+	if err := os.MkdirAll(filepath.Join(dir, "src", "large"), 0o755); err != nil {
+		tb.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "large", "large.go"), []byte(src), 0o644); err != nil {
+		tb.Fatal(err)
+	}
+	return dir
+}
+
+func TestLargeQuery(t *testing.T) {
+	analysistest.Run(t, largeTestdata(t), sqlargs.Analyzer, "large")
+}
+
+func BenchmarkLargeQuery(b *testing.B) {
+	testdata := largeTestdata(b)
+	for i := 0; i < b.N; i++ {
+		analysistest.Run(b, testdata, sqlargs.Analyzer, "large")
+	}
+}
+
+func BenchmarkQueries(b *testing.B) {
+	testdata := analysistest.TestData()
+	for i := 0; i < b.N; i++ {
+		analysistest.Run(b, testdata, sqlargs.Analyzer, "a")
+	}
+}
+
+func BenchmarkParseQuery(b *testing.B) {
+	query := `SELECT u.id, u.name FROM users u JOIN orders o ON o.user_id = u.id WHERE u.id = $1 AND o.status = $2 AND o.data ? 'key' -- $3
+		ORDER BY o.created_at DESC LIMIT $3`
+	for i := 0; i < b.N; i++ {
+		if _, err := sqlargs.ParseQuery(query, sqlargs.Postgres); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEscapes(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Dialect: "postgres"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	for _, r := range analysistest.Run(t, testdata, a, "escapes") {
+		for _, d := range r.Diagnostics {
+			if !d.End.IsValid() {
+				continue
+			}
+			pos, end := r.Pass.Fset.Position(d.Pos), r.Pass.Fset.Position(d.End)
+			src, err := os.ReadFile(pos.Filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text := string(src[pos.Offset:end.Offset]); text != "?" {
+				t.Errorf("%s: %q is placed on %q, want the placeholder", pos, d.Message, text)
+			}
+		}
+	}
+}
+
+func TestPGQuery(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Parser: "pg_query"})
+	if err != nil {
+		t.Skip("pg_query is only built with -tags sqlargs_pgquery")
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "pgquery")
+}
+
+// panickingParser is a backend registered by TestParserPanic, panicking on
+// the queries using MATCH_RECOGNIZE.
+type panickingParser struct{}
+
+func (panickingParser) Parse(query string, d sqlargs.Dialect) error {
+	if strings.Contains(query, "MATCH_RECOGNIZE") {
+		panic("unexpected token")
+	}
+	return nil
+}
+
+func TestParserPanic(t *testing.T) {
+	if err := sqlargs.RegisterParser("panicking", panickingParser{}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Dialect: "postgres", Parser: "panicking", Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "parserpanic")
+}
+
+func TestVisitors(t *testing.T) {
+	tenantFilter := func(q *sqlargs.QueryCall) {
+		if q.Method == "QueryRow" && !strings.Contains(q.Text, "tenant_id") {
+			q.Reportf("Query has no tenant_id filter")
+		}
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Visitors: []sqlargs.QueryVisitor{tenantFilter}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "visitor")
+}
+
+func TestSkipTypeErrors(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{SkipTypeErrors: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.RunDespiteErrors || a.Requires[0].RunDespiteErrors {
+		t.Error("NewAnalyzer with SkipTypeErrors: got RunDespiteErrors")
+	}
+	if err := sqlargs.Analyzer.Flags.Set("skip-type-errors", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer sqlargs.Analyzer.Flags.Set("skip-type-errors", "false")
+	for _, a := range []*analysis.Analyzer{sqlargs.Analyzer, sqlargs.ArgCount, sqlargs.Policy} {
+		if a.RunDespiteErrors || a.Requires[0].RunDespiteErrors {
+			t.Errorf("%s with -skip-type-errors: got RunDespiteErrors", a.Name)
+		}
+	}
+}
+
+func TestGolangciPlugin(t *testing.T) {
+	analyzers, err := golangci.New(map[string]any{
+		"dialect":         "mysql",
+		"extra-funcs":     []any{"options.store.exec", "options.queryRow"},
+		"disabled-checks": []any{"semantics"},
+		"select-star":     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, analyzers[0], "options")
+
+	if _, err := golangci.New(map[string]any{"dialects": "mysql"}); err == nil {
+		t.Error("New with unknown settings: want an error")
+	}
+
+	// The opt-in checks can all be enabled from .golangci.yml.
+	settings := reflect.TypeOf(golangci.Settings{})
+	opts := reflect.TypeOf(sqlargs.Options{})
+	for i := 0; i < opts.NumField(); i++ {
+		if f := opts.Field(i); f.Type.Kind() == reflect.Bool {
+			if _, ok := settings.FieldByName(f.Name); !ok {
+				t.Errorf("Settings has no field for Options.%s", f.Name)
+			}
+		}
+	}
+}
+
+func TestOnlyChecks(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{
+		OnlyChecks:     []string{"argcount", "semantics"},
+		DisabledChecks: []string{"semantics"},
+		SelectStar:     true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "onlychecks")
+
+	for _, name := range []string{"only", "disable"} {
+		if err := sqlargs.Analyzer.Flags.Set(name, "policy"); err == nil {
+			t.Errorf("-%s=policy: want an error", name)
+		}
+	}
+}
+
+func TestDisableChecks(t *testing.T) {
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{
+		DisabledChecks: []string{"selectstar"},
+		SelectStar:     true,
+		InsertColumns:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, a, "disablechecks")
+}
+
+func TestNewAnalyzerErrors(t *testing.T) {
+	for _, opts := range []sqlargs.Options{
+		{Dialect: "nosql"},
+		{Parser: "sqlglot"},
+		{Exclude: []string{"internal/[legacy"}},
+		{DisabledChecks: []string{"style"}},
+		{OnlyChecks: []string{"policy"}},
+	} {
+		if _, err := sqlargs.NewAnalyzer(opts); err == nil {
+			t.Errorf("NewAnalyzer(%+v): want an error", opts)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		d     sqlargs.Dialect
+		args  int
+		names []string
+		err   string
+	}{
+		{query: `SELECT c1 FROM t WHERE c2 = $1 OR c3 = $2 OR c4 = $1`, d: sqlargs.Postgres, args: 2},
+		{query: `SELECT c1 FROM t WHERE c2 = ? AND c3 = '?'`, d: sqlargs.MySQL, args: 1},
+		{query: `SELECT c1 FROM t WHERE data ? 'key' AND c2 = ?`, args: 1},
+		{query: `SELECT c1 FROM t WHERE c2 = :c2 OR c3 = :C2`, d: sqlargs.Oracle, args: 2, names: []string{"c2"}},
+		{query: `SELECT c1 FROM t WHERE c2 = @p2 AND c3 = @c3`, d: sqlargs.SQLServer, args: 2, names: []string{"c3"}},
+		{query: `SELECT c1 FROM t WHERE c2 = :a AND c3 = ?5 AND c4 = :a`, d: sqlargs.SQLite, args: 5, names: []string{"a"}},
+		{query: `SELECT c1 FROM t WHERE c2 = {c2:String} AND c3 = {C2:String}`, d: sqlargs.ClickHouse, names: []string{"c2"}},
+		{query: `SELECT c1 FROM t WHERE c2 = 'a`, d: sqlargs.Postgres, err: `Unterminated string literal: 'a`},
+		{query: `SELECT c1 FROM t WHERE c2 = ?`, d: sqlargs.Postgres, err: `Placeholder ? is not valid for postgres queries`},
+		{query: `SELECT c1 FROM t WHERE c2 = $1 AND c3 = ?`, err: `Mixed placeholder styles: $1 and ?`},
+	}
+	for _, test := range tests {
+		q, err := sqlargs.ParseQuery(test.query, test.d)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("ParseQuery(%q): got error %v, want %s", test.query, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseQuery(%q): %v", test.query, err)
+			continue
+		}
+		if q.Args != test.args || strings.Join(q.Names, ",") != strings.Join(test.names, ",") {
+			t.Errorf("ParseQuery(%q): got %d args and names %v, want %d and %v", test.query, q.Args, q.Names, test.args, test.names)
+		}
+	}
+}
+
+func TestValidateArgs(t *testing.T) {
+	q, err := sqlargs.ParseQuery(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, sqlargs.Postgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, want := range map[int]string{
+		1: "No. of args (1) is less than no. of params (3): the query references $3 but only 1 arg is passed",
+		3: "",
+		4: "No. of args (4) is more than no. of params (3): arg 4 has no placeholder",
+	} {
+		err := sqlargs.ValidateArgs(q, n)
+		if got := fmt.Sprint(err); want == "" && err != nil || want != "" && got != want {
+			t.Errorf("ValidateArgs(%d): got %v, want %q", n, err, want)
+		}
+	}
+}
+
+func TestSubAnalyzers(t *testing.T) {
+	testdata := analysistest.TestData()
+	all := 0
+	for _, r := range analysistest.Run(ignoreErrors{}, testdata, sqlargs.Analyzer, "a") {
+		all += len(r.Diagnostics)
+	}
+	if all == 0 {
+		t.Fatal("sqlargs reported no diagnostics")
+	}
+	subs := []struct {
+		a          *analysis.Analyzer
+		categories []string
+	}{
+		{sqlargs.ArgCount, []string{"argcount", "argtype", "arity", "placeholder-style", "mock"}},
+		{sqlargs.Syntax, []string{"syntax", "semantics", "directive"}},
+		{sqlargs.Injection, []string{"sqlinjection"}},
+		{sqlargs.Schema, []string{"schema", "database"}},
+		{sqlargs.ResourceUse, []string{"method", "rows", "stmt", "tx"}},
+		{sqlargs.Policy, []string{"strict", "requireconstqueries", "requirewhere", "groupby", "selectstar", "insertcolumns", "loopqueries",
+			"errnorows", "uncheckedexec", "contextmethods", "swappedargs", "duplicateargs", "unusedqueries", "duplicatequeries"}},
+	}
+	sum := 0
+	for _, sub := range subs {
+		for _, r := range analysistest.Run(ignoreErrors{}, testdata, sub.a, "a") {
+			for _, d := range r.Diagnostics {
+				found := false
+				for _, c := range sub.categories {
+					found = found || d.Category == c
+				}
+				if !found {
+					t.Errorf("%s reported %q of category %s", sub.a.Name, d.Message, d.Category)
+				}
+			}
+			sum += len(r.Diagnostics)
+		}
+	}
+	if sum != all {
+		t.Errorf("the sub-analyzers reported %d diagnostics, want the %d of sqlargs", sum, all)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("severity", "arity=warning, semantics=info, syntax=off")
+	defer sqlargs.Analyzer.Flags.Set("severity", "arity=error,semantics=error,syntax=error")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "severity")
+}
+
+func TestSeverityFlag(t *testing.T) {
+	for _, value := range []string{"selectstar", "nosuch=warning", "policy=warning", "selectstar=fatal"} {
+		if err := sqlargs.Analyzer.Flags.Set("severity", value); err == nil {
+			t.Errorf("-severity=%s: want an error", value)
+		}
+	}
+}
+
+func TestSanitizers(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("sanitizers", "sanitizers.quoteIdent, sanitizers.table.quoted")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sanitizers")
+}
+
+func TestInjectionCategory(t *testing.T) {
+	testdata := analysistest.TestData()
+	for _, r := range analysistest.Run(t, testdata, sqlargs.Analyzer, "sprintffix") {
+		for _, d := range r.Diagnostics {
+			if d.Category != "sqlinjection" {
+				t.Errorf("diagnostic %q has category %q, want sqlinjection", d.Message, d.Category)
+			}
+		}
+	}
+}
+
+func TestCategories(t *testing.T) {
+	testdata := analysistest.TestData()
+	for _, r := range analysistest.Run(t, testdata, sqlargs.Analyzer, "a", "mysql", "oracle", "sqlserver", "sqlite") {
+		for _, d := range r.Diagnostics {
+			if d.Category == "" {
+				t.Errorf("diagnostic %q has no category", d.Message)
+			}
+		}
+	}
+}
+
+func TestRelated(t *testing.T) {
+	testdata := analysistest.TestData()
+	var related []string
+	for _, r := range analysistest.Run(t, testdata, sqlargs.Analyzer, "related") {
+		for _, d := range r.Diagnostics {
+			for _, info := range d.Related {
+				related = append(related, info.Message)
+			}
+		}
+	}
+	want := []string{"Last placeholder without an arg: $3", "Arg 2 has no placeholder", "Arg 3 has no placeholder"}
+	if strings.Join(related, "\n") != strings.Join(want, "\n") {
+		t.Errorf("related information is %q, want %q", related, want)
+	}
+}
+
+func TestRenumberFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, sqlargs.Analyzer, "renumber")
+}
+
+func TestArgsFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, sqlargs.Analyzer, "argsfix")
+}
+
+func TestSpreadFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, sqlargs.Analyzer, "spreadfix")
+}
+
+func TestSprintfFix(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, sqlargs.Analyzer, "sprintffix")
+}
+
+func TestGroupBy(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("group-by", "true")
+	defer sqlargs.Analyzer.Flags.Set("group-by", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "groupby")
+	// The findings are in a category of their own, reported by Policy.
+	analysistest.Run(t, testdata, sqlargs.Policy, "groupby")
+}
+
+func TestSelectStar(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("select-star", "true")
+	defer sqlargs.Analyzer.Flags.Set("select-star", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "selectstar")
+}
+
+func TestLoopQueries(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("loop-queries", "true")
+	defer sqlargs.Analyzer.Flags.Set("loop-queries", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "loopqueries")
+}
+
+func TestErrNoRows(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("err-no-rows", "true")
+	defer sqlargs.Analyzer.Flags.Set("err-no-rows", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "errnorows")
+}
+
+func TestUncheckedExec(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("unchecked-exec", "true")
+	defer sqlargs.Analyzer.Flags.Set("unchecked-exec", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "uncheckedexec")
+}
+
+func TestDDL(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("ddl", "true")
+	defer sqlargs.Analyzer.Flags.Set("ddl", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "ddl")
+}
+
+func TestDDLPlaceholders(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "ddlparams")
+}
+
+func TestStructDest(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sqlxdest")
+}
+
+func TestDuplicateQueries(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("duplicate-queries", "true")
+	defer sqlargs.Analyzer.Flags.Set("duplicate-queries", "false")
+
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, sqlargs.Analyzer, "duplicates")
+	want := []string{"duplicates.go:9:7: Query defined here", "dep.go:8:18: Query defined here"}
+	if got := relatedPositions(results); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("related information is %q, want %q", got, want)
+	}
+}
+
+func TestUnusedQueries(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("unused-queries", "true")
+	defer sqlargs.Analyzer.Flags.Set("unused-queries", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "unusedqueries", "unusedmain")
+}
+
+func TestDuplicateArgs(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("duplicate-args", "true")
+	defer sqlargs.Analyzer.Flags.Set("duplicate-args", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "duplicateargs")
+}
+
+func TestSwappedArgs(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("swapped-args", "true")
+	defer sqlargs.Analyzer.Flags.Set("swapped-args", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "swappedargs")
+}
+
+func TestSQLConstants(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("sql-constants", "true")
+	defer sqlargs.Analyzer.Flags.Set("sql-constants", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sqlconstants")
+}
+
+func TestContextArgs(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "contextargs")
+}
+
+func TestContextMethods(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("context-methods", "true")
+	defer sqlargs.Analyzer.Flags.Set("context-methods", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, sqlargs.Analyzer, "contextmethods")
+}
+
+func TestInsertColumns(t *testing.T) {
+	sqlargs.Analyzer.Flags.Set("insert-columns", "true")
+	defer sqlargs.Analyzer.Flags.Set("insert-columns", "false")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "insertcolumns")
+}
+
+func TestSchema(t *testing.T) {
+	testdata := analysistest.TestData()
+	sqlargs.Analyzer.Flags.Set("schema", filepath.Join(testdata, "src", "schema", "schema.sql"))
+	defer sqlargs.Analyzer.Flags.Set("schema", "")
+
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "schema")
+}
+
+func TestMigrations(t *testing.T) {
+	testdata := analysistest.TestData()
+	sqlargs.Analyzer.Flags.Set("schema", filepath.Join(testdata, "src", "migrations", "db", "migrate"))
+	defer sqlargs.Analyzer.Flags.Set("schema", "")
+
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "migrations")
+}
+
+func TestSQLC(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sqlc")
+}
+
+func TestQuestionMarks(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "mysql")
+}
+
+func TestDialect(t *testing.T) {
+	defer sqlargs.Analyzer.Flags.Set("dialect", "")
+
+	testdata := analysistest.TestData()
+	for _, dialect := range []string{"mysql", "postgres"} {
+		sqlargs.Analyzer.Flags.Set("dialect", dialect)
+		analysistest.Run(t, testdata, sqlargs.Analyzer, "dialect/"+dialect)
+	}
+}
+
+func TestDetectDialect(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "detect/pq", "detect/mysql", "detect/ambiguous", "detect/store", "detect/sqlxopen", "detect/azuread")
+}
+
+func TestOracle(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "oracle")
+}
+
+func TestSnowflake(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "snowflake")
+}
+
+func TestClickHouse(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "clickhouse")
+}
+
+func TestSQLServer(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sqlserver")
+}
+
+func TestMSSQL(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "mssql")
+}
+
+func TestSQLite(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "sqlite")
+}
+
+func TestBigQuery(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "bigquery")
+}
+
+func TestArrays(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "arrays")
+}
+
+// liveDriver is a database/sql driver whose connections reject the queries
+// using the table missing.
+type liveDriver struct{}
+
+func (liveDriver) Open(name string) (driver.Conn, error) { return liveConn{}, nil }
+
+type liveConn struct{}
+
+func (liveConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "missing") {
+		return nil, errors.New(`pq: relation "missing" does not exist`)
+	}
+	return liveStmt{}, nil
+}
+
+func (liveConn) Close() error              { return nil }
+func (liveConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type liveStmt struct{}
+
+func (liveStmt) Close() error  { return nil }
+func (liveStmt) NumInput() int { return -1 }
+func (liveStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (liveStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestLive(t *testing.T) {
+	sql.Register("sqlargstest", liveDriver{})
+	sqlargs.Analyzer.Flags.Set("dsn", "sqlargstest://localhost/db")
+	defer sqlargs.Analyzer.Flags.Set("dsn", "")
+
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "live")
+}
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	prev := sqlargs.Analyzer.Flags.Lookup("cache").Value.String()
+	sqlargs.Analyzer.Flags.Set("cache", dir)
+	defer sqlargs.Analyzer.Flags.Set("cache", prev)
+
+	// The default backend does not parse the queries, so there is nothing to
+	// cache without another one.
+	if err := sqlargs.RegisterParser("cached", upsertParser{}); err != nil {
+		t.Fatal(err)
+	}
+	a, err := sqlargs.NewAnalyzer(sqlargs.Options{Parser: "cached"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The second run uses the results cached by the first one.
+	testdata := analysistest.TestData()
+	for i := 0; i < 2; i++ {
+		analysistest.Run(t, testdata, a, "a")
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "queries"))
+	if err != nil || len(entries) == 0 {
+		t.Errorf("no queries cached in %s: %v", dir, err)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	dir := t.TempDir()
+	testdata := analysistest.TestData()
+	sqlargs.Analyzer.Flags.Set("schema", filepath.Join(testdata, "src", "schema", "schema.sql"))
+	sqlargs.Analyzer.Flags.Set("drift", dir)
+	defer sqlargs.Analyzer.Flags.Set("schema", "")
+	defer sqlargs.Analyzer.Flags.Set("drift", "")
+
+	analysistest.Run(t, testdata, sqlargs.Analyzer, "schema")
+	var report strings.Builder
+	if err := sqlargs.DriftReport(dir, &report); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"schema.go:16:10: Unknown table accounts at offset 15",
+		"Unused columns (1):\n\torders.\"Note\"\n",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report does not contain %q:\n%s", want, report.String())
+		}
+	}
+}
+
+// jsonOutput is -json output of the analyzer, for TestSARIF and
+// TestGitHubAnnotations.
+const jsonOutput = `{
+	"example.com/users": {
+		"sqlargs": [
+			{"category": "argcount", "posn": "/src/users.go:10:5", "message": "No. of args (1) is less than no. of params (2)"},
+			{"category": "placeholder-style", "posn": "/src/users.go:12:33", "end": "/src/users.go:12:35", "message": "warning: Placeholder $1 is not valid for mysql queries"}
+		]
+	},
+	"example.com/broken": {
+		"sqlargs": {"error": "cannot load schema"}
+	}
+}`
+
+func TestSARIF(t *testing.T) {
+	var sarif bytes.Buffer
+	if err := sqlargs.SARIF(strings.NewReader(jsonOutput), &sarif); err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct{ ID string }
+				}
+			}
+			Results []struct {
+				RuleID    string
+				Level     string
+				Message   struct{ Text string }
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+						Region           struct{ StartLine, StartColumn, EndLine, EndColumn int }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(sarif.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) == 0 || run.Tool.Driver.Rules[0].ID != "argcount" {
+		t.Errorf("got rules %v, want the categories", run.Tool.Driver.Rules)
+	}
+	if len(run.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(run.Results))
+	}
+	got := run.Results[1]
+	region := got.Locations[0].PhysicalLocation.Region
+	if got.RuleID != "placeholder-style" || got.Level != "warning" || got.Message.Text != "Placeholder $1 is not valid for mysql queries" {
+		t.Errorf("got result %+v", got)
+	}
+	if region.StartLine != 12 || region.StartColumn != 33 || region.EndLine != 12 || region.EndColumn != 35 {
+		t.Errorf("got region %+v, want 12:33 to 12:35", region)
+	}
+}
+
+func TestGitHubAnnotations(t *testing.T) {
+	var out strings.Builder
+	if err := sqlargs.GitHubAnnotations(strings.NewReader(jsonOutput), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "::error file=/src/users.go,line=10,col=5,title=sqlargs argcount::No. of args (1) is less than no. of params (2)\n" +
+		"::warning file=/src/users.go,line=12,col=33,endLine=12,endColumn=35,title=sqlargs placeholder-style::Placeholder $1 is not valid for mysql queries\n"
+	if out.String() != want {
+		t.Errorf("got annotations:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,179 @@
+package sqlargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// sqlcConfig is what the analyzer uses of a sqlc configuration file.
+type sqlcConfig struct {
+	// schemas are the paths of the schema files and directories.
+	schemas []string
+	// dialect is the dialect of the engine, or nil if it is not known.
+	dialect *dialect
+}
+
+// sqlcFiles are the names of the sqlc configuration files.
+var sqlcFiles = []string{"sqlc.yaml", "sqlc.yml", "sqlc.json"}
+
+// sqlcEngines maps the sqlc engines to their dialect.
+var sqlcEngines = map[string]string{
+	"postgresql": "postgres",
+	"mysql":      "mysql",
+	"sqlite":     "sqlite",
+}
+
+// findSQLCConfig returns the sqlc configuration of the package of pass, found
+// in its directory or the closest parent one, up to the root of its module. It
+// returns nil if there is none.
+func findSQLCConfig(pass *analysis.Pass) (*sqlcConfig, error) {
+	if len(pass.Files) == 0 {
+		return nil, nil
+	}
+	dir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+	for {
+		for _, name := range sqlcFiles {
+			path := filepath.Join(dir, name)
+			data, err := os.ReadFile(path)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("reading sqlc configuration: %v", err)
+			}
+			conf, err := parseSQLCConfig(name, data)
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %v", path, err)
+			}
+			for i, schema := range conf.schemas {
+				if !filepath.IsAbs(schema) {
+					conf.schemas[i] = filepath.Join(dir, schema)
+				}
+			}
+			return conf, nil
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return nil, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// parseSQLCConfig parses the sqlc configuration file name, in either the
+// version 1 or 2 format. The schemas and engines of all the packages are used.
+func parseSQLCConfig(name string, data []byte) (*sqlcConfig, error) {
+	var schemas, engines []string
+	if strings.HasSuffix(name, ".json") {
+		var file struct {
+			SQL      []sqlcPackage `json:"sql"`
+			Packages []sqlcPackage `json:"packages"`
+		}
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, err
+		}
+		for _, p := range append(file.SQL, file.Packages...) {
+			schemas = append(schemas, p.Schema...)
+			engines = append(engines, p.Engine)
+		}
+	} else {
+		schemas, engines = yamlValues(string(data), "schema"), yamlValues(string(data), "engine")
+	}
+	conf := &sqlcConfig{schemas: schemas}
+	for _, engine := range engines {
+		if name, ok := sqlcEngines[engine]; ok {
+			conf.dialect = dialects[name]
+			break
+		}
+	}
+	return conf, nil
+}
+
+// sqlcPackage is a package of a sqlc.json file.
+type sqlcPackage struct {
+	Engine string      `json:"engine"`
+	Schema sqlcStrings `json:"schema"`
+}
+
+// sqlcStrings is a string, or a list of strings.
+type sqlcStrings []string
+
+func (s *sqlcStrings) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*s = []string{one}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(s))
+}
+
+// yamlValues returns the values of the mapping key in the YAML document doc.
+// It only understands the subset of YAML sqlc configurations are written in:
+// scalars, flow sequences like [a, b] and block sequences of scalars.
+func yamlValues(doc, key string) []string {
+	var values []string
+	lines := strings.Split(doc, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := yamlLine(lines[i])
+		indent := len(line) - len(strings.TrimLeft(line, " -"))
+		rest := strings.TrimLeft(line, " -")
+		if !strings.HasPrefix(rest, key+":") {
+			continue
+		}
+		value := strings.TrimSpace(rest[len(key)+1:])
+		switch {
+		case strings.HasPrefix(value, "["):
+			for _, v := range strings.Split(strings.Trim(value, "[]"), ",") {
+				if v = yamlScalar(v); v != "" {
+					values = append(values, v)
+				}
+			}
+		case value != "":
+			values = append(values, yamlScalar(value))
+		default:
+			// A block sequence, indented deeper than the key.
+			for i+1 < len(lines) {
+				item := yamlLine(lines[i+1])
+				trimmed := strings.TrimSpace(item)
+				if trimmed == "" {
+					i++
+					continue
+				}
+				if !strings.HasPrefix(trimmed, "- ") || len(item)-len(strings.TrimLeft(item, " ")) < indent {
+					break
+				}
+				values = append(values, yamlScalar(trimmed[2:]))
+				i++
+			}
+		}
+	}
+	return values
+}
+
+// yamlLine returns line without its comment.
+func yamlLine(line string) string {
+	if i := strings.Index(line, " #"); i >= 0 {
+		line = line[:i]
+	}
+	if strings.HasPrefix(strings.TrimSpace(line), "#") {
+		return ""
+	}
+	return strings.TrimRight(line, " \t\r")
+}
+
+// yamlScalar returns the value of the possibly quoted YAML scalar s.
+func yamlScalar(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,165 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkSQLDirectives checks the queries of the constants and variables whose
+// declaration has a //sqlargs:sql comment, optionally followed by
+// dialect=name, like the queries passed through layers which are not
+// recognized. Without a dialect, the one of the file or of the package is
+// used. There is no call to count the args of, so only the syntax of the
+// queries is checked. The constants run by calls, which are checked there,
+// are not checked again. With -sql-constants, the constants which look like
+// queries are checked too, without a directive.
+func checkSQLDirectives(cfg *config, d *dialect, directives *dialectDirectives, called map[*types.Const]bool, skipped map[*ast.File]bool, parsed parseMemo, pass *analysis.Pass) {
+	for _, f := range pass.Files {
+		if skipped[f] {
+			continue
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST && gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.ValueSpec)
+				c, opts, directive := sqlDirective(spec.Doc, spec.Comment, gen.Doc)
+				if !directive && (!cfg.sqlConstants || gen.Tok != token.CONST) {
+					continue
+				}
+				var selected *dialect
+				if directive {
+					if selected, ok = directiveDialect(c, opts, pass); !ok {
+						continue
+					}
+				}
+				if len(spec.Values) != len(spec.Names) {
+					continue
+				}
+				for i, name := range spec.Names {
+					if k, ok := pass.TypesInfo.Defs[name].(*types.Const); ok && called[k] {
+						continue
+					}
+					value := spec.Values[i]
+					typ, ok := pass.TypesInfo.Types[value]
+					if !ok || typ.Value == nil || typ.Value.Kind() != constant.String {
+						if directive {
+							reportf(pass, catDirective, name.Pos(), "%s is not a constant string: //sqlargs:sql only checks constant queries", name.Name)
+						}
+						continue
+					}
+					query := constant.StringVal(typ.Value)
+					if !directive && !looksLikeQuery(query, d) {
+						continue
+					}
+					// The checks report on the query of a call.
+					call := &ast.CallExpr{Fun: name, Lparen: value.Pos(), Args: []ast.Expr{value}, Rparen: value.End()}
+					qd := selected
+					if qd == nil {
+						qd = directives.dialect(pass.Fset, call, d)
+					}
+					checkSQLQuery(cfg, query, qd, call, parsed, pass)
+				}
+			}
+		}
+	}
+}
+
+// sqlDirective returns the first //sqlargs:sql comment of groups, along with
+// the options following it.
+func sqlDirective(groups ...*ast.CommentGroup) (*ast.Comment, string, bool) {
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, c := range group.List {
+			rest, ok := cutDirective(strings.TrimPrefix(c.Text, "//"), "sqlargs:sql")
+			if ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+				return c, rest, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// directiveDialect returns the dialect selected by the options of the
+// //sqlargs:sql comment c, which is nil if there is none. It reports the
+// options which are not valid, and returns false if there are any.
+func directiveDialect(c *ast.Comment, opts string, pass *analysis.Pass) (*dialect, bool) {
+	var d *dialect
+	for _, opt := range strings.Fields(opts) {
+		value := strings.TrimPrefix(opt, "dialect=")
+		if value == opt {
+			reportf(pass, catDirective, c.Slash, "Unknown option %q in //sqlargs:sql: expected dialect=name", opt)
+			return nil, false
+		}
+		var ok bool
+		if d, ok = dialects[value]; !ok {
+			reportf(pass, catDirective, c.Slash, "Unknown dialect %q in //sqlargs:sql: must be one of %s", value, dialectNames())
+			return nil, false
+		}
+	}
+	return d, true
+}
+
+// queryShapes maps the keywords starting the statements looked for by
+// -sql-constants to a keyword the statement must also have, so that messages
+// like "Update the profile" are not mistaken for queries.
+var queryShapes = map[string]string{
+	"SELECT": "FROM",
+	"INSERT": "INTO",
+	"UPDATE": "SET",
+	"DELETE": "FROM",
+	"WITH":   "AS",
+}
+
+// looksLikeQuery reports whether query starts with a SELECT, INSERT, UPDATE,
+// DELETE or WITH statement in dialect d. Strings with a %, which may be fmt
+// formats, are left out.
+func looksLikeQuery(query string, d *dialect) bool {
+	if strings.Contains(query, "%") {
+		return false
+	}
+	stmts := statements(query, d)
+	if len(stmts) == 0 || stmts[0][0].kind != lexWord {
+		return false
+	}
+	want, ok := queryShapes[strings.ToUpper(stmts[0][0].text)]
+	if !ok {
+		return false
+	}
+	for _, l := range stmts[0][1:] {
+		if l.kind == lexWord && strings.EqualFold(l.text, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSQLQuery runs the checks of the constant queries which do not depend
+// on the args of a call on query, and validates it with the parser.
+func checkSQLQuery(cfg *config, query string, d *dialect, call *ast.CallExpr, parsed parseMemo, pass *analysis.Pass) {
+	analyze, parse := checkConstantQuery(cfg, query, d, call, pass)
+	if !analyze {
+		return
+	}
+	if d != permissive && !checkForeignStyle(query, d, call, pass) {
+		parse = false
+	}
+	params, style := placeholders(query, d)
+	if style == styleMixed {
+		checkMixedStyles(params, call, pass)
+		return
+	}
+	checkIndices(params, call, pass)
+	if parse {
+		checkSyntax(cfg, query, d, call, parsed, pass)
+	}
+}
@@ -0,0 +1,178 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// sqlmockPath is the import path of go-sqlmock.
+const sqlmockPath = "github.com/DATA-DOG/go-sqlmock"
+
+// checkSQLMock checks the ExpectQuery and ExpectExec expectations of
+// go-sqlmock in the test files of pass against the constant queries of the
+// code: the ones of queries outside the test files, and the ones of the
+// packages it depends on. An expectation matching none of them has drifted
+// away from the implementation, and so has one whose WithArgs has a no. of
+// args which none of the matched queries takes.
+func checkSQLMock(queries *Queries, skipped map[*ast.File]bool, inspect *inspector.Inspector, pass *analysis.Pass) {
+	imported := false
+	for _, imp := range pass.Pkg.Imports() {
+		if imp.Path() == sqlmockPath {
+			imported = true
+		}
+	}
+	if !imported {
+		return
+	}
+	var code []Query
+	for _, q := range queries.Queries {
+		if !strings.Contains(q.Pos, "_test.go:") {
+			code = append(code, q)
+		}
+	}
+	for _, f := range pass.AllPackageFacts() {
+		if deps, ok := f.Fact.(*Queries); ok && deps != queries {
+			code = append(code, deps.Queries...)
+		}
+	}
+	inspect.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		file := stack[0].(*ast.File)
+		if skipped[file] || !strings.HasSuffix(pass.Fset.File(file.Pos()).Name(), "_test.go") {
+			return false
+		}
+		call := n.(*ast.CallExpr)
+		if !isSQLMockExpect(call, pass.TypesInfo) || len(call.Args) != 1 {
+			return true
+		}
+		match, ok := mockMatcher(call.Args[0], pass.TypesInfo)
+		if !ok {
+			return true
+		}
+		var matched []Query
+		for _, q := range code {
+			if match(q.Text) {
+				matched = append(matched, q)
+			}
+		}
+		if len(matched) == 0 {
+			reportf(pass, catMock, call.Args[0].Pos(), "sqlmock expectation matches no query of the code: it may have drifted away from the implementation")
+			return true
+		}
+		args, ok := withArgsCount(stack)
+		if !ok {
+			return true
+		}
+		// The expectation is fine if one of the queries it matches takes its
+		// no. of args.
+		want := -1
+		for _, q := range matched {
+			params, err := ParseQuery(q.Text, nil)
+			if err != nil || params.Args == args {
+				return true
+			}
+			if want < 0 {
+				want = params.Args
+			}
+		}
+		diag := analysis.Diagnostic{
+			Pos:      call.Args[0].Pos(),
+			Category: catMock,
+			Message:  fmt.Sprintf("sqlmock expectation has %d args in WithArgs, but the query it matches has %d params", args, want),
+		}
+		if pos := factPos(pass, matched[0].Pos); pos.IsValid() {
+			diag.Related = []analysis.RelatedInformation{{Pos: pos, Message: "Query matched by the expectation"}}
+		}
+		pass.Report(diag)
+		return true
+	})
+}
+
+// isSQLMockExpect reports whether call is a call of the ExpectQuery or
+// ExpectExec method of go-sqlmock.
+func isSQLMockExpect(call *ast.CallExpr, info *types.Info) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "ExpectQuery" && sel.Sel.Name != "ExpectExec" {
+		return false
+	}
+	f, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && f.Pkg() != nil && f.Pkg().Path() == sqlmockPath
+}
+
+// mockWhitespace is the whitespace go-sqlmock collapses before matching.
+var mockWhitespace = regexp.MustCompile(`\s+`)
+
+// stripMockQuery normalizes the whitespace of query, as go-sqlmock does.
+func stripMockQuery(query string) string {
+	return strings.TrimSpace(mockWhitespace.ReplaceAllString(query, " "))
+}
+
+// mockMatcher returns the matcher of the expected query expr, as go-sqlmock
+// matches queries by default: a regexp, which is often a constant quoted with
+// regexp.QuoteMeta. It returns false if the query is not known statically.
+func mockMatcher(expr ast.Expr, info *types.Info) (func(query string) bool, bool) {
+	pattern, ok := constantString(expr, info)
+	if !ok {
+		call, isCall := expr.(*ast.CallExpr)
+		if !isCall || len(call.Args) != 1 || !isQuoteMeta(call.Fun, info) {
+			return nil, false
+		}
+		if pattern, ok = constantString(call.Args[0], info); !ok {
+			return nil, false
+		}
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	re, err := regexp.Compile(stripMockQuery(pattern))
+	if err != nil {
+		// The expectation is not a regexp, so go-sqlmock must be set to
+		// compare the queries.
+		expected := stripMockQuery(pattern)
+		return func(query string) bool { return stripMockQuery(query) == expected }, true
+	}
+	return func(query string) bool { return re.MatchString(stripMockQuery(query)) }, true
+}
+
+// constantString returns the value of expr if it is a constant string.
+func constantString(expr ast.Expr, info *types.Info) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+// isQuoteMeta reports whether fun is regexp.QuoteMeta.
+func isQuoteMeta(fun ast.Expr, info *types.Info) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	f, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && f.Pkg() != nil && f.Pkg().Path() == "regexp" && f.Name() == "QuoteMeta"
+}
+
+// withArgsCount returns the no. of args of the WithArgs call chained to the
+// expectation at the top of stack, if there is one and its args are listed.
+func withArgsCount(stack []ast.Node) (int, bool) {
+	if len(stack) < 3 {
+		return 0, false
+	}
+	sel, ok := stack[len(stack)-2].(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "WithArgs" {
+		return 0, false
+	}
+	call, ok := stack[len(stack)-3].(*ast.CallExpr)
+	if !ok || call.Fun != sel || call.Ellipsis.IsValid() {
+		return 0, false
+	}
+	return len(call.Args), true
+}
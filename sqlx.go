@@ -0,0 +1,178 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// sqlxPath is the import path of sqlx.
+const sqlxPath = "github.com/jmoiron/sqlx"
+
+// sqlxQueries maps the methods of sqlx.DB and sqlx.Tx running queries to the
+// index of their query in their args, which is followed by the args of the
+// query.
+var sqlxQueries = map[string]int{
+	"Get":              1,
+	"GetContext":       2,
+	"Queryx":           0,
+	"QueryxContext":    1,
+	"QueryRowx":        0,
+	"QueryRowxContext": 1,
+	"Select":           1,
+	"SelectContext":    2,
+}
+
+// sqlxCall returns call with the query as its first arg, followed by the args
+// of the query, along with the name of the method run, if it runs a query
+// with a method of sqlx.
+func sqlxCall(call *ast.CallExpr, info *types.Info) (*ast.CallExpr, string, bool) {
+	method, recv, ok := dbMethod(call, info)
+	if !ok || recv != "DB" && recv != "Tx" {
+		return nil, "", false
+	}
+	i, ok := sqlxQueries[method]
+	if !ok || len(call.Args) <= i {
+		return nil, "", false
+	}
+	query := *call
+	query.Args = call.Args[i:]
+	return &query, method, true
+}
+
+// checkStructDest reports the columns of query which have no field in the
+// struct their rows are scanned into by orig, the call of the sqlx method, as
+// sqlx then fails with a "missing destination name" error. The destinations
+// are the one of Get, the elements of the slice of Select, and the ones of
+// the StructScan calls on the rows of Queryx and QueryRowx. call is the query
+// call of orig, as returned by sqlxCall.
+func checkStructDest(query string, d *dialect, call, orig *ast.CallExpr, method string, stack []ast.Node, pass *analysis.Pass) {
+	// dests are the pointers to the destinations of the rows.
+	var dests []types.Type
+	switch method {
+	case "Get", "GetContext":
+		dests = append(dests, pass.TypesInfo.TypeOf(orig.Args[sqlxQueries[method]-1]))
+	case "Select", "SelectContext":
+		dest := orig.Args[sqlxQueries[method]-1]
+		elem, ok := sliceElem(pass.TypesInfo.TypeOf(dest))
+		if !ok {
+			reportf(pass, catArgType, dest.Pos(), "Destination of %s is a %s: sqlx needs a pointer to a slice", method, types.TypeString(pass.TypesInfo.TypeOf(dest), types.RelativeTo(pass.Pkg)))
+			return
+		}
+		if elem == nil {
+			return
+		}
+		dests = append(dests, types.NewPointer(elem))
+	default:
+		for _, scan := range scanCalls(orig, "StructScan", stack, enclosingBody(stack), pass.TypesInfo) {
+			if len(scan.Args) == 1 {
+				dests = append(dests, pass.TypesInfo.TypeOf(scan.Args[0]))
+			}
+		}
+	}
+	if len(dests) == 0 {
+		return
+	}
+	names, ok := resultNames(query, d)
+	if !ok {
+		return
+	}
+	reported := make(map[string]bool)
+	for _, dest := range dests {
+		fields, ok := destFields(dest)
+		if !ok {
+			continue
+		}
+		typ := types.TypeString(dest.(*types.Pointer).Elem(), types.RelativeTo(pass.Pkg))
+		for _, l := range names {
+			name := strings.ToLower(normalizeIdent(l))
+			if fields[name] || reported[name] {
+				continue
+			}
+			reported[name] = true
+			reportQuery(pass, call, catArity, l.pos, len(l.text), "Column %s has no destination in %s: sqlx fails with missing destination name, tag a field with `db:\"%s\"`", l.text, typ, name)
+		}
+	}
+}
+
+// sliceElem returns the type of the elements of the slice pointed to by t,
+// dereferenced if they are pointers, as sqlx scans into both []T and []*T. It
+// returns false if t is not a pointer to a slice, and a nil type if its
+// elements are not known, like for an interface{} passed through.
+func sliceElem(t types.Type) (types.Type, bool) {
+	if t == nil || types.IsInterface(t) {
+		return nil, true
+	}
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return nil, false
+	}
+	slice, ok := ptr.Elem().Underlying().(*types.Slice)
+	if !ok {
+		return nil, false
+	}
+	elem := slice.Elem()
+	if ptr, ok := elem.(*types.Pointer); ok {
+		elem = ptr.Elem()
+	}
+	return elem, true
+}
+
+// destFields returns the lower cased names of the columns which sqlx scans
+// into the struct pointed to by t: the db tags of its fields, or the names of
+// the untagged ones, including the fields of embedded structs. It returns
+// false if t is not a pointer to a struct sqlx scans into field by field,
+// like a time.Time or a sql.Scanner, whose row has a single column.
+func destFields(t types.Type) (map[string]bool, bool) {
+	ptr, ok := t.(*types.Pointer)
+	if !ok {
+		return nil, false
+	}
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+	if !ok {
+		return nil, false
+	}
+	if scan, _, _ := types.LookupFieldOrMethod(ptr, true, nil, "Scan"); scan != nil {
+		return nil, false
+	}
+	fields := make(map[string]bool)
+	addFields(st, fields, make(map[*types.Struct]bool))
+	return fields, len(fields) > 0
+}
+
+// addFields adds the names of the columns scanned into the fields of st to
+// fields, as for destFields. seen are the structs already added, as embedded
+// pointers can be recursive.
+func addFields(st *types.Struct, fields map[string]bool, seen map[*types.Struct]bool) {
+	if seen[st] {
+		return
+	}
+	seen[st] = true
+	for i := 0; i < st.NumFields(); i++ {
+		f := st.Field(i)
+		if !f.Exported() && !f.Embedded() {
+			continue
+		}
+		name := strings.Split(reflect.StructTag(st.Tag(i)).Get("db"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Embedded() && name == "" {
+			t := f.Type()
+			if ptr, ok := t.(*types.Pointer); ok {
+				t = ptr.Elem()
+			}
+			if embedded, ok := t.Underlying().(*types.Struct); ok {
+				addFields(embedded, fields, seen)
+				continue
+			}
+		}
+		if name == "" {
+			name = f.Name()
+		}
+		fields[strings.ToLower(name)] = true
+	}
+}
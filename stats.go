@@ -0,0 +1,105 @@
+package sqlargs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// statsDir is the directory selected with the -stats flag, where the
+// statistics of each package are written.
+var statsDir string
+
+// packageStats are the statistics of the analysis of a package, as written to
+// the -stats directory.
+type packageStats struct {
+	Package string
+	// Queries is the no. of queries which are known statically, and so
+	// analyzed.
+	Queries int
+	// Dynamic is the no. of queries which cannot be determined statically,
+	// and so are skipped.
+	Dynamic int
+	// Findings are the no. of findings reported by category.
+	Findings map[string]int
+}
+
+// hasStats reports whether the statistics of the package of queries are
+// written. Packages without queries, like most of the dependencies which are
+// only analyzed for their facts, and the packages running the queries, like
+// database/sql, whose calls wrap the ones of their importers, would only
+// dilute the coverage.
+func hasStats(queries *Queries, dynamic int) bool {
+	return (len(queries.Queries) > 0 || dynamic > 0) && !matchesImport(queries.Package, defaultImports)
+}
+
+// writeStats writes the statistics of the package of pass to dir.
+func writeStats(dir string, queries *Queries, dynamic int, found *findings) error {
+	stats := packageStats{Package: queries.Package, Queries: len(queries.Queries), Dynamic: dynamic, Findings: make(map[string]int)}
+	for _, d := range found.diagnostics {
+		stats.Findings[d.Category]++
+	}
+	data, err := json.MarshalIndent(stats, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("-stats: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, cacheKey(queries.Package)+".json"), data, 0o644); err != nil {
+		return fmt.Errorf("-stats: %v", err)
+	}
+	return nil
+}
+
+// StatsReport writes to w the summary of the statistics written to the -stats
+// directory dir: the packages scanned, the queries analyzed or skipped as
+// dynamic, and the findings by category, to track the coverage of the
+// verification over time.
+func StatsReport(dir string, w io.Writer) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no statistics in %s: run sqlargs -stats=%s first", dir, dir)
+	}
+	var total packageStats
+	total.Findings = make(map[string]int)
+	findings := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var s packageStats
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		total.Queries += s.Queries
+		total.Dynamic += s.Dynamic
+		for category, n := range s.Findings {
+			total.Findings[category] += n
+			findings += n
+		}
+	}
+	fmt.Fprintf(w, "Packages scanned: %d\n", len(files))
+	fmt.Fprintf(w, "Queries analyzed: %d\n", total.Queries)
+	fmt.Fprintf(w, "Queries skipped as dynamic: %d\n", total.Dynamic)
+	if all := total.Queries + total.Dynamic; all > 0 {
+		fmt.Fprintf(w, "Coverage: %.1f%%\n", 100*float64(total.Queries)/float64(all))
+	}
+	fmt.Fprintf(w, "Findings: %d\n", findings)
+	var categories []string
+	for category := range total.Findings {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		fmt.Fprintf(w, "\t%s: %d\n", category, total.Findings[category])
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// dbMethod returns the name of the method called by call, and of the type of
+// its receiver, like "DB" for sql.DB, if it is a method of database/sql or
+// sqlx.
+func dbMethod(call *ast.CallExpr, info *types.Info) (method, recv string, ok bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", false
+	}
+	f, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || f.Pkg() == nil {
+		return "", "", false
+	}
+	if path := f.Pkg().Path(); path != "database/sql" && path != sqlxPath {
+		return "", "", false
+	}
+	r := f.Type().(*types.Signature).Recv()
+	if r == nil {
+		return "", "", false
+	}
+	ptr, ok := r.Type().(*types.Pointer)
+	if !ok {
+		return "", "", false
+	}
+	n, ok := ptr.Elem().(*types.Named)
+	if !ok {
+		return "", "", false
+	}
+	return f.Name(), n.Obj().Name(), true
+}
+
+// isPrepareCall reports whether call prepares a statement, with the Prepare
+// methods of sql.DB, sql.Tx and sql.Conn, or the Preparex ones of sqlx.
+func isPrepareCall(call *ast.CallExpr, info *types.Info) bool {
+	method, recv, ok := dbMethod(call, info)
+	if !ok || recv != "DB" && recv != "Tx" && recv != "Conn" {
+		return false
+	}
+	switch method {
+	case "Prepare", "PrepareContext", "Preparex", "PreparexContext":
+		return true
+	}
+	return false
+}
+
+// checkStmtClose reports the statement prepared by call which is never
+// closed, as it holds resources of the database until it is. Statements which
+// escape the function, like the ones stored in the fields of a struct to be
+// reused, are left to their owner.
+func checkStmtClose(call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	body := enclosingBody(stack)
+	obj, assign, end, ok := localVar(call, stack, body, pass.TypesInfo)
+	if !ok {
+		return
+	}
+	if closes, _ := methodCalls(obj, "Close", body, assign.End(), end, pass.TypesInfo); len(closes) == 0 {
+		reportf(pass, catStmt, assign.Lhs[0].Pos(), "Statement is never closed: defer %s.Close() after checking the error, as it holds resources of the database until it is", obj.Name())
+	}
+}
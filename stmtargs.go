@@ -0,0 +1,209 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// preparedStmt is a statement prepared in the package.
+type preparedStmt struct {
+	// pos is the position of the Prepare call.
+	pos token.Pos
+	// params is the no. of positional args of the query, or -1 if it is not
+	// known.
+	params int
+}
+
+// stmtUses records the statements prepared in a package, and the calls
+// running them, by the variable or field holding them, to check that their
+// no. of args match.
+type stmtUses struct {
+	prepared map[types.Object][]preparedStmt
+	calls    map[types.Object][]*ast.CallExpr
+	// sources maps the statements bound to a transaction with tx.Stmt to the
+	// statement they were bound from, whose query they run.
+	sources map[types.Object]types.Object
+	vars    []types.Object
+}
+
+func newStmtUses() *stmtUses {
+	return &stmtUses{
+		prepared: make(map[types.Object][]preparedStmt),
+		calls:    make(map[types.Object][]*ast.CallExpr),
+		sources:  make(map[types.Object]types.Object),
+	}
+}
+
+// stmtVar returns the variable, or field, of expr.
+func stmtVar(expr ast.Expr, info *types.Info) types.Object {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return info.ObjectOf(e)
+	case *ast.SelectorExpr:
+		if v, ok := info.Uses[e.Sel].(*types.Var); ok && v.IsField() {
+			return v
+		}
+	}
+	return nil
+}
+
+// prepare records the statement prepared by call, a Prepare in dialect d,
+// given the stack of nodes enclosing it, when it is assigned to a variable or
+// a field.
+func (s *stmtUses) prepare(call *ast.CallExpr, stack []ast.Node, d *dialect, info *types.Info) {
+	obj := assignedStmt(call, stack, info)
+	if obj == nil {
+		return
+	}
+	stmt := preparedStmt{pos: call.Pos(), params: -1}
+	// PrepareContext takes the context first.
+	if query := call.Args[len(call.Args)-1]; info.Types[query].Value != nil && info.Types[query].Value.Kind() == constant.String {
+		if q, err := ParseQuery(constant.StringVal(info.Types[query].Value), d); err == nil && len(q.Names) == 0 {
+			stmt.params = q.Args
+		}
+	}
+	s.add(obj)
+	s.prepared[obj] = append(s.prepared[obj], stmt)
+}
+
+// rebind records the statement bound to a transaction by call, a Stmt or
+// StmtContext of sql.Tx, when it is assigned to a variable or a field, and
+// reports whether call is one.
+func (s *stmtUses) rebind(call *ast.CallExpr, stack []ast.Node, info *types.Info) bool {
+	if !isTxStmt(call, info) {
+		return false
+	}
+	// StmtContext takes the context first.
+	if obj, src := assignedStmt(call, stack, info), stmtVar(call.Args[len(call.Args)-1], info); obj != nil && src != nil {
+		s.add(obj)
+		s.sources[obj] = src
+	}
+	return true
+}
+
+// isTxStmt reports whether call binds a statement to a transaction, with the
+// Stmt or StmtContext methods of sql.Tx.
+func isTxStmt(call *ast.CallExpr, info *types.Info) bool {
+	method, recv, ok := dbMethod(call, info)
+	return ok && recv == "Tx" && (method == "Stmt" || method == "StmtContext") && len(call.Args) > 0
+}
+
+// assignedStmt returns the variable, or field, the statement returned by call
+// is assigned to, given the stack of nodes enclosing it.
+func assignedStmt(call *ast.CallExpr, stack []ast.Node, info *types.Info) types.Object {
+	if len(stack) < 2 {
+		return nil
+	}
+	assign, ok := stack[len(stack)-2].(*ast.AssignStmt)
+	if !ok || len(assign.Rhs) != 1 || assign.Rhs[0] != call || len(assign.Lhs) == 0 {
+		return nil
+	}
+	return stmtVar(assign.Lhs[0], info)
+}
+
+// exec records call if it runs a statement held by a variable or a field,
+// and reports whether it does.
+func (s *stmtUses) exec(call *ast.CallExpr, info *types.Info) bool {
+	method, recv, ok := dbMethod(call, info)
+	if !ok || recv != "Stmt" {
+		return false
+	}
+	switch method {
+	case "Exec", "Query", "QueryRow", "ExecContext", "QueryContext", "QueryRowContext":
+	default:
+		return false
+	}
+	stmt := call.Fun.(*ast.SelectorExpr).X
+	// A statement bound to a transaction in place, like in
+	// tx.Stmt(stmt).Exec(...), runs the query of stmt.
+	if inner, ok := stmt.(*ast.CallExpr); ok && isTxStmt(inner, info) {
+		stmt = inner.Args[len(inner.Args)-1]
+	}
+	if obj := stmtVar(stmt, info); obj != nil && !call.Ellipsis.IsValid() {
+		s.add(obj)
+		s.calls[obj] = append(s.calls[obj], call)
+	}
+	return true
+}
+
+func (s *stmtUses) add(obj types.Object) {
+	if s.prepared[obj] == nil && s.calls[obj] == nil && s.sources[obj] == nil {
+		s.vars = append(s.vars, obj)
+	}
+}
+
+// preparedOf returns the statements prepared for obj, which are the ones of
+// the statement it was bound from if it is the statement of a transaction.
+func (s *stmtUses) preparedOf(obj types.Object) []preparedStmt {
+	// The no. of sources bounds the chain, which can loop.
+	for i := 0; i <= len(s.sources); i++ {
+		src, ok := s.sources[obj]
+		if !ok || s.prepared[obj] != nil {
+			break
+		}
+		obj = src
+	}
+	return s.prepared[obj]
+}
+
+// stmtArgs returns the no. of args of call, which runs a statement.
+func stmtArgs(call *ast.CallExpr, info *types.Info) int {
+	if len(call.Args) > 0 && isContext(info.TypeOf(call.Args[0])) {
+		return len(call.Args) - 1
+	}
+	return len(call.Args)
+}
+
+// check reports the calls running a statement whose no. of args does not
+// match the params of its query. When the query is not known, and the calls
+// pass different no. of args, at most one of them is right, so they are all
+// reported.
+func (s *stmtUses) check(pass *analysis.Pass) {
+	for _, obj := range s.vars {
+		calls, prepared := s.calls[obj], s.preparedOf(obj)
+		params := -1
+		for i, p := range prepared {
+			// The variable holds statements of different queries.
+			if i > 0 && p.params != params {
+				params = -1
+				break
+			}
+			params = p.params
+		}
+		if params >= 0 {
+			for _, call := range calls {
+				if n := stmtArgs(call, pass.TypesInfo); n != params {
+					pass.Report(analysis.Diagnostic{
+						Pos:      call.Lparen,
+						Category: catArgCount,
+						Message:  fmt.Sprintf("No. of args (%d) does not match the no. of params (%d) of the statement", n, params),
+						Related:  []analysis.RelatedInformation{{Pos: prepared[0].pos, Message: "Statement prepared here"}},
+					})
+				}
+			}
+			continue
+		}
+		if len(prepared) > 1 {
+			continue
+		}
+		for _, call := range calls {
+			n := stmtArgs(call, pass.TypesInfo)
+			for _, other := range calls {
+				if m := stmtArgs(other, pass.TypesInfo); m != n {
+					pass.Report(analysis.Diagnostic{
+						Pos:      call.Lparen,
+						Category: catArgCount,
+						Message:  fmt.Sprintf("Statement %s is run with %d args here, but with %d elsewhere: at most one of them matches its params", obj.Name(), n, m),
+						Related:  []analysis.RelatedInformation{{Pos: other.Pos(), Message: fmt.Sprintf("Statement %s run with %d args", obj.Name(), m)}},
+					})
+					break
+				}
+			}
+		}
+	}
+}
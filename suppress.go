@@ -0,0 +1,136 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// span is a range of source positions, which a directive comment applies to.
+type span struct {
+	pos, end token.Pos
+	// value is the argument of the directive.
+	value string
+}
+
+// isSuppression reports whether comment is a //nolint:sqlargs or
+// //sqlargs:ignore directive, optionally followed by a reason. A //nolint
+// without a list of linters suppresses all of them, including sqlargs.
+func isSuppression(comment string) bool {
+	text := strings.TrimPrefix(comment, "//")
+	if text == comment {
+		return false
+	}
+	text = strings.TrimSpace(text)
+	if cut, ok := cutDirective(text, "sqlargs:ignore"); ok {
+		return cut == "" || cut[0] == ' ' || cut[0] == '\t'
+	}
+	rest, ok := cutDirective(text, "nolint")
+	if !ok {
+		return false
+	}
+	if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+		return true
+	}
+	if rest[0] != ':' {
+		return false
+	}
+	list := rest[1:]
+	if i := strings.IndexAny(list, " \t"); i >= 0 {
+		list = list[:i]
+	}
+	for _, name := range strings.Split(list, ",") {
+		if name == "sqlargs" {
+			return true
+		}
+	}
+	return false
+}
+
+// cutDirective returns text after directive, if text starts with it.
+func cutDirective(text, directive string) (string, bool) {
+	if !strings.HasPrefix(text, directive) {
+		return "", false
+	}
+	return text[len(directive):], true
+}
+
+// suppressedSpans returns the spans of the calls and assignments in file which
+// are silenced by a suppression comment.
+func suppressedSpans(fset *token.FileSet, file *ast.File) []span {
+	lines := make(map[int]string)
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if isSuppression(c.Text) {
+				lines[fset.Position(c.Slash).Line] = ""
+			}
+		}
+	}
+	return directiveSpans(fset, file, lines)
+}
+
+// directiveSpans returns the spans of the calls and assignments in file which
+// the directive comments on lines apply to, with their values. A comment
+// applies to the ones which start or end on its line, or, if there is none, to
+// the ones starting on the next line. The assignments cover the findings on
+// the variables of a call, like rows which are never closed.
+func directiveSpans(fset *token.FileSet, file *ast.File, lines map[int]string) []span {
+	if len(lines) == 0 {
+		return nil
+	}
+	byLine := make(map[int][]span)
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.CallExpr, *ast.AssignStmt:
+		default:
+			return true
+		}
+		s := span{pos: n.Pos(), end: n.End()}
+		start, end := fset.Position(n.Pos()).Line, fset.Position(n.End()).Line
+		byLine[start] = append(byLine[start], s)
+		if end != start {
+			byLine[end] = append(byLine[end], s)
+		}
+		return true
+	})
+	var spans []span
+	for line, value := range lines {
+		calls := byLine[line]
+		if len(calls) == 0 {
+			for _, s := range byLine[line+1] {
+				if fset.Position(s.pos).Line == line+1 {
+					calls = append(calls, s)
+				}
+			}
+		}
+		for _, s := range calls {
+			s.value = value
+			spans = append(spans, s)
+		}
+	}
+	return spans
+}
+
+// withSuppressions returns a copy of pass which does not report the
+// diagnostics of calls silenced by a suppression comment.
+func withSuppressions(pass *analysis.Pass) *analysis.Pass {
+	var spans []span
+	for _, file := range pass.Files {
+		spans = append(spans, suppressedSpans(pass.Fset, file)...)
+	}
+	if len(spans) == 0 {
+		return pass
+	}
+	p := *pass
+	p.Report = func(d analysis.Diagnostic) {
+		for _, s := range spans {
+			if s.pos <= d.Pos && d.Pos < s.end {
+				return
+			}
+		}
+		pass.Report(d)
+	}
+	return &p
+}
@@ -0,0 +1,227 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// templateAction is what template actions are replaced with while analyzing the
+// text of a template. It is a plain identifier, so that actions substituting
+// table or column names keep the query parseable.
+const templateAction = "template_action"
+
+// templateQuery returns the query built by executing a text/template, if expr
+// is of the form buf.String() and buf is only written to by executing a
+// template with a constant text inside body. Template actions are replaced by
+// identifiers. Templates with control structures are not supported.
+func templateQuery(expr ast.Expr, body *ast.BlockStmt, pass *analysis.Pass) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 || body == nil {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "String" {
+		return "", false
+	}
+	buf, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	bufVar, ok := pass.TypesInfo.Uses[buf].(*types.Var)
+	if !ok {
+		return "", false
+	}
+
+	// Find the template executed with buf as the writer.
+	var tmpl ast.Expr
+	execs := 0
+	ast.Inspect(body, func(n ast.Node) bool {
+		c, ok := n.(*ast.CallExpr)
+		if !ok || len(c.Args) != 2 {
+			return true
+		}
+		s, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok || s.Sel.Name != "Execute" || !isTemplate(pass.TypesInfo.TypeOf(s.X)) {
+			return true
+		}
+		w := c.Args[0]
+		if u, ok := w.(*ast.UnaryExpr); ok && u.Op == token.AND {
+			w = u.X
+		}
+		if id, ok := w.(*ast.Ident); ok && pass.TypesInfo.Uses[id] == bufVar {
+			tmpl = s.X
+			execs++
+		}
+		return true
+	})
+	if execs != 1 {
+		return "", false
+	}
+	text, ok := templateText(tmpl, body, pass)
+	if !ok {
+		return "", false
+	}
+	return replaceActions(text)
+}
+
+// templateText returns the constant text a template was parsed from. tmpl has to
+// be a variable initialized with template.New(...).Parse(text), optionally
+// wrapped in template.Must.
+func templateText(tmpl ast.Expr, body *ast.BlockStmt, pass *analysis.Pass) (string, bool) {
+	id, ok := tmpl.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	v, ok := pass.TypesInfo.Uses[id].(*types.Var)
+	if !ok {
+		return "", false
+	}
+	init := varInit(v, body, pass)
+	if init == nil {
+		return "", false
+	}
+	if must, ok := init.(*ast.CallExpr); ok && isTemplateFunc(must.Fun, "Must", pass.TypesInfo) && len(must.Args) == 1 {
+		init = must.Args[0]
+	}
+	parse, ok := init.(*ast.CallExpr)
+	if !ok || len(parse.Args) != 1 {
+		return "", false
+	}
+	sel, ok := parse.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Parse" {
+		return "", false
+	}
+	// Only allow a template.New(...) chain that does not change delimiters.
+	for x := sel.X; ; {
+		c, ok := x.(*ast.CallExpr)
+		if !ok {
+			return "", false
+		}
+		if isTemplateFunc(c.Fun, "New", pass.TypesInfo) {
+			break
+		}
+		s, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok || (s.Sel.Name != "Funcs" && s.Sel.Name != "Option") {
+			return "", false
+		}
+		x = s.X
+	}
+	typ, ok := pass.TypesInfo.Types[parse.Args[0]]
+	if !ok || typ.Value == nil || typ.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(typ.Value), true
+}
+
+// replaceActions replaces every action in a template text with an identifier.
+// It fails if the template contains anything other than plain substitutions.
+func replaceActions(text string) (string, bool) {
+	var b strings.Builder
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			b.WriteString(text)
+			return b.String(), true
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return "", false
+		}
+		end += start
+		action := text[start+2 : end]
+		before, after := text[:start], text[end+2:]
+		if strings.HasPrefix(action, "-") {
+			before = strings.TrimRight(before, " \t\r\n")
+			action = action[1:]
+		}
+		if strings.HasSuffix(action, "-") {
+			after = strings.TrimLeft(after, " \t\r\n")
+			action = action[:len(action)-1]
+		}
+		action = strings.TrimSpace(action)
+		b.WriteString(before)
+		switch {
+		case strings.HasPrefix(action, "/*"):
+			// Comments produce no output.
+		case isControlAction(action):
+			return "", false
+		default:
+			b.WriteString(templateAction)
+		}
+		text = after
+	}
+}
+
+func isControlAction(action string) bool {
+	keyword := action
+	if i := strings.IndexAny(action, " \t\r\n"); i >= 0 {
+		keyword = action[:i]
+	}
+	switch keyword {
+	case "if", "else", "end", "range", "with", "define", "template", "block", "break", "continue":
+		return true
+	}
+	return false
+}
+
+// varInit returns the expression v is initialized with. It looks at the
+// definitions inside body for local variables and at the package files for
+// package level variables. It returns nil if v is assigned more than once.
+func varInit(v *types.Var, body *ast.BlockStmt, pass *analysis.Pass) ast.Expr {
+	var init ast.Expr
+	assigns := 0
+	visit := func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				if pass.TypesInfo.Defs[name] == v && len(n.Names) == len(n.Values) {
+					init = n.Values[i]
+					assigns++
+				}
+			}
+		case *ast.AssignStmt:
+			for i, lhs := range n.Lhs {
+				id, ok := lhs.(*ast.Ident)
+				if !ok || (pass.TypesInfo.Defs[id] != v && pass.TypesInfo.Uses[id] != v) {
+					continue
+				}
+				assigns++
+				if len(n.Lhs) == len(n.Rhs) {
+					init = n.Rhs[i]
+				}
+			}
+		}
+		return true
+	}
+	if v.Parent() == v.Pkg().Scope() {
+		for _, f := range pass.Files {
+			ast.Inspect(f, visit)
+		}
+	} else if body != nil {
+		ast.Inspect(body, visit)
+	}
+	if assigns != 1 {
+		return nil
+	}
+	return init
+}
+
+// isTemplate reports whether typ is *text/template.Template.
+func isTemplate(typ types.Type) bool {
+	return isNamedPtr(typ, "text/template", "Template")
+}
+
+// isTemplateFunc reports whether fun refers to the text/template function name.
+func isTemplateFunc(fun ast.Expr, name string, info *types.Info) bool {
+	sel, ok := fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "text/template"
+}
@@ -1,8 +1,12 @@
 package a
 
 import (
+	"bytes"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"strings"
+	"text/template"
 	"time"
 )
 
@@ -19,9 +23,6 @@ func runDB() {
 
 	db.Exec(`INSERT INTO t VALUES ($1, $2)`, p1, p2)
 
-	const q = `INSERT INTO t(c1 c2) VALUES ($1, $2)`
-	db.Exec(q, p1, p2) // want `Invalid query: syntax error at or near "c2"`
-
 	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, "const")
 
 	db.Exec(`INSERT INTO t (c1) VALUES ($1::uuid, $2)`, p1, p2) // want `No. of columns \(1\) not equal to no. of values \(2\)`
@@ -31,7 +32,7 @@ func runDB() {
 	db.Exec(`INSERT INTO t (c1, c2, c3, c4, c5) values ('o', $1, $1, 1, '{"duration": "1440h00m00s"}')`) // // want `No. of args \(0\) is less than no. of params \(1\)`
 
 	// QueryRow
-	db.QueryRow(`INSERT INTO t (c1, c2) VALUES ($1) RETURNING c1`, p1, p2) // want `No. of columns \(2\) not equal to no. of values \(1\)`
+	db.QueryRow(`INSERT INTO t (c1, c2) VALUES ($1) RETURNING c1`, p1, p2) // want `No. of columns \(2\) not equal to no. of values \(1\)` `No. of args \(2\) is more than no. of params \(1\)`
 
 	db.QueryRow(`INSERT INTO t (c1, c2, c3, c4) VALUES ('o', $1, 'epoch'::timestamp, $2) RETURNING c1`, p1, p2)
 
@@ -55,9 +56,671 @@ func runTx() {
 	tx.Exec(`INSERT INTO t (c1, c2, c3, c4, c5) values ('o', $1, $1, 1, '{"duration": "1440h00m00s"}')`, time.Now())
 
 	// QueryRow
-	tx.QueryRow(`INSERT INTO t (c1, c2) VALUES ($1) RETURNING c1`, p1, p2) // want `No. of columns \(2\) not equal to no. of values \(1\)`
+	tx.QueryRow(`INSERT INTO t (c1, c2) VALUES ($1) RETURNING c1`, p1, p2) // want `No. of columns \(2\) not equal to no. of values \(1\)` `No. of args \(2\) is more than no. of params \(1\)`
 
 	tx.QueryRow(`INSERT INTO t (c1, c2, c3, c4) VALUES ('o', $1, 'epoch'::timestamp, $2) RETURNING c1`, p1, p2)
 
 	tx.QueryRow(`INSERT INTO t (c1, c2, c3, c4) VALUES ('o', $1, 'epoch'::timestamp, $2) RETURNING c1`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
 }
+
+func runSpread(dynamic []interface{}) {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, []interface{}{p1, p2, p3}...)
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, []interface{}{p1, p2}...) // want `No. of args \(2\) is less than no. of params \(3\)`
+
+	args := []interface{}{p1, p2}
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, args...)
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, args...) // want `No. of args \(2\) is less than no. of params \(3\)`
+
+	var reassigned = []interface{}{p1}
+	reassigned = dynamic
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, reassigned...)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, dynamic...)
+}
+
+func runAppend(cond bool, dynamic []interface{}) {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	args := []interface{}{p1}
+	args = append(args, p2, p3)
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, args...)
+
+	var short []interface{}
+	short = append(short, p1)
+	short = append(short, []interface{}{p2}...)
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, short...) // want `No. of args \(2\) is less than no. of params \(3\)`
+
+	optional := []interface{}{p1}
+	if cond {
+		optional = append(optional, p2)
+	}
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, optional...)
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, optional...) // want `No. of args \(1 to 2\) is less than no. of params \(3\)`
+
+	looped := []interface{}{p1}
+	for range dynamic {
+		looped = append(looped, p2)
+	}
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, looped...)
+
+	spread := []interface{}{p1}
+	spread = append(spread, dynamic...)
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, spread...)
+}
+
+func runUnspread(dynamic []interface{}, blob []byte) {
+	var db *sql.DB
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1 AND c2 = $2`, dynamic...)
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1`, dynamic) // want `Slice passed without ...: it will be bound as a single arg`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = $1`, blob)
+}
+
+var insertTmpl = template.Must(template.New("insert").Parse(`INSERT INTO {{.Table}} (c1, c2) VALUES ($1, $2)`))
+
+var controlTmpl = template.Must(template.New("control").Parse(`INSERT INTO t (c1{{if .C2}}, c2{{end}}) VALUES ($1{{if .C2}}, $2{{end}})`))
+
+func runTemplate(data interface{}) {
+	var db *sql.DB
+	var p1, p2 string
+
+	var buf bytes.Buffer
+	insertTmpl.Execute(&buf, data)
+	db.Exec(buf.String(), p1, p2)
+
+	var short strings.Builder
+	insertTmpl.Execute(&short, data)
+	db.Exec(short.String(), p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	local := template.Must(template.New("local").Option("missingkey=error").Parse(`INSERT INTO {{.Table -}} (c1, c2) VALUES ($1, $2)`))
+	var buf2 bytes.Buffer
+	local.Execute(&buf2, data)
+	db.Exec(buf2.String(), p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	var control bytes.Buffer
+	controlTmpl.Execute(&control, data)
+	db.Exec(control.String(), p1)
+}
+
+func runMixed() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, ?)`, p1, p2) // want `Mixed placeholder styles: \$1 and \?`
+
+	db.Exec(`UPDATE t SET c1 = ? WHERE c2 = $1 AND c3 = $2`, p1, p2) // want `Mixed placeholder styles: \? and \$1`
+}
+
+func runNumbering() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`UPDATE t SET c1 = $1 WHERE c2 = $3`, p1, p2, p3) // want `Gap in placeholder numbering: \$3 is used but not \$2`
+
+	db.Exec(`UPDATE t SET c1 = $2, c2 = $5 WHERE c3 = $2`, p1, p2, p3, p1, p2) // want `Gap in placeholder numbering: \$5 is used but not \$1, \$3, \$4`
+
+	db.Exec(`UPDATE t SET c1 = $9 WHERE c2 = $1`, p1, p2, p3, p1, p2, p3, p1, p2, p3) // want `Gap in placeholder numbering: \$9 is used but not \$2, \$3, \$4, \$5, \$6, \.\.\.`
+}
+
+func runIndices() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = $0 WHERE c2 = $1`, p1) // want `Invalid placeholder \$0: indices start at 1`
+
+	db.Exec(`UPDATE t SET c1 = $-1 WHERE c2 = $1`, p1) // want `Invalid placeholder \$-1: indices start at 1`
+}
+
+func runRepeated() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 OR c3 = $1`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 OR c3 = $1`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $2 OR c3 = $1 OR c4 = $2`, p1, p2)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $2 OR c3 = $1 OR c4 = $2`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`UPDATE t SET c1 = $1, c2 = $2 WHERE c3 = $3 AND c4 = $1`, p1, p2, p3)
+
+	db.Exec(`DELETE FROM t`, p1) // want `No. of args \(1\) is more than no. of params \(0\)`
+}
+
+func runLiterals() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET note = 'costs $1'`)
+
+	db.Exec(`UPDATE t SET note = 'it''s $2' WHERE c1 = $1`, p1)
+
+	db.Exec(`UPDATE t SET note = E'it\'s $2' WHERE c1 = $1`, p1)
+
+	db.Exec(`UPDATE t SET note = 'C:\' WHERE c1 = $1`, p1)
+
+	db.Exec(`UPDATE t SET "$2" = 'costs $3' WHERE c1 = $1`, p1)
+}
+
+func runComments() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`UPDATE t SET c1 = $1 -- uses $3 for tenant
+	WHERE c2 = $2`, p1, p2)
+
+	db.Exec(`UPDATE t SET c1 = $1 /* , c2 = $2 */ WHERE c3 = $2`, p1, p2)
+
+	db.Exec(`UPDATE t SET c1 = $1 /* nested /* $3 */ $4 */ WHERE c3 = $2`, p1, p2)
+
+	db.Exec(`UPDATE t SET c1 = '--' WHERE c3 = $1`, p1)
+
+	db.Exec(`UPDATE t SET c1 = $1 -- WHERE c3 = $2`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
+
+func runJSONB() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE data ? 'key' AND c2 = $1`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE data ? c3 AND c2 = $1`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE data ?| array['a', 'b'] AND data ?& array['c'] AND c2 = ?`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE data ? 'key' AND c2 = ?`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE data ? /* key */ 'key' AND c2 = ?`, p1, p1) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
+
+func runDollarQuotes() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`DO $$ BEGIN PERFORM f($1); END $$`)
+
+	db.Exec(`CREATE FUNCTION f(int) RETURNS int AS $body$ SELECT $1 + $2 $body$ LANGUAGE SQL`)
+
+	db.Query(`SELECT $1, $$ $2 $$, $a$ $$ $3 $$ $a$`, p1)
+
+	db.Query(`SELECT $1, $$ $2 $$, $2`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
+
+func runMultiRow() {
+	var db *sql.DB
+	var p1, p2, p3, p4 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2), ($3, $4)`, p1, p2, p3, p4)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2), ($3, $4)`, p1, p2, p3) // want `No. of args \(3\) is less than no. of params \(4\)`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2), ($3), ($4, 'x')`, p1, p2, p3, p4) // want `No. of columns \(2\) not equal to no. of values \(1\) in row 2`
+}
+
+func runCasts() {
+	var db *sql.DB
+	var p1, p2, p3, p4 string
+
+	db.Exec(`UPDATE t SET c1 = $1::uuid, c2 = $2::timestamptz[] WHERE c3 = coalesce($3::text, '') AND c4 = CAST($4 AS int)`, p1, p2, p3, p4)
+
+	db.Exec(`UPDATE t SET c1 = $1::uuid, c2 = $2::timestamptz[] WHERE c3 = coalesce($3::text, '')`, p1, p2) // want `No. of args \(2\) is less than no. of params \(3\)`
+
+	db.Query(`SELECT c1 FROM t WHERE data->>'k' = $1::jsonb->>'k' AND c2 = lower(trim($2::varchar(10)))`, p1, p2)
+}
+
+func runFmtVerbs() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = '%s'`, p1) // want `Query contains fmt verb %s: use a placeholder or fmt.Sprintf`
+
+	db.Query(`SELECT c1 FROM "%s" WHERE c2 = $1`, p1) // want `Query contains fmt verb %s`
+
+	db.Query(`SELECT c1 FROM t LIMIT %d`, p1) // want `Query contains fmt verb %d`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 LIKE '%s%' AND c3 = c4 % d AND c5 = $1 -- %v`, p1)
+
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s WHERE c2 = $1`, "t"), p1)
+}
+
+func runQuoted() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query(`SELECT c1 FROM t WHERE name = '$1'`, p1) // want `Placeholder \$1 appears inside quotes: arg 1 will be unused`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 AND name = '$2'`, p1, p2) // want `Placeholder \$2 appears inside quotes: arg 2 will be unused`
+
+	db.Query(`SELECT c1 FROM t WHERE name = '?' AND c2 = ?`, p1, p2) // want `Placeholder \? appears inside quotes: arg 1 will be unused`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 AND note <> '$1'`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE name = '?'`)
+}
+
+func runCall() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`CALL transfer($1, $2)`, p1, p2)
+
+	db.Exec(`CALL transfer($1, $2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.QueryRow(`SELECT balance($1)`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
+
+func runUpsert() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (id, x, y) VALUES ($1, $2, $2) ON CONFLICT (id) DO UPDATE SET x = $3, y = EXCLUDED.y`, p1, p2, p3)
+
+	db.Exec(`INSERT INTO t (id, x, y) VALUES ($1, $2, $2) ON CONFLICT (id) DO UPDATE SET x = $3, y = EXCLUDED.y`, p1, p2) // want `No. of args \(2\) is less than no. of params \(3\)`
+
+	db.Exec(`INSERT INTO t (id, x) VALUES ($1, $2) ON CONFLICT ON CONSTRAINT t_pkey DO UPDATE SET x = $3 WHERE t.x <> $4`, p1, p2, p3) // want `No. of args \(3\) is less than no. of params \(4\)`
+
+	db.Exec(`INSERT INTO t (id, x, y) VALUES ($1, $2) ON CONFLICT DO NOTHING`, p1, p2) // want `No. of columns \(3\) not equal to no. of values \(2\)`
+}
+
+func runCTE() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Query(`WITH recent AS (SELECT id FROM t WHERE created > $1) SELECT c1 FROM u WHERE id IN (SELECT id FROM recent) AND c2 = $2`, p1, p2)
+
+	db.Query(`WITH recent AS (SELECT id FROM t WHERE created > $1) SELECT c1 FROM u WHERE id IN (SELECT id FROM recent) AND c2 = $2`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`WITH ins AS (INSERT INTO t (c1, c2) VALUES ($1, $2) RETURNING id) INSERT INTO log (t_id, note) SELECT id, $3 FROM ins`, p1, p2, p3)
+
+	db.Query(`WITH ins AS (INSERT INTO t (c1, c2, c3) VALUES ($1, $2) RETURNING id) SELECT id FROM ins`, p1, p2) // want `No. of columns \(3\) not equal to no. of values \(2\)`
+
+	db.Exec(`WITH src AS (SELECT $1::int AS id) INSERT INTO t (id, c1) VALUES ($2)`, p1, p2) // want `No. of columns \(2\) not equal to no. of values \(1\)`
+}
+
+func runWindows() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query(`SELECT c1, row_number() OVER (PARTITION BY c2 ORDER BY c3 DESC) FROM t WHERE c4 = $1`, p1)
+
+	db.Query(`SELECT count(*) FILTER (WHERE c2 > $1), sum(c3) OVER w FROM t WHERE c4 = $2 WINDOW w AS (ORDER BY c5)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Query(`SELECT DISTINCT ON (u.id) u.id, o.total FROM u, LATERAL (SELECT total FROM o WHERE o.uid = u.id AND o.total > $1 LIMIT 1) o`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
+
+func runMerge() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`MERGE INTO t USING s ON t.id = s.id AND s.kind = $1 WHEN MATCHED THEN UPDATE SET c1 = $2 WHEN NOT MATCHED THEN INSERT (id, c1) VALUES (s.id, $3)`, p1, p2, p3)
+
+	db.Exec(`MERGE INTO t USING s ON t.id = s.id AND s.kind = $1 WHEN MATCHED THEN UPDATE SET c1 = $2 WHEN NOT MATCHED THEN INSERT (id, c1) VALUES (s.id, $3)`, p1, p2) // want `No. of args \(2\) is less than no. of params \(3\)`
+}
+
+func runMultiStatement() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`UPDATE t SET c1 = $1; DELETE FROM u WHERE c2 = $2`, p1, p2) // want `Multiple statements with args: most drivers cannot bind args to them`
+
+	db.Exec(`UPDATE t SET c1 = 1; DELETE FROM u WHERE c2 = 2`)
+
+	db.Exec(`UPDATE t SET c1 = $1; -- done`, p1)
+
+	db.Exec(`UPDATE t SET c1 = ';' WHERE c2 = $1`, p1)
+}
+
+func runLimit() {
+	var db *sql.DB
+	var p1, limit string
+	var offset float64
+	var n int
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 LIMIT $2 OFFSET $3`, p1, n, n)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 LIMIT $2 OFFSET $3`, p1, limit, offset) // want `Arg 2 is used in LIMIT but has type string: it should be an integer` `Arg 3 is used in OFFSET but has type float64: it should be an integer`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = ? LIMIT ?`, p1, "10") // want `Arg 2 is used in LIMIT but has type string: it should be an integer`
+
+	db.Query(`SELECT c1 FROM t LIMIT $1`, interface{}(limit))
+}
+
+func runReturning() {
+	var db *sql.DB
+	var p1 string
+	var id int
+
+	db.QueryRow(`INSERT INTO t (c1) VALUES ($1) RETURNING id`, p1).Scan(&id)
+
+	db.Exec(`INSERT INTO t (c1) VALUES ($1) RETURNING id`, p1) // want `Query has a RETURNING clause but is run with Exec: the returned rows are discarded, use QueryRow or Query`
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1 RETURNING *`, p1) // want `Query has a RETURNING clause but is run with Exec`
+
+	db.Exec(`WITH del AS (DELETE FROM t WHERE c1 = $1 RETURNING id) INSERT INTO log (t_id) SELECT id FROM del`, p1)
+
+	db.Exec(`UPDATE t SET note = 'RETURNING' WHERE c1 = $1`, p1)
+}
+
+func runInsertArity() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2)`, p1, p2) // want `No. of columns \(3\) not equal to no. of values \(2\): too few values`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2, $3)`, p1, p2, p3) // want `No. of columns \(2\) not equal to no. of values \(3\): too few columns`
+
+	db.Exec(`INSERT INTO s."t" (c1, c2) VALUES (coalesce($1, 'a, b'), now())`, p1)
+
+	db.Exec(`INSERT INTO t (c1, c2) SELECT c1, c2 FROM u WHERE c3 = $1`, p1)
+}
+
+func runUpdateSet() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`UPDATE t SET c1 = $1, c2 = $2 WHERE id = $3`, p1, p2, p3)
+
+	db.Exec(`UPDATE t SET c1 = $1, c2 = coalesce($4, c2), c3 = $2 WHERE id = $3`, p1, p2, p3) // want `No. of args \(3\) is less than no. of params \(4\): SET c2 = coalesce\(\$4, c2\) has no arg`
+
+	db.Exec(`UPDATE t SET c1 = $1 WHERE id = $2 AND c2 = $3`, p1, p2) // want `No. of args \(2\) is less than no. of params \(3\): the query references \$3 but only 2 args are passed$`
+
+	db.Exec(`INSERT INTO t (id, c1) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET c1 = $3`, p1, p2) // want `: SET c1 = \$3 has no arg`
+}
+
+func runBalance() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = 'abc AND c3 = $1`, p1) // want `Unterminated string literal: 'abc AND c3 = \$1`
+
+	db.Query(`SELECT "c1 FROM t WHERE c2 = $1`, p1) // want `Unterminated quoted identifier`
+
+	db.Query(`SELECT c1 FROM t /* WHERE c2 = $1`, p1) // want `Unterminated comment`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 IN (SELECT c2 FROM u WHERE c3 = $1`, p1) // want `Unbalanced parentheses: 1 \( not closed`
+
+	db.Query(`SELECT c1 FROM t WHERE lower(c2)) = $1`, p1) // want `Unbalanced parentheses: \) at offset 32 has no matching \(`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = '(' AND c3 = $1 -- )`, p1)
+}
+
+func runCommas() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query(`SELECT a, b, FROM t WHERE c = $1`, p1) // want `Trailing comma before FROM`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1,, $2)`, p1, p2) // want `Doubled comma at offset 33`
+
+	db.Exec(`INSERT INTO t (c1, c2,) VALUES ($1, $2)`, p1, p2) // want `Trailing comma at offset 21`
+
+	db.Query("SELECT a, b, -- c,\nFROM t") // want `Trailing comma before FROM`
+
+	db.Query(`SELECT a, ',', "from" FROM t WHERE c = $1`, p1)
+
+	db.Query(`SELECT a, b,`) // want `Trailing comma at offset 11`
+}
+
+func runReserved() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query(`SELECT id FROM user WHERE name = $1`, p1) // want `Reserved keyword user used as an identifier: quote it as "user"`
+
+	db.Exec(`INSERT INTO orders (id, order) VALUES ($1, $2)`, p1, p2) // want `Reserved keyword order used as an identifier`
+
+	db.Exec(`UPDATE t SET c1 = $1, desc = $2`, p1, p2) // want `Reserved keyword desc used as an identifier`
+
+	db.Query(`SELECT id FROM "user" WHERE owner = user AND c1 = $1 ORDER BY id DESC, name`, p1)
+}
+
+func runOrdinals() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1, count(*) FROM t WHERE c2 = $1 GROUP BY 1 ORDER BY 2 DESC, 1`, p1)
+
+	db.Query(`SELECT c1, c2 FROM t WHERE c3 = $1 ORDER BY 3`, p1) // want `ORDER BY 3 is out of range: the select list has 2 columns`
+
+	db.Query(`SELECT DISTINCT ON (c1, c2) c1, coalesce(c3, 0) FROM t GROUP BY 1, 3 ORDER BY c1 LIMIT 5`) // want `GROUP BY 3 is out of range: the select list has 2 columns`
+
+	db.Query(`SELECT * FROM t ORDER BY 5`)
+
+	db.Query(`SELECT c1, row_number() OVER (ORDER BY 7) FROM t ORDER BY c2 + 3`)
+}
+
+func runNullComparisons() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = NULL AND c3 = $1`, p1) // want `Comparison = NULL is never true: use IS NULL`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 != null`) // want `Comparison != NULL is never true: use IS NOT NULL`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 <> NULL`) // want `Comparison <> NULL is never true: use IS NOT NULL`
+
+	db.Exec(`UPDATE t SET c1 = NULL, c2 = NULL WHERE c3 IS NULL AND c4 = $1`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 >= NULLIF($1, '') AND c3 = 'NULL'`, p1)
+}
+
+func runDuplicateColumns() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2, c1) VALUES ($1, $2, $3)`, p1, p2, p3) // want `Column c1 is listed more than once in INSERT`
+
+	db.Exec(`INSERT INTO t (c1, c2, C1) VALUES ($1, $2, $3)`, p1, p2, p3) // want `Column C1 is listed more than once in INSERT`
+
+	db.Exec(`INSERT INTO t (c1, c2) SELECT c1, c1 FROM u`)
+}
+
+func runScan() {
+	var db *sql.DB
+	var p1 string
+	var a, b int
+
+	db.QueryRow(`SELECT c1, c2 FROM t WHERE c3 = $1`, p1).Scan(&a, &b)
+
+	db.QueryRow(`SELECT c1, c2 FROM t WHERE c3 = $1`, p1).Scan(&a) // want `No. of Scan destinations \(1\) not equal to no. of columns \(2\)`
+
+	db.QueryRow(`WITH x AS (SELECT c1, c2 FROM t) SELECT count(*) FROM x WHERE c1 = $1`, p1).Scan(&a, &b) // want `No. of Scan destinations \(2\) not equal to no. of columns \(1\)`
+
+	db.QueryRow(`SELECT * FROM t WHERE c3 = $1`, p1).Scan(&a)
+
+	dest := []interface{}{&a}
+	db.QueryRow(`SELECT c1, c2 FROM t WHERE c3 = $1`, p1).Scan(dest...)
+}
+
+func runRowsScan() {
+	var db *sql.DB
+	var p1 string
+	var a, b int
+
+	rows, _ := db.Query(`SELECT c1, c2 FROM t WHERE c3 = $1`, p1)
+	defer rows.Close()
+	for rows.Next() {
+		rows.Scan(&a, &b)
+		rows.Scan(&a) // want `No. of Scan destinations \(1\) not equal to no. of columns \(2\)`
+	}
+	if rows.Err() != nil {
+		return
+	}
+
+	rows, _ = db.Query(`SELECT c1 FROM t WHERE c3 = $1`, p1)
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(&a, &b); err != nil { // want `No. of Scan destinations \(2\) not equal to no. of columns \(1\)`
+			return
+		}
+	}
+	if rows.Err() != nil {
+		return
+	}
+}
+
+func runReturningScan() {
+	var db *sql.DB
+	var p1 string
+	var id, created int
+
+	db.QueryRow(`INSERT INTO t (c1) VALUES ($1) RETURNING id, created_at`, p1).Scan(&id, &created)
+
+	db.QueryRow(`INSERT INTO t (c1) VALUES ($1) RETURNING id, created_at`, p1).Scan(&id) // want `No. of Scan destinations \(1\) not equal to no. of columns \(2\)`
+
+	db.QueryRow(`UPDATE t SET c1 = $1 RETURNING (xmax = 0) AS inserted`, p1).Scan(&id, &created) // want `No. of Scan destinations \(2\) not equal to no. of columns \(1\)`
+
+	db.QueryRow(`DELETE FROM t WHERE c1 = $1 RETURNING *`, p1).Scan(&id)
+}
+
+func runExecSelect() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`SELECT c1 FROM t WHERE c2 = $1`, p1) // want `SELECT is run with Exec: the selected rows are discarded, use QueryRow or Query`
+
+	db.Exec(`WITH x AS (SELECT c1 FROM t) SELECT c1 FROM x`) // want `SELECT is run with Exec`
+
+	db.Exec(`SELECT c1 INTO backup FROM t WHERE c2 = $1`, p1)
+
+	db.Exec(`INSERT INTO t (c1) SELECT c1 FROM u WHERE c2 = $1`, p1)
+}
+
+func runQueryWrite() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`DELETE FROM t WHERE c1 = $1`, p1) // want `DELETE without RETURNING is run with Query: it returns no rows, use Exec`
+
+	db.QueryRow(`UPDATE t SET c1 = $1`, p1) // want `UPDATE without RETURNING is run with QueryRow: it returns no rows, use Exec`
+
+	db.Query(`DELETE FROM t WHERE c1 = $1 RETURNING id`, p1)
+
+	db.Query(`WITH del AS (DELETE FROM t WHERE c1 = $1 RETURNING id) SELECT id FROM del`, p1)
+}
+
+func runCastArgs() {
+	var db *sql.DB
+	var id string
+	var n int
+	var at time.Time
+
+	db.Exec(`UPDATE t SET c1 = $1::uuid, c2 = $2::timestamptz WHERE c3 = CAST($3 AS int)`, id, at, n)
+
+	db.Exec(`UPDATE t SET c1 = $1::uuid, c2 = $2::timestamptz WHERE c3 = CAST($3 AS int)`, n, n, at) // want `Arg 1 has type int but is cast to uuid` `Arg 2 has type int but is cast to timestamptz` `Arg 3 has type time.Time but is cast to int`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1::text AND c3 = $2::jsonb`, n, at)
+}
+
+type point struct{ x, y int }
+
+type money struct{ cents int64 }
+
+func (m money) Value() (driver.Value, error) { return m.cents, nil }
+
+func runStructArgs() {
+	var db *sql.DB
+	var p point
+	var m money
+	var at time.Time
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, m, at, sql.NullString{})
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p, &p) // want `Arg of type point does not implement driver.Valuer: it cannot be bound` `Arg of type \*point does not implement driver.Valuer`
+
+	db.Exec(`INSERT INTO t (c1) VALUES ($1)`, sql.Named("c1", m))
+}
+
+func runErrorArgs() {
+	var db *sql.DB
+	var p1 string
+	var err error
+
+	db.Exec(`UPDATE t SET c1 = $1 WHERE c2 = $2`, p1, err) // want `Arg of type error passed to the query: it is most likely the wrong variable`
+
+	db.Exec(`UPDATE t SET c1 = $1 WHERE c2 = $2`, p1, err.Error())
+}
+
+func runSprintf(name string, id int) {
+	var db *sql.DB
+
+	db.Query(fmt.Sprintf(`SELECT c1 FROM t WHERE c2 = '%s'`, name)) // want `Non-constant name is interpolated into the query with fmt.Sprintf: pass it as an arg to avoid SQL injection`
+
+	query := fmt.Sprintf(`SELECT c1 FROM t WHERE c2 = %d AND c3 = %d`, id, 1) // want `Non-constant id is interpolated`
+	db.Query(query)
+
+	const table = "t"
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s`, table))
+}
+
+func runConcat(name string) {
+	var db *sql.DB
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = '` + name + `'`) // want `Non-constant name is concatenated into the query: pass it as an arg to avoid SQL injection`
+
+	const where = ` WHERE c2 = $1`
+	db.Query(`SELECT c1 FROM t`+where, name)
+
+	query := `SELECT c1 FROM t WHERE c2 = '` + strings.ToLower(name) + `'` // want `Non-constant strings.ToLower\(name\) is concatenated`
+	db.Query(query)
+}
+
+func runPositions() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`
+		SELECT c1
+		FROM t
+		WHERE c2 = $1
+		AND c3 = $2`, // want `No. of args \(1\) is less than no. of params \(2\)`
+		p1)
+
+	db.Query("SELECT c1\n"+
+		"FROM t WHERE c2 = $1", p1)
+
+	db.Query("SELECT c1\n\tFROM t\n\tWHERE c2 = 'é' AND c3 = $1 AND c4 = $2", p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// Queries which are not a single literal are reported at the call.
+	db.Exec(`INSERT INTO t (c1, c2,)`+ // want `Trailing comma at offset 21`
+		`
+		VALUES ($1, $2)`, p1, p1)
+}
+
+func runDistinctProblems() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query(`SELECT id, order FROM t WHERE c1 = $1 AND c2 = $2`, p1) // want `Trailing comma before order` `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, '$2')`, p1, p2) // want `Placeholder \$2 appears inside quotes` `No. of columns \(3\) not equal to no. of values \(2\)`
+}
+
+func runCountDetails() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 AND c3 = $2 AND c4 = $4 AND c5 = $3`, p1, p2) // want `No. of args \(2\) is less than no. of params \(4\): the query references \$4 but only 2 args are passed`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = ? AND c3 = ?`) // want `No. of args \(0\) is less than no. of params \(2\): the query has 2 placeholders but no args are passed`
+
+	db.Exec(`UPDATE t SET c1 = $1`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\): arg 2 has no placeholder`
+
+	db.Exec(`DELETE FROM t`, p1, p2, p3) // want `No. of args \(3\) is more than no. of params \(0\): args 1 to 3 have no placeholder`
+
+	args := []interface{}{p1}
+	if p1 != "" {
+		args = append(args, p2)
+	}
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1 AND c3 = $2 AND c4 = $3`, args...) // want `No. of args \(1 to 2\) is less than no. of params \(3\): the query references \$3 but at most 2 args are passed`
+}
@@ -0,0 +1,23 @@
+package argsfix
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2, p3) // want `No. of args \(3\) is more than no. of params \(2\)`
+
+	db.Exec(`DELETE FROM t`, p1, p2) // want `No. of args \(2\) is more than no. of params \(0\)`
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, p1) // want `No. of args \(1\) is less than no. of params \(3\)`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, // want `No. of args \(1\) is less than no. of params \(2\)`
+		p1,
+	)
+
+	args := []interface{}{p1}
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, args...) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
@@ -0,0 +1,30 @@
+package arrays
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+func runDB(ids []int64, names []string) {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE id = ANY($1)`, pq.Array(ids))
+
+	db.Query(`SELECT c1 FROM t WHERE id = ANY($1::bigint[]) AND name <> ALL ($2)`, pq.Array(ids), pq.Array(names))
+
+	db.Query(`SELECT c1 FROM t WHERE id = ANY($1) AND c2 = $2`, pq.Array(ids)) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`UPDATE t SET tags = $1 WHERE id = ANY($2) AND c2 = $3`, pq.Array(names), pq.Array(ids), p1)
+
+	db.Exec(`UPDATE t SET tags = ARRAY[$1, $2] WHERE id = $3`, p1, p1, p1)
+}
+
+func runUnwrapped(ids []int64, attrs map[string]string, data []byte) {
+	var db *sql.DB
+
+	db.Query(`SELECT c1 FROM t WHERE id = ANY($1)`, ids) // want `Arg of type \[\]int64 cannot be bound as a single value: wrap it with pq.Array`
+
+	db.Exec(`UPDATE t SET attrs = $1, data = $2`, attrs, data) // want `Arg of type map\[string\]string cannot be bound as a single value: encode it, e.g. with json.Marshal`
+}
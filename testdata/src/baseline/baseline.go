@@ -0,0 +1,23 @@
+package baseline
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p2 string
+
+	// Listed in the baseline.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1)
+
+	db.Exec(`UPDATE t SET c1 = $1`, p1, p2)
+
+	// Listed once, for the first of the two calls.
+	db.Exec(`DELETE FROM t WHERE c1 = $1`)
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1`) // want `No. of args \(0\) is less than no. of params \(1\)`
+
+	// New findings are reported.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`) // want `No. of args \(0\) is less than no. of params \(2\)`
+}
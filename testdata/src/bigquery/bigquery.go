@@ -0,0 +1,36 @@
+package bigquery
+
+import (
+	"context"
+
+	"cloud.google.com/go/bigquery"
+)
+
+func run(ctx context.Context, client *bigquery.Client, params []bigquery.QueryParameter) {
+	q := client.Query(`SELECT c1 FROM t WHERE c2 = @c2 AND c3 = @c3`)
+	q.Parameters = []bigquery.QueryParameter{
+		{Name: "c2", Value: 1},
+		{Name: "C3", Value: 2},
+	}
+	q.Read(ctx)
+
+	missing := client.Query(`SELECT c1 FROM t WHERE c2 = @c2 AND c3 = @c3`) // want `No value for parameter @c3`
+	missing.Parameters = []bigquery.QueryParameter{
+		{Name: "c2", Value: 1},
+	}
+	missing.Read(ctx)
+
+	none := client.Query(`SELECT c1 FROM t WHERE c2 = @c2`) // want `No value for parameter @c2`
+	none.Read(ctx)
+
+	positional := client.Query(`SELECT c1 FROM t WHERE c2 = ? AND c3 = ?`) // want `No. of args \(1\) is less than no. of params \(2\)`
+	positional.Parameters = []bigquery.QueryParameter{{Value: 1}}
+	positional.Read(ctx)
+
+	dynamic := client.Query(`SELECT c1 FROM t WHERE c2 = @c2`)
+	dynamic.Parameters = params
+	dynamic.Read(ctx)
+
+	quoted := client.Query("SELECT `@c1` FROM t WHERE c2 = '@c2'")
+	quoted.Read(ctx)
+}
@@ -0,0 +1,39 @@
+package clickhouse
+
+import (
+	"database/sql"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+	var ids []uint64
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// Slices are bound as arrays.
+	db.Query(`SELECT c1 FROM t WHERE has(?, id)`, ids)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = {c2:String} AND id IN {ids:Array(UInt64)}`, clickhouse.Named("c2", p1), clickhouse.Named("ids", ids))
+
+	// A parameter can be used more than once.
+	db.Query(`SELECT c1 FROM t WHERE c2 = {v:String} OR c3 = {v:String}`, sql.Named("v", p1))
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = {c2:String} AND c3 = {c3:String}`, clickhouse.Named("c2", p1), clickhouse.Named("c4", p2)) // want `No arg for parameter \{c3:String\}` `Named args not used by the query: c4`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = {c2:String}`, p1) // want `Positional args cannot be bound to \{name:Type\} parameters`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = ? AND c3 = {c3:String}`, p1, clickhouse.Named("c3", p2)) // want `Mixed placeholder styles: \? and \{c3:String\}`
+}
+
+func runLiterals() {
+	var db *sql.DB
+	var p1 string
+
+	// Braces in strings, and maps, are not parameters.
+	db.Query(`SELECT c1 FROM t WHERE c2 = '{c2:String}' AND c3 = ? AND m = map('a', 1)`, p1)
+}
@@ -0,0 +1,42 @@
+package confidence
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"text/template"
+
+	_ "github.com/lib/pq"
+)
+
+var insertTmpl = template.Must(template.New("insert").Parse(`INSERT INTO {{.Table}} (c1, c2) VALUES ($1, $2)`))
+
+func run(name string, data interface{}) {
+	var db *sql.DB
+	var p1 string
+
+	// Constant queries with listed args are high confidence.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// The length of spread slices is inferred.
+	args := []interface{}{p1}
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, args...)
+
+	// Interpolated values might be validated.
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s`, name))
+
+	// Templates are only analyzed with heuristics.
+	var buf bytes.Buffer
+	insertTmpl.Execute(&buf, data)
+	db.Exec(buf.String(), p1)
+}
+
+func duplicate(db *sql.DB, id int64) {
+	// Duplicate args are sometimes intended.
+	db.Exec(`DELETE FROM users WHERE id = $1 AND org_id = $2`, id, id)
+}
+
+func swapped(db *sql.DB, id int64, email string) {
+	// Swapped args are only guessed from their names.
+	db.Exec(`DELETE FROM users WHERE id = $1 AND email = $2`, email, id)
+}
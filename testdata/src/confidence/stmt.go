@@ -0,0 +1,19 @@
+package confidence
+
+import "database/sql"
+
+func insertOne(db *sql.DB, p1 string) error {
+	stmt, err := db.Prepare(`INSERT INTO t (c1, c2) VALUES ($1, $2)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(p1) // want `No. of args \(1\) does not match the no. of params \(2\)`
+	return err
+}
+
+// The statements are checked after the calls, with the confidence of none of
+// them.
+func insertAll(db *sql.DB, args []interface{}) {
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, args...)
+}
@@ -0,0 +1,22 @@
+package constqueries
+
+import (
+	"database/sql"
+	"strings"
+)
+
+const base = `SELECT c1 FROM t`
+
+func run(table string) {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1`, p1)
+
+	db.Query(base+` WHERE c2 = $1`, p1)
+
+	query := `SELECT c1 FROM t WHERE c2 = $1`
+	db.Query(query, p1) // want `Query is not a constant: only constant queries are allowed`
+
+	db.Query(strings.Replace(base, "t", table, 1)) // want `Query is not a constant`
+}
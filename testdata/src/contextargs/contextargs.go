@@ -0,0 +1,24 @@
+package contextargs
+
+import (
+	"context"
+	"database/sql"
+)
+
+func run(ctx context.Context, db *sql.DB, tx *sql.Tx, id int64) error {
+	if _, err := db.ExecContext(ctx, `DELETE FROM t WHERE c1 = $1 AND c2 = $2`, id); err != nil { // want `No. of args \(1\) is less than no. of params \(2\)`
+		return err
+	}
+	var c1 string
+	if err := tx.QueryRowContext(ctx, `SELECT c1, c2 FROM t WHERE c1 = $1`, id).Scan(&c1); err != nil { // want `No. of Scan destinations \(1\) not equal to no. of columns \(2\)`
+		return err
+	}
+	rows, err := db.QueryContext(ctx, `SELECT c1 FROM t`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+	}
+	return rows.Err()
+}
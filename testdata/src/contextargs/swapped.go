@@ -0,0 +1,10 @@
+package contextargs
+
+import (
+	"context"
+	"database/sql"
+)
+
+func swapped(ctx context.Context, db *sql.DB, id int64) {
+	db.ExecContext(`DELETE FROM t WHERE c1 = $1`, ctx, id) // want `First arg of ExecContext is a string, not a context.Context: pass the context first, then the query`
+}
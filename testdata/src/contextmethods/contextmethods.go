@@ -0,0 +1,34 @@
+package contextmethods
+
+import (
+	"context"
+	"database/sql"
+)
+
+func exec(ctx context.Context, db *sql.DB) error {
+	_, err := db.Exec(`DELETE FROM t WHERE c1 = $1`, 1) // want `Exec does not use the context ctx of the function: use ExecContext, so that its cancellation is propagated`
+	return err
+}
+
+func queryRow(c context.Context, tx *sql.Tx) (string, error) {
+	var s string
+	err := tx.QueryRow(`SELECT c1 FROM t`).Scan(&s) // want `QueryRow does not use the context c of the function: use QueryRowContext`
+	return s, err
+}
+
+func withoutContext(db *sql.DB) error {
+	_, err := db.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	return err
+}
+
+func unnamed(_ context.Context, db *sql.DB) error {
+	_, err := db.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	return err
+}
+
+func closure(ctx context.Context, db *sql.DB) {
+	go func() {
+		// The goroutine may outlive the context.
+		db.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	}()
+}
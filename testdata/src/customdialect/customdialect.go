@@ -0,0 +1,16 @@
+package customdialect
+
+import (
+	"database/sql"
+)
+
+func runTrino() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query("SELECT c1 FROM t WHERE c2 = ? AND c3 = ?", p1, p2)
+
+	db.Query("SELECT c1 FROM t WHERE c2 = ? AND c3 = ?", p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Query("SELECT c1 FROM t WHERE c2 = $1", p1) // want `Placeholder \$1 is not valid for trino queries`
+}
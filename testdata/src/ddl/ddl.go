@@ -0,0 +1,36 @@
+package ddl
+
+import "database/sql"
+
+func migrate(db *sql.DB) {
+	db.Exec(`CREATE TABLE IF NOT EXISTS users (id bigserial PRIMARY KEY, name text NOT NULL, CONSTRAINT name_key UNIQUE (name))`)
+	db.Exec(`CREATE TABLE users (id bigserial PRIMARY KEY, name NOT NULL)`) // want `Column name has no type`
+	db.Exec(`CREATE TABLE users (id, name text)`)                           // want `Column id has no type`
+	db.Exec(`CREATE TABLE users`)                                           // want `CREATE TABLE users without column definitions`
+	db.Exec(`CREATE TABLE users id bigint`)                                 // want `Unexpected id after CREATE TABLE users: expected the column definitions in parentheses`
+	db.Exec(`CREATE TABLE users_2 PARTITION OF users FOR VALUES IN (2)`)
+	db.Exec(`CREATE TABLE (id bigint)`) // want `CREATE TABLE without a table name`
+
+	db.Exec(`CREATE UNIQUE INDEX CONCURRENTLY IF NOT EXISTS users_name ON users USING btree (name)`)
+	db.Exec(`CREATE INDEX ON users (name)`)
+	db.Exec(`CREATE INDEX users_name users (name)`) // want `CREATE INDEX without ON: expected the table the index is on`
+	db.Exec(`CREATE INDEX users_name ON users`)     // want `CREATE INDEX on users without a column list`
+
+	db.Exec(`ALTER TABLE users ADD COLUMN email text, ALTER COLUMN name SET NOT NULL, DROP COLUMN age`)
+	db.Exec(`ALTER TABLE ONLY users ADD CONSTRAINT email_key UNIQUE (email)`)
+	db.Exec(`ALTER TABLE users ADD COLUMN email`) // want `Column email has no type`
+	db.Exec(`ALTER TABLE users MOVE email`)       // want `Unknown ALTER TABLE action MOVE: expected one like ADD, DROP, ALTER, RENAME or SET`
+	db.Exec(`ALTER TABLE users`)                  // want `ALTER TABLE users without an action`
+
+	db.Exec(`CREATE TABLE a (id bigint); CREATE INDEX a_id ON a`) // want `CREATE INDEX on a without a column list`
+}
+
+func sqlite(db *sql.DB) {
+	// Columns have no type in SQLite.
+	db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name)`) //sqlargs:dialect sqlite
+}
+
+func query(db *sql.DB) {
+	// Only the statements run with Exec are checked.
+	db.Query(`CREATE TABLE users (id)`)
+}
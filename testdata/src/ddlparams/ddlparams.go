@@ -0,0 +1,13 @@
+package ddlparams
+
+import "database/sql"
+
+func migrate(db *sql.DB, days int) {
+	db.Exec(`CREATE TABLE sessions (id bigint, ttl int DEFAULT $1)`, days) // want `Placeholder \$1 in DDL: CREATE statements cannot have bind parameters, write the value in the statement`
+	db.Exec(`ALTER TABLE sessions ALTER COLUMN ttl SET DEFAULT $1`)        // want `Placeholder \$1 in DDL: ALTER statements`
+	db.Exec(`DROP TABLE ?`, "sessions")                                    // want `Placeholder \? in DDL: DROP statements`
+	db.Exec(`CREATE TABLE t (c text DEFAULT '$1')`)
+	db.Exec(`CREATE FUNCTION f(int) RETURNS int AS $$ SELECT $1 $$ LANGUAGE SQL`)
+	// Only the DDL statements are reported.
+	db.Exec(`CREATE TABLE t (c int); INSERT INTO t (c) VALUES ($1)`, days) // want `Multiple statements with args`
+}
@@ -0,0 +1,19 @@
+package ambiguous
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+func runDB() {
+	db, _ := sql.Open("mysql", "user:password@/dbname")
+	var p1, p2 string
+
+	// Both $N and ? are counted when drivers of different dialects are used.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
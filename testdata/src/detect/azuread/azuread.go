@@ -0,0 +1,16 @@
+package azuread
+
+import (
+	"database/sql"
+
+	"github.com/microsoft/go-mssqldb/azuread"
+)
+
+func runDB() {
+	db, _ := sql.Open(azuread.DriverName, "sqlserver://localhost")
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (@p1, @p2)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2) // want `Placeholder \? is not valid for sqlserver queries`
+}
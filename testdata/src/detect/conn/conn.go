@@ -0,0 +1,12 @@
+package conn
+
+import (
+	"database/sql"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Open opens the database of the stores.
+func Open(dsn string) (*sql.DB, error) {
+	return sql.Open("pgx", dsn)
+}
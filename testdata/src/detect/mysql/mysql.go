@@ -0,0 +1,34 @@
+package mysql
+
+import (
+	"database/sql"
+)
+
+func runDB() {
+	db, _ := sql.Open("mysql", "user:password@/dbname")
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2) // want `Placeholder \$1 is not valid for mysql queries`
+}
+
+func runLiterals() {
+	db, _ := sql.Open("mysql", "user:password@/dbname")
+	var p1 string
+
+	db.Exec(`UPDATE t SET note = 'it\'s $1' WHERE c1 = ?`, p1)
+}
+
+func runReserved() {
+	db, _ := sql.Open("mysql", "user:password@/dbname")
+	var p1 string
+
+	db.Exec(`INSERT INTO t (id, key) VALUES (?, ?) ON DUPLICATE KEY UPDATE id = id`, p1, p1) // want "Reserved keyword key used as an identifier: quote it as `key`"
+}
+
+func runForeignFuncs() {
+	db, _ := sql.Open("mysql", "user:password@/dbname")
+
+	db.Query(`SELECT NVL(c1, '') FROM t`) // want `Function NVL does not exist in mysql: use IFNULL instead`
+}
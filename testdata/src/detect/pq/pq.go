@@ -0,0 +1,34 @@
+package pq
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2) // want `Placeholder \? is not valid for postgres queries`
+}
+
+func runJSONB() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE data ? 'key' AND c2 = $1`, p1)
+}
+
+func runForeignFuncs() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT coalesce(c1, ''), now() FROM t WHERE c2 = $1`, p1)
+
+	db.Query(`SELECT IFNULL(c1, '') FROM t WHERE c2 > GETDATE()`) // want `Function IFNULL does not exist in postgres: use coalesce instead` `Function GETDATE does not exist in postgres: use now instead`
+
+	db.Query(`SELECT util.len(c1), "len"(c1) FROM t`)
+}
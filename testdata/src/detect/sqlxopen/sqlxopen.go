@@ -0,0 +1,13 @@
+package sqlxopen
+
+import "github.com/jmoiron/sqlx"
+
+func runDB() {
+	db := sqlx.MustConnect("mysql", "user:password@/dbname")
+	var p1, p2 string
+	var n int
+
+	db.Get(&n, `SELECT count(*) FROM t WHERE c1 = ? AND c2 = ?`, p1, p2)
+
+	db.Get(&n, `SELECT count(*) FROM t WHERE c1 = $1 AND c2 = $2`, p1, p2) // want `Placeholder \$1 is not valid for mysql queries`
+}
@@ -0,0 +1,13 @@
+package store
+
+import "detect/conn"
+
+// The dialect is the one of the driver of conn.
+func runDB() {
+	db, _ := conn.Open("postgres://localhost/db")
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2) // want `Placeholder \? is not valid for postgres queries`
+}
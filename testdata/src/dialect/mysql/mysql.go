@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"database/sql"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// The Postgres parser is not used for MySQL queries.
+	db.Exec(`INSERT INTO t (c1 c2) VALUES (?, ?)`, p1, p2)
+
+	// $N placeholders are not counted.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2) // want `Placeholder \$1 is not valid for mysql queries`
+}
+
+func runLiterals() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET note = 'it\'s ?' WHERE c1 = ?`, p1)
+
+	db.Exec(`UPDATE t SET note = "it\"s ?" WHERE c1 = ? AND c2 = ?`, p1, p1)
+}
+
+func runComments() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = ? # WHERE c2 = ?
+	/* AND c3 = ? */`, p1)
+}
+
+func runEscaped() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = ? AND data ?? 'key'`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = ? AND data ?? 'key'`, p1, p1) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
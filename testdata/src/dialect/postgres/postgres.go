@@ -0,0 +1,15 @@
+package postgres
+
+import (
+	"database/sql"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// ? is not a placeholder in Postgres.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2) // want `Placeholder \? is not valid for postgres queries`
+}
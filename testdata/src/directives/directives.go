@@ -0,0 +1,21 @@
+package directives
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec("INSERT INTO t (c1) VALUES ($1)", p1)
+
+	db.Exec("INSERT INTO t (c1) VALUES (?)", p1) // want `Placeholder \? is not valid for postgres queries`
+
+	db.Exec("INSERT INTO t (c1) VALUES (?)", p1) //sqlargs:dialect sqlite
+
+	//sqlargs:dialect duckdb // want `Unknown dialect "duckdb" in //sqlargs:dialect: must be one of clickhouse, mysql, oracle, postgres, snowflake, sqlite, sqlserver`
+	db.Exec("INSERT INTO t (c1) VALUES ($1)", p1)
+}
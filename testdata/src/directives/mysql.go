@@ -0,0 +1,19 @@
+//sqlargs:dialect mysql
+
+package directives
+
+import (
+	"database/sql"
+)
+
+func runMySQL() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec("INSERT INTO t (c1) VALUES (?)", p1)
+
+	db.Exec("INSERT INTO t (c1) VALUES ($1)", p1) // want `Placeholder \$1 is not valid for mysql queries`
+
+	//sqlargs:dialect postgres the events are in Postgres
+	db.Exec("INSERT INTO events (c1) VALUES ($1)", p1)
+}
@@ -0,0 +1,15 @@
+package disablechecks
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+func runDB() {
+	var db *sql.DB
+
+	db.Query(`SELECT * FROM t`)
+
+	db.Exec(`INSERT INTO t VALUES (1, 2)`) // want `INSERT without a column list: list the columns so that it does not break when the table changes`
+}
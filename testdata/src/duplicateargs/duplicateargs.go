@@ -0,0 +1,28 @@
+package duplicateargs
+
+import "database/sql"
+
+type user struct {
+	ID, OrgID int64
+}
+
+func where(db *sql.DB, id, org int64, u user) {
+	db.Exec(`DELETE FROM users WHERE id = $1 AND org_id = $2`, id, id) // want `id is passed both as arg 1, for column id, and as arg 2, for column org_id: one of them is likely meant to be another value`
+	db.Exec(`DELETE FROM users WHERE id = $1 AND org_id = $2`, id, org)
+	db.Exec(`DELETE FROM users WHERE id = $1 AND $2 = users.org_id`, u.ID, u.ID) // want `u.ID is passed both as arg 1, for column id, and as arg 2, for column org_id`
+	db.Exec(`DELETE FROM users WHERE id = $1 AND org_id = $2`, u.ID, u.OrgID)
+	// The same column of different tables.
+	db.Exec(`DELETE FROM users u USING orgs o WHERE u.id = $1 AND o.id = $2`, id, id)
+	// Constants are often passed more than once.
+	db.Exec(`DELETE FROM users WHERE active = $1 AND admin = $2`, false, false)
+}
+
+func set(db *sql.DB, name string, id int64) {
+	db.Exec(`UPDATE users SET name = $1, nickname = $2 WHERE id = $3`, name, name, id) // want `name is passed both as arg 1, for column name, and as arg 2, for column nickname`
+}
+
+func insert(db *sql.DB, id int64) {
+	db.Exec(`INSERT INTO users (id, org_id) VALUES ($1, $2)`, id, id) // want `id is passed both as arg 1, for column id, and as arg 2, for column org_id`
+	// Args which are not bound to a column.
+	db.Exec(`INSERT INTO users (id, org_id) VALUES ($1, coalesce($2, 0))`, id, id)
+}
@@ -0,0 +1,13 @@
+package dep
+
+import "database/sql"
+
+const Users = `SELECT id, name FROM users WHERE org_id = $1`
+
+func Orders(db *sql.DB, user int64) (*sql.Rows, error) {
+	return db.Query(`SELECT id, total FROM orders WHERE user_id = $1`, user)
+}
+
+func users(db *sql.DB, org int64) (*sql.Rows, error) {
+	return db.Query(Users, org)
+}
@@ -0,0 +1,27 @@
+package duplicates
+
+import (
+	"database/sql"
+
+	"duplicates/dep"
+)
+
+const byID = `SELECT id, name FROM users WHERE id = $1`
+
+func get(db *sql.DB, id int64) {
+	db.QueryRow(byID, id)
+	// Uses of the same constant are fine.
+	db.QueryRow(byID, id)
+	db.QueryRow(`SELECT id,name  FROM users /* by id */ WHERE id = $1`, id) // want `Query is defined more than once: define it once, as a constant, so that the copies do not drift apart`
+}
+
+func orders(db *sql.DB, user int64) {
+	db.Query(`SELECT id, total FROM orders WHERE user_id = $1`, user) // want `Query is also defined in duplicates/dep: define it once`
+	// The constants of dependencies are theirs.
+	db.Query(dep.Users, user)
+}
+
+func short(db *sql.DB) {
+	db.QueryRow(`SELECT 1`)
+	db.QueryRow(`SELECT 1`)
+}
@@ -0,0 +1,60 @@
+package embedded
+
+import (
+	"context"
+	"database/sql"
+)
+
+type baseRepo struct {
+	*sql.DB
+}
+
+type userRepo struct {
+	baseRepo
+}
+
+type orderRepo struct {
+	*userRepo
+}
+
+type txRepo struct {
+	base struct {
+		*sql.Tx
+	}
+}
+
+func (r *userRepo) rename(id int, name string) error {
+	_, err := r.Exec(`UPDATE users SET name = $1 WHERE id = $2`, name) // want `No. of args \(1\) is less than no. of params \(2\)`
+	return err
+}
+
+func (r orderRepo) count(ctx context.Context, id int) (int, error) {
+	var n int
+	err := r.QueryRowContext(ctx, `SELECT count(*) FROM orders WHERE user_id = $1`, id, n).Scan(&n) // want `No. of args \(2\) is more than no. of params \(1\)`
+	return n, err
+}
+
+func (r *txRepo) remove(id int) error {
+	_, err := r.base.Exec(`DELETE FROM users WHERE id = $1`, id)
+	return err
+}
+
+// A method of the wrapper hides the one of sql.DB.
+type loggingRepo struct {
+	*sql.DB
+}
+
+func (r *loggingRepo) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return r.DB.Exec(query, args...)
+}
+
+func (r *loggingRepo) archive(id int) error {
+	_, err := r.Exec(`UPDATE users SET archived = true WHERE id = $1 AND org_id = $2`, id)
+	return err
+}
+
+// Method expressions take the receiver first.
+func expr(db *sql.DB, id int) error {
+	_, err := (*sql.DB).Exec(db, `DELETE FROM users WHERE id = $1`, id)
+	return err
+}
@@ -0,0 +1,49 @@
+package errnorows
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func handler(db *sql.DB, w http.ResponseWriter, id int) {
+	var name string
+	if err := db.QueryRow(`SELECT name FROM users WHERE id = $1`, id).Scan(&name); err != nil { // want `Error of QueryRow is handled as a failure: check errors.Is\(err, sql.ErrNoRows\) first`
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, name)
+}
+
+func checked(db *sql.DB, w http.ResponseWriter, id int) {
+	var name string
+	err := db.QueryRow(`SELECT name FROM users WHERE id = $1`, id).Scan(&name)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.NotFound(w, nil)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fmt.Fprint(w, name)
+}
+
+func logged(db *sql.DB, id int) string {
+	var name string
+	err := db.QueryRow(`SELECT name FROM users WHERE id = $1`, id).Scan(&name)
+	if err != nil { // want `Error of QueryRow is handled as a failure`
+		log.Fatal(err)
+	}
+	return name
+}
+
+func returned(db *sql.DB, id int) (string, error) {
+	var name string
+	if err := db.QueryRow(`SELECT name FROM users WHERE id = $1`, id).Scan(&name); err != nil {
+		return "", fmt.Errorf("user %d: %w", id, err)
+	}
+	return name, nil
+}
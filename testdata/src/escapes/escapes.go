@@ -0,0 +1,22 @@
+package escapes
+
+import "database/sql"
+
+func run(db *sql.DB, p1 string) {
+	// The escapes of interpreted strings are decoded before the placeholders
+	// are counted, and the diagnostics are placed on the placeholders in the
+	// source.
+	db.Exec("UPDATE t SET c1 = 1\n\tWHERE c2 = ?", p1)             // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET \"c1\" = 1 WHERE c2 = ?", p1)            // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET c1 = 'café' WHERE c3 = ?", p1)           // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET c1 = 'caf\U000000e9' WHERE c3 = ?", p1)  // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET c1 = '\xff\101' WHERE c3 = ?", p1)       // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET c1 = E'caf\u00e9' WHERE c3 = ?", p1)     // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET c1 = '\\' WHERE c3 = ?", p1)             // want `Placeholder \? is not valid for postgres queries`
+	db.Exec("UPDATE t SET c1 = '\a\b\f\r\v\x00' WHERE c3 = ?", p1) // want `Placeholder \? is not valid for postgres queries`
+
+	// An escaped quote is part of the query, so the $1 after it is in the
+	// string.
+	db.Exec("UPDATE t SET c1 = 'it\x27\x27s $1' WHERE c3 = $1", p1)
+	db.Exec("UPDATE t SET c1 = '$1\x27\x27 WHERE c3 = $1'", p1) // want `No. of args \(1\) is more than no. of params \(0\)`
+}
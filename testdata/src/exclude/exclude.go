@@ -0,0 +1,12 @@
+package exclude
+
+import (
+	"database/sql"
+
+	"exclude/legacy"
+)
+
+func updateUser(db *sql.DB, name string) {
+	db.Exec("UPDATE users SET name = $1 WHERE id = $2", name) // want `No. of args \(1\) is less than no. of params \(2\)`
+	legacy.InsertUser(db, name)
+}
@@ -0,0 +1,9 @@
+package legacy
+
+import (
+	"database/sql"
+)
+
+func InsertUser(db *sql.DB, name string) {
+	db.Exec("INSERT INTO users (name, email) VALUES ($1, $2)", name)
+}
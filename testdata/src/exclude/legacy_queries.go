@@ -0,0 +1,9 @@
+package exclude
+
+import (
+	"database/sql"
+)
+
+func deleteUser(db *sql.DB, name string) {
+	db.Exec("DELETE FROM users WHERE name = $1 AND id = $2", name)
+}
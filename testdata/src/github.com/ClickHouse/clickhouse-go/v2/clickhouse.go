@@ -0,0 +1,9 @@
+// Package clickhouse is a stub of the clickhouse-go ClickHouse driver.
+package clickhouse
+
+import "database/sql/driver"
+
+// Named binds value to the {name:Type} parameter of a query.
+func Named(name string, value interface{}) driver.NamedValue {
+	return driver.NamedValue{Name: name, Value: value}
+}
@@ -0,0 +1,34 @@
+// Package sqlmock is a stub of DATA-DOG/go-sqlmock.
+package sqlmock
+
+import (
+	"database/sql"
+	"database/sql/driver"
+)
+
+// Sqlmock records the expectations of a mocked database.
+type Sqlmock interface {
+	ExpectQuery(expectedSQL string) *ExpectedQuery
+	ExpectExec(expectedSQL string) *ExpectedExec
+}
+
+// New returns a mocked database.
+func New() (*sql.DB, Sqlmock, error) {
+	return nil, nil, nil
+}
+
+// ExpectedQuery is an expected call to Query.
+type ExpectedQuery struct{}
+
+// WithArgs sets the expected args of the query.
+func (e *ExpectedQuery) WithArgs(args ...driver.Value) *ExpectedQuery {
+	return e
+}
+
+// ExpectedExec is an expected call to Exec.
+type ExpectedExec struct{}
+
+// WithArgs sets the expected args of the statement.
+func (e *ExpectedExec) WithArgs(args ...driver.Value) *ExpectedExec {
+	return e
+}
@@ -0,0 +1,21 @@
+// Package godror is a stub of the godror Oracle driver.
+package godror
+
+// Number is an Oracle NUMBER.
+type Number string
+
+type stmtOptions struct {
+	fetchArraySize int
+	plSQLArrays    bool
+}
+
+// Option changes how a statement is run, when passed among its args.
+type Option func(*stmtOptions)
+
+// PlSQLArrays binds the slices to PL/SQL associative arrays.
+var PlSQLArrays Option = func(o *stmtOptions) { o.plSQLArrays = true }
+
+// FetchArraySize sets the no. of rows fetched at once.
+func FetchArraySize(n int) Option {
+	return func(o *stmtOptions) { o.fetchArraySize = n }
+}
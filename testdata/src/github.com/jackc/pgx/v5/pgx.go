@@ -0,0 +1,10 @@
+// Package pgx is a stub of the pgx driver.
+package pgx
+
+// Identifier is a possibly schema qualified identifier.
+type Identifier []string
+
+// Sanitize returns the quoted identifier.
+func (ident Identifier) Sanitize() string {
+	return ""
+}
@@ -0,0 +1,2 @@
+// Package stdlib is a stub of the database/sql driver of pgx.
+package stdlib
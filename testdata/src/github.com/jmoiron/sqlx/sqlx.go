@@ -0,0 +1,109 @@
+// Package sqlx is a stub of jmoiron/sqlx.
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DB is a sql.DB with extensions.
+type DB struct {
+	*sql.DB
+}
+
+// Tx is a sql.Tx with extensions.
+type Tx struct {
+	*sql.Tx
+}
+
+// Stmt is a sql.Stmt with extensions.
+type Stmt struct {
+	*sql.Stmt
+}
+
+// Preparex prepares query, returning a Stmt.
+func (db *DB) Preparex(query string) (*Stmt, error) {
+	return nil, nil
+}
+
+// PreparexContext prepares query with ctx, returning a Stmt.
+func (db *DB) PreparexContext(ctx context.Context, query string) (*Stmt, error) {
+	return nil, nil
+}
+
+// Preparex prepares query in the transaction, returning a Stmt.
+func (tx *Tx) Preparex(query string) (*Stmt, error) {
+	return nil, nil
+}
+
+// MustConnect opens a database with the driver named driverName, and pings
+// it. It panics on errors.
+func MustConnect(driverName, dataSourceName string) *DB {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		panic(err)
+	}
+	return &DB{DB: db}
+}
+
+// Row is a sql.Row with extensions.
+type Row struct {
+	*sql.Row
+}
+
+// StructScan scans the columns of the row into the fields of dest.
+func (r *Row) StructScan(dest interface{}) error {
+	return nil
+}
+
+// Rows is a sql.Rows with extensions.
+type Rows struct {
+	*sql.Rows
+}
+
+// StructScan scans the columns of the current row into the fields of dest.
+func (r *Rows) StructScan(dest interface{}) error {
+	return nil
+}
+
+// Get runs query with args, scanning its row into dest.
+func (db *DB) Get(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// GetContext runs query with args and ctx, scanning its row into dest.
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// QueryRowx runs query with args, returning a Row.
+func (db *DB) QueryRowx(query string, args ...interface{}) *Row {
+	return nil
+}
+
+// Queryx runs query with args, returning Rows.
+func (db *DB) Queryx(query string, args ...interface{}) (*Rows, error) {
+	return nil, nil
+}
+
+// Get runs query with args in the transaction, scanning its row into dest.
+func (tx *Tx) Get(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// Select runs query with args, scanning its rows into the slice dest.
+func (db *DB) Select(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// SelectContext runs query with args and ctx, scanning its rows into the
+// slice dest.
+func (db *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
+
+// Select runs query with args in the transaction, scanning its rows into the
+// slice dest.
+func (tx *Tx) Select(dest interface{}, query string, args ...interface{}) error {
+	return nil
+}
@@ -0,0 +1,17 @@
+// Package pq is a stub of the lib/pq driver.
+package pq
+
+// Array wraps a slice so that it can be used as a query arg.
+func Array(a interface{}) interface{} {
+	return a
+}
+
+// QuoteIdentifier quotes an identifier so that it can be part of a query.
+func QuoteIdentifier(name string) string {
+	return `"` + name + `"`
+}
+
+// QuoteLiteral quotes a literal so that it can be part of a query.
+func QuoteLiteral(literal string) string {
+	return `'` + literal + `'`
+}
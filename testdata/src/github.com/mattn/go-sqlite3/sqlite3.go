@@ -0,0 +1,2 @@
+// Package sqlite3 is a stub of the go-sqlite3 driver.
+package sqlite3
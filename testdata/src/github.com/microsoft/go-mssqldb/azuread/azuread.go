@@ -0,0 +1,5 @@
+// Package azuread is a stub of the Azure AD driver of go-mssqldb.
+package azuread
+
+// DriverName is the name the driver is registered with.
+const DriverName = "azuresql"
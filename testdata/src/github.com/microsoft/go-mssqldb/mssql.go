@@ -0,0 +1,30 @@
+// Package mssql is a stub of the go-mssqldb driver.
+package mssql
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// TVP is a table-valued parameter.
+type TVP struct {
+	TypeName string
+	Value    interface{}
+}
+
+// ReturnStatus receives the return status of a stored procedure.
+type ReturnStatus int32
+
+type VarChar string
+
+type NVarCharMax string
+
+type DateTime1 time.Time
+
+type DateTimeOffset time.Time
+
+type UniqueIdentifier [16]byte
+
+func (u UniqueIdentifier) Value() (driver.Value, error) {
+	return u[:], nil
+}
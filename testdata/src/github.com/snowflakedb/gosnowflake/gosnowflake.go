@@ -0,0 +1,7 @@
+// Package gosnowflake is a stub of the gosnowflake Snowflake driver.
+package gosnowflake
+
+// Config is the configuration of a connection.
+type Config struct {
+	Account string
+}
@@ -0,0 +1,24 @@
+package groupby
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1, count(*) FROM t WHERE c2 = $1 GROUP BY c1`, p1)
+
+	db.Query(`SELECT c1, count(*) FROM t WHERE c2 = $1`, p1) // want `Column c1 must be in GROUP BY or used in an aggregate function`
+
+	db.Query(`SELECT t.c1, t.c2 AS c, sum(c3) FROM t GROUP BY t.c1`) // want `Column t.c2 must be in GROUP BY or used in an aggregate function`
+
+	db.Query(`SELECT t.c1, c2, max(c3) FROM t GROUP BY c1, 2 ORDER BY 1`)
+
+	db.Query(`SELECT c1, count(*) OVER (PARTITION BY c2) FROM t`)
+
+	db.Query(`SELECT count(*), max(c1) FROM t`)
+
+	db.Query(`SELECT c1, c2 FROM t`)
+}
@@ -0,0 +1,18 @@
+package insertcolumns
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t VALUES ($1, $2)`, p1, p2) // want `INSERT without a column list: list the columns so that it does not break when the table changes`
+
+	db.Exec(`INSERT INTO t SELECT * FROM u`) // want `INSERT without a column list`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2)
+
+	db.Exec(`INSERT INTO t DEFAULT VALUES`)
+}
@@ -0,0 +1,18 @@
+package inventory
+
+import (
+	"database/sql"
+
+	"inventory/store"
+)
+
+func run(query string) {
+	var db *sql.DB
+	var id int
+
+	store.Insert(db, id, "name")
+	db.QueryRow(`SELECT name FROM users WHERE id = $1`, id)
+
+	// Queries which are not known statically are left out.
+	db.Exec(query, id)
+}
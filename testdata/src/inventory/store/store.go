@@ -0,0 +1,19 @@
+package store
+
+import (
+	"bytes"
+	"database/sql"
+	"text/template"
+)
+
+var deleteTmpl = template.Must(template.New("delete").Parse(`DELETE FROM {{.Table}} WHERE id = $1`))
+
+func Insert(db *sql.DB, id int, name string) {
+	db.Exec(`INSERT INTO users (id, name) VALUES ($1, $2)`, id, name)
+}
+
+func Delete(db *sql.DB, id int, data interface{}) {
+	var buf bytes.Buffer
+	deleteTmpl.Execute(&buf, data)
+	db.Exec(buf.String(), id)
+}
@@ -0,0 +1,17 @@
+package live
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = $1`, p1)
+
+	db.Query(`SELECT c1 FROM missing WHERE c2 = $1`, p1) // want `Query rejected by the database: pq: relation "missing" does not exist`
+
+	// Batches are not prepared.
+	db.Exec(`DELETE FROM missing WHERE c1 = 1; DELETE FROM t WHERE c1 = 1`)
+}
@@ -0,0 +1,57 @@
+package loopqueries
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func names(db *sql.DB, ids []int) ([]string, error) {
+	var names []string
+	for _, id := range ids {
+		var name string
+		if err := db.QueryRow(`SELECT name FROM users WHERE id = $1`, id).Scan(&name); err != nil { // want `Query is run in a loop: run it once for all the iterations, e.g. with IN or = ANY\(\$1\), or prepare it before the loop`
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func insert(db *sql.DB, names []string) error {
+	for i := 0; i < len(names); i++ {
+		if _, err := db.Exec(`INSERT INTO users (name) VALUES ($1)`, names[i]); err != nil { // want `Query is run in a loop: insert the rows of all the iterations at once with a multi-row VALUES`
+			return err
+		}
+	}
+	return nil
+}
+
+func condition(db *sql.DB) error {
+	// The query of the condition runs once per iteration, yet is not in the
+	// body of the loop.
+	for n := count(db); n > 0; n-- {
+	}
+	return nil
+}
+
+func count(db *sql.DB) int {
+	var n int
+	db.QueryRow(`SELECT count(*) FROM users`).Scan(&n)
+	return n
+}
+
+func funcLit(db *sql.DB, ids []int) {
+	for _, id := range ids {
+		go func() {
+			// The query runs in another goroutine.
+			db.Exec(`DELETE FROM users WHERE id = $1`, id)
+		}()
+	}
+}
+
+func dynamic(db *sql.DB, ids []int) {
+	for _, id := range ids {
+		// Only constant queries are known to be the same in every iteration.
+		db.Exec(fmt.Sprintf(`DELETE FROM users_%d`, id)) // want `Non-constant id is interpolated into the query`
+	}
+}
@@ -0,0 +1,12 @@
+package memo
+
+import "database/sql"
+
+const deleteQuery = `DELETE FROM t WHERE c1 = $1`
+
+func run(db *sql.DB) {
+	db.Exec(deleteQuery, 1)
+	db.Exec(deleteQuery, 2)
+	db.Exec(deleteQuery, 3)
+	db.Exec(`INSERT INTO t (c1) VALUES ($1)`, 1)
+}
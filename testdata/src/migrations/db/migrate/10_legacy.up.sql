@@ -0,0 +1,3 @@
+CREATE TABLE legacy (id int);
+DROP TABLE legacy;
+ALTER TABLE users RENAME TO accounts;
@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE TABLE orders (id bigserial PRIMARY KEY, user_id bigint NOT NULL, totl numeric);
+ALTER TABLE users ADD COLUMN email text, DROP COLUMN IF EXISTS nick;
+ALTER TABLE orders RENAME COLUMN totl TO total;
+
+-- +goose Down
+DROP TABLE orders;
@@ -0,0 +1,18 @@
+package migrations
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT id, name, email FROM accounts WHERE id = $1`, p1)
+
+	db.Query(`SELECT id FROM users WHERE id = $1`, p1) // want `Unknown table users at offset 15`
+
+	db.Query(`SELECT id FROM legacy`) // want `Unknown table legacy`
+
+	db.Query(`SELECT o.total, o.totl FROM orders o`) // want `Unknown column totl of table orders`
+}
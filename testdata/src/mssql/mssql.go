@@ -0,0 +1,61 @@
+package mssql
+
+import (
+	"database/sql"
+	"time"
+
+	mssql "github.com/microsoft/go-mssqldb"
+)
+
+type user struct {
+	Name  string
+	Email string
+}
+
+func runTypes() {
+	var db *sql.DB
+	var users []user
+	var name string
+	var at time.Time
+	var id mssql.UniqueIdentifier
+
+	db.Exec(`INSERT INTO t (c1) SELECT name FROM @users`, sql.Named("users", mssql.TVP{TypeName: "dbo.Users", Value: users}))
+	db.Exec(`EXEC dbo.ImportUsers @p1`, mssql.TVP{TypeName: "dbo.Users", Value: users})
+	db.Exec(`INSERT INTO t (c1, c2, c3, c4) VALUES (@p1, @p2, @p3, @p4)`, mssql.VarChar(name), mssql.NVarCharMax(name), mssql.DateTime1(at), mssql.DateTimeOffset(at))
+	db.Exec(`DELETE FROM t WHERE id = @p1`, id)
+}
+
+func runProcedures() {
+	var db *sql.DB
+	var id int
+	var name string
+	var rs mssql.ReturnStatus
+
+	db.Exec(`dbo.RenameUser`, sql.Named("ID", id), sql.Named("Name", name))
+	db.Exec(`[dbo].[RenameUser]`, id, name)
+	db.Exec(`dbo.RenameUser`, sql.Named("ID", id), sql.Named("Name", name), &rs)
+	db.Exec(`EXEC dbo.RenameUser @ID, @Name`, sql.Named("ID", id), sql.Named("Name", name), &rs)
+	db.Exec(`EXEC dbo.RenameUser @p1`, &rs) // want `No. of args \(0\) is less than no. of params \(1\)`
+}
+
+func runOutputs() {
+	var db *sql.DB
+	var id int
+	var name string
+
+	db.Exec(`EXEC dbo.GetName @ID, @Name OUTPUT`, sql.Named("ID", id), sql.Named("Name", sql.Out{Dest: &name}))
+
+	db.Exec(`EXEC dbo.GetName @p1, @p2 OUT`, id, sql.Out{Dest: &name})
+
+	db.Exec(`EXEC dbo.GetName @ID, @Name`, sql.Named("ID", id), sql.Named("Name", sql.Out{Dest: &name})) // want `sql.Out is bound to @Name, which is not an OUTPUT parameter: its Dest is never set, add OUTPUT after it`
+
+	db.Exec(`EXEC dbo.GetName @p1, @p2`, id, sql.Out{Dest: &name}) // want `sql.Out is bound to @p2, which is not an OUTPUT parameter`
+
+	db.Exec(`EXEC dbo.GetName @ID, @Name OUTPUT`, sql.Named("ID", id), sql.Named("Name", name)) // want `Parameter @Name is OUTPUT but its arg is not a sql.Out: its value is discarded`
+
+	db.Exec(`EXEC dbo.GetName @ID, @Name OUTPUT`, sql.Named("ID", id), sql.Named("Name", &sql.Out{Dest: &name})) // want `Arg of type \*sql.Out cannot be bound: pass the sql.Out itself`
+
+	db.Exec(`DECLARE @n NVARCHAR(50); EXEC dbo.GetName @ID, @n OUTPUT; SELECT @n`, sql.Named("ID", id))
+
+	db.Exec(`dbo.GetName`, sql.Named("ID", id), sql.Named("Name", sql.Out{Dest: &name}))
+}
@@ -0,0 +1,56 @@
+package mysql
+
+import (
+	"database/sql"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2, p3) // want `No. of args \(3\) is more than no. of params \(2\)`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = ? AND c3 = 'what?'`, p1)
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = ? AND c3 = 'it''s ?'`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+
+	db.Query("SELECT `c1?` FROM t WHERE c2 = ? AND c3 = ?", p1, p2)
+
+	args := []interface{}{p1}
+	db.Query(`SELECT c1 FROM t WHERE c2 = ? AND c3 = ?`, args...) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
+
+func runCall() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`CALL transfer(?, ?)`, p1, p2)
+
+	db.Exec(`CALL transfer(?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
+
+func runInsertArity() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT IGNORE INTO t (c1, c2, c3) VALUES (?, ?)`, p1, p2) // want `No. of columns \(3\) not equal to no. of values \(2\): too few values`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?), (?)`, p1, p2, p1) // want `No. of columns \(2\) not equal to no. of values \(1\) in row 2: too few values`
+}
+
+func runUpdateSet() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`UPDATE t SET c1 = ?, c2 = ?, c3 = ? WHERE id = ?`, p1, p2) // want `No. of args \(2\) is less than no. of params \(4\): SET c3 = \? has no arg`
+}
+
+func runUnwrapped(ids []int64) {
+	var db *sql.DB
+
+	db.Query(`SELECT c1 FROM t WHERE id IN (?)`, ids) // want `Arg of type \[\]int64 cannot be bound as a single value: expand it into one placeholder per element`
+}
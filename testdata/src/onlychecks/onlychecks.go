@@ -0,0 +1,20 @@
+package onlychecks
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Query(`SELECT c1, FROM t`)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = NULL`)
+
+	db.Query(`SELECT * FROM t`)
+}
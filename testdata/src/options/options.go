@@ -0,0 +1,34 @@
+package options
+
+import (
+	"context"
+	"database/sql"
+)
+
+type store struct {
+	db *sql.DB
+}
+
+func (s *store) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := s.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func queryRow(db *sql.DB, query string, args ...interface{}) *sql.Row {
+	return db.QueryRow(query, args...)
+}
+
+func run(ctx context.Context, s *store) {
+	var p1, p2 string
+
+	s.exec(ctx, "INSERT INTO t (c1, c2) VALUES (?, ?)", p1, p2)
+
+	s.exec(ctx, "INSERT INTO t (c1, c2) VALUES (?, ?)", p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	s.exec(ctx, "INSERT INTO t (c1, c2) VALUES ($1, $2)", p1, p2) // want `Placeholder \$1 is not valid for mysql queries`
+
+	queryRow(s.db, "SELECT * FROM t WHERE c1 = ?", p1, p2) // want `Query selects \*` `No. of args \(2\) is more than no. of params \(1\)`
+
+	// Comparisons with NULL are semantics, which are disabled.
+	s.db.Query("SELECT c1 FROM t WHERE c2 = NULL")
+}
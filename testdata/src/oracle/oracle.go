@@ -0,0 +1,72 @@
+package oracle
+
+import (
+	"database/sql"
+
+	"github.com/godror/godror"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+	var n godror.Number
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (:1, :2)`, p1, n)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (:1, :2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`UPDATE t SET c1 = :c1 WHERE c2 = :c2`, p1, p2, n) // want `No. of args \(3\) is more than no. of params \(2\)`
+
+	// Every occurrence is bound by position in SQL statements.
+	db.Query(`SELECT c1 FROM t WHERE c2 = :v OR c3 = :v`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// But not in PL/SQL blocks.
+	db.Exec(`BEGIN proc(:v, :v); x := 1; END;`, p1)
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = :c2 AND c3 = :C3`, sql.Named("c2", p1), sql.Named("c3", p2))
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = :c2 AND c3 = :c3`, sql.Named("c2", p1), sql.Named("c4", p2)) // want `No arg for bind variable :c3` `Named args not used by the query: c4`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = :c2 AND c3 = :c3`, sql.Named("c2", p1), p2) // want `Named and positional args cannot be mixed`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = ':c2' AND c3 = :c3`, p2)
+}
+
+func runCasts() {
+	var db *sql.DB
+	var p1 string
+
+	// :: is never a bind variable.
+	db.Query(`SELECT c1 FROM t WHERE c2 = :1 AND c3 = 'a'::text`, p1)
+}
+
+func runReturning() {
+	var db *sql.DB
+	var p1 string
+	var id int
+
+	db.Exec(`INSERT INTO t (c1) VALUES (:1) RETURNING id INTO :2`, p1, sql.Out{Dest: &id})
+}
+
+func runOptions() {
+	var db *sql.DB
+	var p1 string
+	var ids []int
+
+	// The options of godror are not bound.
+	db.Query(`SELECT c1 FROM t WHERE c2 = :1`, p1, godror.FetchArraySize(100))
+
+	db.Exec(`BEGIN proc(:1, :2); END;`, godror.PlSQLArrays, ids, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = :1 AND c3 = :2`, p1, godror.FetchArraySize(100)) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	// Slices are bound for array DML.
+	db.Exec(`INSERT INTO t (c1) VALUES (:1)`, ids)
+}
+
+func runUnusedNames() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = :c1`, sql.Named("c1", p1), sql.Named("c2", p1), sql.Named("C3", p1)) // want `Named args not used by the query: c2, c3`
+}
@@ -0,0 +1,17 @@
+package parser
+
+import (
+	"database/sql"
+)
+
+func runParser() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec("INSERT INTO t (c1) VALUES ($1)", p1)
+
+	db.Exec("UPSERT INTO t (c1) VALUES ($1)", p1) // want `Invalid query: UPSERT is not supported`
+
+	// The placeholder is not valid, so the query is not parsed.
+	db.Exec("UPSERT INTO t (c1) VALUES (?)", p1) // want `Placeholder \? is not valid for postgres queries`
+}
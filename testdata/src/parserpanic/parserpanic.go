@@ -0,0 +1,14 @@
+package parserpanic
+
+import "database/sql"
+
+const exotic = `SELECT * FROM events MATCH_RECOGNIZE (PARTITION BY user_id ORDER BY at PATTERN (a b+)) WHERE user_id = $1 AND kind = $2`
+
+func run(db *sql.DB) {
+	db.Query(exotic, 1, 2) // want `Query could not be fully parsed: the panicking parser panicked: unexpected token`
+
+	// The placeholders are still counted.
+	db.Query(exotic, 1) // want `Query could not be fully parsed` `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Query(`SELECT id FROM events WHERE user_id = $1`, 1)
+}
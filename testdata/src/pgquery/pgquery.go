@@ -0,0 +1,10 @@
+package pgquery
+
+import "database/sql"
+
+func run(db *sql.DB, p1, p2 string) {
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1, p2)
+
+	const q = `INSERT INTO t(c1 c2) VALUES ($1, $2)`
+	db.Exec(q, p1, p2) // want `Invalid query: syntax error at or near "c2"`
+}
@@ -0,0 +1,14 @@
+package related
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES ($1, $2, $3)`, p1) // want `No. of args \(1\) is less than no. of params \(3\)`
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1`, p1, p2, p3) // want `No. of args \(3\) is more than no. of params \(1\)`
+}
@@ -0,0 +1,14 @@
+package renumber
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c3) VALUES ($1, $3)`, p1, p3) // want `Gap in placeholder numbering: \$3 is used but not \$2` `No. of args \(2\) is less than no. of params \(3\)`
+
+	db.Exec("UPDATE t SET c1 = $2 WHERE c2 = $4 OR c3 = $4", p1, p3) // want `Gap in placeholder numbering: \$4 is used but not \$1, \$3` `No. of args \(2\) is less than no. of params \(4\)`
+}
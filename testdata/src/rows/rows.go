@@ -0,0 +1,150 @@
+package rows
+
+import (
+	"database/sql"
+	"errors"
+)
+
+func deferred(db *sql.DB) error {
+	rows, err := db.Query(`SELECT c1 FROM t`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return nil
+}
+
+func deferredFunc(db *sql.DB) {
+	rows, _ := db.Query(`SELECT c1 FROM t`)
+	defer func() {
+		rows.Close()
+	}()
+}
+
+func neverClosed(db *sql.DB) error {
+	rows, err := db.Query(`SELECT c1 FROM t`) // want `Rows are never closed: defer rows.Close\(\) after checking the error`
+	if err != nil {
+		return err
+	}
+	for rows.Next() {
+	}
+	return rows.Err()
+}
+
+func explicit(db *sql.DB, limit int) error {
+	rows, err := db.Query(`SELECT c1 FROM t`)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for rows.Next() {
+		if n++; n > limit {
+			return errors.New("too many rows") // want `Rows are not closed on this path: defer rows.Close\(\) after checking the error`
+		}
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	return rows.Close()
+}
+
+func unchecked(db *sql.DB) error {
+	rows, err := db.Query(`SELECT c1 FROM t`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var c1 string
+	for rows.Next() { // want `Iteration errors are never checked: check rows.Err\(\) after the loop`
+		if err := rows.Scan(&c1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The rows are closed by the caller.
+func returned(db *sql.DB) (*sql.Rows, error) {
+	rows, err := db.Query(`SELECT c1 FROM t`)
+	return rows, err
+}
+
+// The named results are returned by the bare return.
+func returnedNamed(db *sql.DB) (rows *sql.Rows, err error) {
+	rows, err = db.Query(`SELECT c1 FROM t`)
+	return
+}
+
+// The bare return of the literal does not return the rows.
+func returnedByLiteral(db *sql.DB) (rows *sql.Rows, err error) {
+	rows, err = db.Query(`SELECT c1 FROM t`) // want `Rows are never closed`
+	func() (err error) {
+		return
+	}()
+	return nil, err
+}
+
+// Uses which do not store the rows do not close them.
+func discarded(db *sql.DB) {
+	rows, _ := db.Query(`SELECT c1 FROM t`) // want `Rows are never closed`
+	_ = rows
+	if rows != nil {
+		return
+	}
+}
+
+func passed(db *sql.DB) {
+	rows, _ := db.Query(`SELECT c1 FROM t`)
+	consume(rows)
+}
+
+func consume(rows *sql.Rows) {
+	defer rows.Close()
+}
+
+func reassigned(db *sql.DB) {
+	rows, _ := db.Query(`SELECT c1 FROM t`) // want `Rows are never closed`
+	rows, _ = db.Query(`SELECT c2 FROM t`)
+	defer rows.Close()
+}
+
+func deferInLoop(db *sql.DB, ids []int) error {
+	for _, id := range ids {
+		rows, err := db.Query(`SELECT c1 FROM t WHERE c2 = $1`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close() // want `rows.Close\(\) is deferred in a loop: the rows of every iteration stay open until the function returns`
+		for rows.Next() {
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deferInLoopFunc(db *sql.DB, ids []int) error {
+	for _, id := range ids {
+		err := func() error {
+			rows, err := db.Query(`SELECT c1 FROM t WHERE c2 = $1`, id)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+			}
+			return rows.Err()
+		}()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func blank(db *sql.DB) error {
+	_, err := db.Query(`DELETE FROM t WHERE c1 = $1 RETURNING c2`, 1) // want `Rows are assigned to _: they are never closed, which leaks their connection`
+	return err
+}
@@ -0,0 +1,37 @@
+package sanitizers
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+)
+
+func quoteIdent(name string) string {
+	return `"` + name + `"`
+}
+
+type table string
+
+func (t *table) quoted() string {
+	return `"` + string(*t) + `"`
+}
+
+func run(name string, t *table) {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s WHERE c2 = $1`, pq.QuoteIdentifier(name)), p1)
+
+	db.Query(`SELECT c1 FROM `+pgx.Identifier{"public", name}.Sanitize()+` WHERE c2 = $1`, p1)
+
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s WHERE c2 = $1`, quoteIdent(name)), p1)
+
+	db.Query(`SELECT c1 FROM ` + t.quoted())
+
+	quoted := quoteIdent(name)
+	db.Query(`SELECT c1 FROM ` + quoted)
+
+	db.Query(`SELECT c1 FROM ` + name) // want `Non-constant name is concatenated into the query`
+}
@@ -0,0 +1,62 @@
+package schema
+
+import (
+	"database/sql"
+	"time"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Query(`SELECT id, name FROM users WHERE email = $1`, p1)
+
+	db.Query(`SELECT id, nick FROM users WHERE email = $1`, p1) // want `Unknown column nick of table users at offset 11`
+
+	db.Query(`SELECT id FROM accounts WHERE email = $1`, p1) // want `Unknown table accounts at offset 15`
+
+	db.Query(`SELECT u.name, o.totl FROM users u JOIN public.orders AS o ON o.user_id = u.id WHERE u.id = $1`, p1) // want `Unknown column totl of table orders at offset 17`
+
+	db.Exec(`INSERT INTO orders (user_id, total, "note") VALUES ($1, $2, '')`, p1, p2) // want `Unknown column "note" of table orders`
+
+	db.Exec(`UPDATE users SET name = $1, mail = $2 WHERE id = 1`, p1, p2) // want `Unknown column mail of table users`
+
+	db.Query(`WITH recent AS (SELECT id FROM orders WHERE total > $1) SELECT id, lower(name) FROM recent, users`, p1)
+
+	db.Query(`SELECT extract(year FROM created_at), count(*) FROM users GROUP BY 1`)
+
+	db.Query(`SELECT id FROM users WHERE id IN (SELECT user_id FROM orders)`)
+}
+
+func runTypes() {
+	var db *sql.DB
+	var name string
+	var id int64
+	var total float64
+	var created time.Time
+
+	db.Query(`SELECT name FROM users WHERE id = $1 AND created_at > $2`, id, created)
+
+	db.Query(`SELECT name FROM users WHERE id = $1`, total) // want `Arg of type float64 is used for column id of type bigserial`
+
+	db.Query(`SELECT u.name FROM users u JOIN orders o ON o.user_id = u.id WHERE $1 < o.total AND u.created_at = $2`, total, id) // want `Arg of type int64 is used for column created_at of type timestamptz`
+
+	db.Exec(`UPDATE users SET name = $1 WHERE email = $2`, id, name) // want `Arg of type int64 is used for column name of type text`
+
+	db.Exec(`INSERT INTO orders (user_id, total) VALUES ($1, $2), ($3, $4)`, id, total, id, created) // want `Arg of type time.Time is used for column total of type numeric\(10,2\)`
+
+	db.Query(`SELECT name FROM users WHERE length(name) = $1`, id)
+}
+
+func runNotNull() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO users (name, email) VALUES ($1, $2)`, p1, p2)
+
+	db.Exec(`INSERT INTO users (name) VALUES ($1)`, p1) // want `INSERT into users does not set the NOT NULL columns without a default: email`
+
+	db.QueryRow(`INSERT INTO orders DEFAULT VALUES RETURNING id`) // want `INSERT into orders does not set the NOT NULL columns without a default: user_id`
+
+	db.Exec(`INSERT INTO users (id) SELECT user_id FROM orders`) // want `INSERT into users does not set the NOT NULL columns without a default: email, name`
+}
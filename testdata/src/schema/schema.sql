@@ -0,0 +1,15 @@
+-- The schema of the schema test package.
+CREATE TABLE users (
+	id bigserial PRIMARY KEY,
+	name text NOT NULL,
+	email varchar(255) NOT NULL UNIQUE,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	CONSTRAINT email_lower CHECK (email = lower(email))
+);
+
+CREATE TABLE IF NOT EXISTS public.orders (
+	id bigserial PRIMARY KEY,
+	user_id bigint NOT NULL REFERENCES users (id),
+	total numeric(10, 2),
+	"Note" text
+);
@@ -0,0 +1,18 @@
+package selectstar
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT * FROM t WHERE c1 = $1`, p1) // want `Query selects \*: list the columns so that Scan does not break when the table changes`
+
+	db.Query(`SELECT t.*, u.c1 FROM t JOIN u ON u.id = t.uid`) // want `Query selects \*`
+
+	db.QueryRow(`SELECT count(*) FROM t WHERE c1 = $1`, p1)
+
+	db.Query(`SELECT c1 FROM t WHERE EXISTS (SELECT * FROM u WHERE u.id = t.id)`)
+}
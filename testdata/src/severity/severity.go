@@ -0,0 +1,22 @@
+package severity
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func run(name string) {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`INSERT INTO t (c1) VALUES ($1)`) // want `^No. of args \(0\) is less than no. of params \(1\)`
+
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s`, name)) // want `^Non-constant name is interpolated into the query`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1)`, p1) // want `^warning: No. of columns \(2\) not equal to no. of values \(1\)`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = NULL`) // want `^info: Comparison = NULL is never true`
+
+	// Syntax errors are off.
+	db.Query(`SELECT c1, FROM t WHERE c2 = $1`, p1)
+}
@@ -0,0 +1,9 @@
+package skiptests
+
+import (
+	"database/sql"
+)
+
+func updateUser(db *sql.DB, name string) {
+	db.Exec("UPDATE users SET name = $1 WHERE id = $2", name) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
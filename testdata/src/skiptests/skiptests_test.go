@@ -0,0 +1,11 @@
+package skiptests
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestInsertUser(t *testing.T) {
+	var db *sql.DB
+	db.Exec("INSERT INTO users (name, email) VALUES ($1, $2)", "fixture")
+}
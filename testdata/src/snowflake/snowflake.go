@@ -0,0 +1,39 @@
+package snowflake
+
+import (
+	"database/sql"
+
+	_ "github.com/snowflakedb/gosnowflake"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`UPDATE t SET c1 = :1 WHERE c2 = :2`, p1, p2)
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = :c2 AND c3 = :c3`, sql.Named("c2", p1), sql.Named("c4", p2)) // want `No arg for bind variable :c3` `Named args not used by the query: c4`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = ? AND c3 = :c3`, p1, p2) // want `Mixed placeholder styles: \? and :c3`
+}
+
+func runPaths() {
+	var db *sql.DB
+	var p1 string
+
+	// The paths into VARIANT columns are not bind variables.
+	db.Query(`SELECT src:customer.name, src['items'][0]:price::number FROM t WHERE c1 = ?`, p1)
+}
+
+func runKeywords() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE account SET c1 = ?`, p1) // want `Reserved keyword account used as an identifier: quote it as "account"`
+
+	db.Query(`SELECT group_concat(c1) FROM t`) // want `Function group_concat does not exist in snowflake: use LISTAGG instead`
+}
@@ -0,0 +1,17 @@
+package spreadfix
+
+import (
+	"database/sql"
+)
+
+func run(args []interface{}) {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1 AND c2 = $2`, args) // want `Slice passed without ...: it will be bound as a single arg` `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1`, args) // want `Slice passed without ...: it will be bound as a single arg`
+
+	// Slices passed along with other args cannot be spread.
+	db.Exec(`DELETE FROM t WHERE c1 = $1 AND c2 = $2`, p1, args) // want `Slice passed without ...: it will be bound as a single arg`
+}
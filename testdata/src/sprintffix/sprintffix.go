@@ -0,0 +1,18 @@
+package sprintffix
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func run(name, table string, id int) {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(fmt.Sprintf(`SELECT c1 FROM t WHERE c2 = '%s' AND c3 = %d`, name, id)) // want `Non-constant name is interpolated` `Non-constant id is interpolated`
+
+	db.Query(fmt.Sprintf("SELECT c1 FROM t WHERE c2 = $1 AND c3 = '%v'", name), p1) // want `Non-constant name is interpolated`
+
+	// Identifiers cannot be placeholders.
+	db.Query(fmt.Sprintf(`SELECT c1 FROM %s WHERE c2 = '%s'`, table, name)) // want `Non-constant table is interpolated` `Non-constant name is interpolated`
+}
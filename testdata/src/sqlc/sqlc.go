@@ -0,0 +1,19 @@
+package sqlc
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Query(`SELECT id, name, bio FROM authors WHERE name = $1`, p1)
+
+	db.Query(`SELECT id, title FROM authors WHERE name = $1`, p1) // want `Unknown column title of table authors`
+
+	db.Query(`SELECT id FROM books WHERE title = $1`, p1) // want `Unknown table books`
+
+	// The engine selects the postgres dialect.
+	db.Query(`SELECT id FROM authors WHERE name = ?`, p1) // want `Placeholder \? is not valid for postgres queries`
+}
@@ -0,0 +1,37 @@
+package sqlconstants
+
+import (
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+// The queries are looked up by name, through reflection.
+const (
+	listUsers  = `SELECT id, name FROM users WHERE org_id = $1`
+	findUser   = `SELECT id, name FROM users WHERE (id = $1`                                                                              // want `Unbalanced parentheses: 1 \( not closed`
+	insertUser = `INSERT INTO users (id, name) VALUES ($1, ?)`                                                                            // want `Placeholder \? is not valid for postgres queries`
+	renameUser = `UPDATE users SET name = $1, WHERE id = $2`                                                                              // want `Trailing comma`
+	deleteUser = `DELETE FROM users WHERE id = $1 AND name = NULL`                                                                        // want `Comparison = NULL is never true: use IS NULL`
+	recentIDs  = `WITH recent AS (SELECT id FROM users WHERE created_at > now() - interval '1 day') SELECT id FROM recent, WHERE id > $1` // want `Trailing comma`
+)
+
+// Messages and formats are not queries.
+const (
+	updateMessage = "Update available: restart the app"
+	selectPrompt  = "Select the users to invite"
+	deletePrompt  = "Delete the list? (y/n"
+	findFormat    = `SELECT id FROM %s WHERE (id = $1`
+)
+
+// Variables are only checked with //sqlargs:sql.
+var listTeams = `SELECT id FROM teams WHERE (org_id = $1`
+
+// Constants run by a call are checked there.
+const listNames = `SELECT IFNULL(name, '') FROM users`
+
+func runDB() {
+	var db *sql.DB
+
+	db.Query(listNames) // want `Function IFNULL does not exist in postgres: use coalesce instead`
+}
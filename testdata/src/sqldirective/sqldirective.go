@@ -0,0 +1,53 @@
+package sqldirective
+
+import (
+	"database/sql"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// The queries are run through a registry of queries which is not recognized.
+
+//sqlargs:sql
+const listUsers = `SELECT id, name FROM users WHERE org_id = $1`
+
+//sqlargs:sql
+const findUser = `SELECT id, name FROM users WHERE id = ?` // want `Placeholder \? is not valid for postgres queries`
+
+//sqlargs:sql
+const countUsers = `SELECT count(*) FROM users WHERE (org_id = $1` // want `Unbalanced parentheses`
+
+//sqlargs:sql dialect=mysql
+const (
+	listOrders  = `SELECT id FROM orders WHERE user_id = ?`
+	countOrders = `SELECT count(*) FROM orders WHERE user_id = $1` // want `Placeholder \$1 is not valid for mysql queries`
+)
+
+var (
+	//sqlargs:sql
+	deleteUser = `DELETE FROM users WHERE id = $1 AND name = NULL` // want `Comparison = NULL is never true: use IS NULL`
+
+	//sqlargs:sql
+	upperQuery = strings.ToUpper(listUsers) // want `upperQuery is not a constant string: //sqlargs:sql only checks constant queries`
+)
+
+// Constants run by a call are checked there.
+//
+//sqlargs:sql
+const listNames = `SELECT IFNULL(name, '') FROM users`
+
+//sqlargs:sql dialect=cockroach // want `Unknown dialect "cockroach" in //sqlargs:sql: must be one of`
+const listTeams = `SELECT id FROM teams WHERE (org_id = $1`
+
+//sqlargs:sql strict // want `Unknown option "strict" in //sqlargs:sql: expected dialect=name`
+const listRoles = `SELECT id FROM roles WHERE (org_id = $1`
+
+// Without the directive, unused constants are not checked.
+const listGroups = `SELECT id FROM groups WHERE (org_id = $1`
+
+func runDB() {
+	var db *sql.DB
+
+	db.Query(listNames) // want `Function IFNULL does not exist in postgres: use coalesce instead`
+}
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2, p3 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (?, ?)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES (?1, ?2, ?1)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES (?1, ?3, ?1)`, p1, p2) // want `No. of args \(2\) is less than no. of params \(3\)`
+
+	db.Exec(`INSERT INTO t (c1, c2, c3) VALUES (?1, ?, ?)`, p1, p2, p3) // want `Mixed \? and \?NNN placeholders`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = :c2 OR c3 = :c2 OR c4 = @c4 OR c5 = $c5`, p1, p2, p3)
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = :c2 OR c3 = :c2`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = :c2 AND c3 = $c3`, sql.Named("c2", p1), sql.Named("c3", p2))
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = :c2 AND c3 = @c3`, sql.Named("c2", p1)) // want `No arg for parameter @c3`
+}
+
+func runMixed() {
+	var db *sql.DB
+	var p1, p2 string
+
+	// SQLite allows mixing styles.
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (:c1, ?)`, p1, p2)
+}
+
+func runIndices() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = ?0, c2 = ?1`, p1) // want `Invalid placeholder \?0: indices start at 1`
+}
+
+func runUnusedNames() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = :c1 WHERE c2 = $c2`, sql.Named("c1", p1), sql.Named("c3", p1)) // want `No arg for parameter \$c2` `Named args not used by the query: c3`
+}
@@ -0,0 +1,15 @@
+package sqlmock
+
+import (
+	"database/sql"
+)
+
+func updateUser(db *sql.DB, id int, name string) {
+	db.Exec(`UPDATE users
+		SET name = $1
+		WHERE id = $2`, name, id)
+}
+
+func deleteUser(db *sql.DB, id int) {
+	db.Exec(`DELETE FROM users WHERE id = $1`, id)
+}
@@ -0,0 +1,24 @@
+package sqlmock
+
+import (
+	"regexp"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestUsers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+
+	mock.ExpectExec(`UPDATE users SET name = \$1 WHERE id = \$2`).WithArgs("name", 1)
+	updateUser(db, 1, "name")
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users WHERE id = $1`)).WithArgs(1)
+	deleteUser(db, 1)
+
+	mock.ExpectExec(`^DELETE FROM users`)
+
+	mock.ExpectExec(`UPDATE users SET email`).WithArgs("email", 1) // want `sqlmock expectation matches no query of the code`
+
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM users WHERE id = $1`)).WithArgs(1, "name") // want `sqlmock expectation has 2 args in WithArgs, but the query it matches has 1 params`
+}
@@ -0,0 +1,60 @@
+package sqlserver
+
+import (
+	"database/sql"
+
+	_ "github.com/microsoft/go-mssqldb"
+)
+
+func runDB() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (@p1, @p2)`, p1, p2)
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES (@p1, @p2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Query(`SELECT c1 FROM t WHERE c2 = @p1 OR c3 = @p1`, p1)
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = @c2 AND c3 = @p1`, sql.Named("c2", p1), p2)
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = @c2 AND c3 = @p1 AND c4 = @p2`, sql.Named("c2", p1), p2) // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = @c2 AND c3 = @C3`, sql.Named("c2", p1), sql.Named("c3", p2))
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = @c2 AND c3 = @c3`, sql.Named("c2", p1)) // want `No arg for parameter @c3`
+
+	db.Exec(`DECLARE @n INT, @m INT; SET @n = @p1; SELECT @@ROWCOUNT, @n, @m`, p1)
+
+	db.QueryRow(`SELECT c1 FROM t WHERE c2 = '@c2'`)
+}
+
+func runMerge() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN UPDATE SET c1 = @p1 WHEN NOT MATCHED THEN INSERT (id, c1) VALUES (s.id, @p2);`, p1, p2)
+
+	db.Exec(`MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN UPDATE SET c1 = @p1 WHEN NOT MATCHED THEN INSERT (id, c1) VALUES (s.id, @p2);`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
+
+func runBatch() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`UPDATE t SET c1 = @p1; DELETE FROM u WHERE c2 = @p2`, p1, p2)
+}
+
+func runInsertArity() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.QueryRow(`INSERT INTO t (c1, c2, c3) OUTPUT inserted.id VALUES (@p1, @p2)`, p1, p2) // want `No. of columns \(3\) not equal to no. of values \(2\): too few values`
+}
+
+func runUnusedNames() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = @c1`, sql.Named("c1", p1), sql.Named("c2", p1)) // want `Named args not used by the query: c2`
+}
@@ -0,0 +1,92 @@
+package sqlxdest
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Base struct {
+	ID      int64 `db:"id"`
+	Created time.Time
+}
+
+type User struct {
+	Base
+	Name     string `db:"name"`
+	Email    string `db:"email,omitempty"`
+	Password string `db:"-"`
+	internal string
+}
+
+func get(db *sqlx.DB, id int64) (User, error) {
+	var u User
+	err := db.Get(&u, `SELECT id, name, email, created FROM users WHERE id = $1`, id)
+	return u, err
+}
+
+func missing(db *sqlx.DB, id int64) (User, error) {
+	var u User
+	err := db.Get(&u, `SELECT id, name, u.password, internal AS secret FROM users u WHERE id = $1`, id) // want `Column password has no destination in User: sqlx fails with missing destination name, tag a field with .db:"password".` `Column secret has no destination in User`
+	return u, err
+}
+
+func context_(ctx context.Context, tx *sqlx.Tx, db *sqlx.DB, id int64) {
+	var u User
+	db.GetContext(ctx, &u, `SELECT id, login FROM users WHERE id = $1`, id) // want `Column login has no destination in User`
+	tx.Get(&u, `SELECT id, name FROM users WHERE id = $1`, id)
+}
+
+func args(db *sqlx.DB, id int64) {
+	var u User
+	db.Get(&u, `SELECT id, name FROM users WHERE id = $1`) // want `No. of args \(0\) is less than no. of params \(1\)`
+}
+
+func single(db *sqlx.DB) {
+	var n int
+	db.Get(&n, `SELECT count(*) FROM users`)
+	var t time.Time
+	db.Get(&t, `SELECT max(created) AS last FROM users`)
+}
+
+func unknown(db *sqlx.DB) {
+	var u User
+	// The names of the expressions without an alias, and of *, are up to
+	// the database.
+	db.Get(&u, `SELECT id, upper(name) FROM users`)
+	db.Get(&u, `SELECT * FROM users`)
+}
+
+func structScan(db *sqlx.DB) error {
+	var u User
+	if err := db.QueryRowx(`SELECT id, nickname FROM users`).StructScan(&u); err != nil { // want `Column nickname has no destination in User`
+		return err
+	}
+	rows, err := db.Queryx(`SELECT id, name, age FROM users`) // want `Column age has no destination in User`
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.StructScan(&u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func selects(ctx context.Context, db *sqlx.DB, tx *sqlx.Tx, dest interface{}) {
+	var users []User
+	db.Select(&users, `SELECT id, name FROM users`)
+	db.Select(&users, `SELECT id, name, age FROM users`) // want `Column age has no destination in User`
+	var ptrs []*User
+	db.SelectContext(ctx, &ptrs, `SELECT id, nickname FROM users`) // want `Column nickname has no destination in User`
+	var ids []int64
+	tx.Select(&ids, `SELECT id FROM users`)
+	db.Select(users, `SELECT id, name FROM users`) // want `Destination of Select is a \[\]User: sqlx needs a pointer to a slice`
+	var u User
+	db.Select(&u, `SELECT id, name FROM users`) // want `Destination of Select is a \*User: sqlx needs a pointer to a slice`
+	// The destination of a wrapper is not known.
+	db.Select(dest, `SELECT id, name FROM users`)
+}
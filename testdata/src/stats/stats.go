@@ -0,0 +1,14 @@
+package stats
+
+import (
+	"database/sql"
+)
+
+func run(query string) {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) // want `No. of args \(1\) is less than no. of params \(2\)`
+	db.Exec(`UPDATE t SET c1 = $1`, p1)
+	db.Exec(query, p1)
+}
@@ -0,0 +1,96 @@
+package stmt
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func deferred(db *sql.DB) error {
+	stmt, err := db.Prepare(`INSERT INTO t (c1) VALUES ($1)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(1)
+	return err
+}
+
+func neverClosed(db *sql.DB) error {
+	stmt, err := db.Prepare(`INSERT INTO t (c1) VALUES ($1)`) // want `Statement is never closed: defer stmt.Close\(\) after checking the error`
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(1)
+	return err
+}
+
+func inTx(ctx context.Context, tx *sql.Tx) error {
+	stmt, err := tx.PrepareContext(ctx, `DELETE FROM t WHERE c1 = $1`) // want `Statement is never closed`
+	if err != nil {
+		return err
+	}
+	for _, id := range []int{1, 2} {
+		if _, err := stmt.ExecContext(ctx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func explicit(db *sql.DB) error {
+	stmt, err := db.Prepare(`INSERT INTO t (c1) VALUES ($1)`)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(1)
+	stmt.Close()
+	return err
+}
+
+func returned(db *sql.DB) (*sql.Stmt, error) {
+	stmt, err := db.Prepare(`INSERT INTO t (c1) VALUES ($1)`)
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+type store struct {
+	insert *sql.Stmt
+	delete *sql.Stmt
+}
+
+func newStore(db *sql.DB) (*store, error) {
+	s := &store{}
+	var err error
+	if s.insert, err = db.Prepare(`INSERT INTO t (c1) VALUES ($1)`); err != nil {
+		return nil, err
+	}
+	del, err := db.Prepare(`DELETE FROM t WHERE c1 = $1`)
+	if err != nil {
+		return nil, err
+	}
+	s.delete = del
+	return s, nil
+}
+
+func preparex(db *sqlx.DB) error {
+	stmt, err := db.Preparex(`INSERT INTO t (c1) VALUES ($1)`) // want `Statement is never closed`
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(1)
+	return err
+}
+
+func preparexClosed(db *sqlx.DB) error {
+	stmt, err := db.Preparex(`INSERT INTO t (c1) VALUES ($1)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(1)
+	return err
+}
@@ -0,0 +1,77 @@
+package stmtargs
+
+import (
+	"context"
+	"database/sql"
+)
+
+type store struct {
+	insert *sql.Stmt
+	update *sql.Stmt
+}
+
+func newStore(db *sql.DB) (*store, error) {
+	s := &store{}
+	var err error
+	if s.insert, err = db.Prepare(`INSERT INTO t (c1, c2) VALUES ($1, $2)`); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *store) add(c1, c2 string) error {
+	_, err := s.insert.Exec(c1, c2)
+	return err
+}
+
+func (s *store) addOne(ctx context.Context, c1 string) error {
+	_, err := s.insert.ExecContext(ctx, c1) // want `No. of args \(1\) does not match the no. of params \(2\) of the statement`
+	return err
+}
+
+func (s *store) rename(c1, c2 string) error {
+	_, err := s.update.Exec(c1, c2) // want `Statement update is run with 2 args here, but with 1 elsewhere: at most one of them matches its params`
+	return err
+}
+
+func (s *store) touch(c1 string) error {
+	_, err := s.update.Exec(c1) // want `Statement update is run with 1 args here, but with 2 elsewhere: at most one of them matches its params`
+	return err
+}
+
+func local(db *sql.DB) error {
+	stmt, err := db.Prepare(`DELETE FROM t WHERE c1 = $1`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	if _, err := stmt.Exec(1); err != nil {
+		return err
+	}
+	_, err = stmt.Exec(1, 2) // want `No. of args \(2\) does not match the no. of params \(1\)`
+	return err
+}
+
+func spread(db *sql.DB, args []interface{}) error {
+	stmt, err := db.Prepare(`DELETE FROM t WHERE c1 = $1`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(args...)
+	return err
+}
+
+func (s *store) addInTx(ctx context.Context, tx *sql.Tx, c1, c2 string) error {
+	txInsert := tx.Stmt(s.insert)
+	if _, err := txInsert.Exec(c1, c2); err != nil {
+		return err
+	}
+	_, err := tx.StmtContext(ctx, s.insert).Exec(c1) // want `No. of args \(1\) does not match the no. of params \(2\) of the statement`
+	if err != nil {
+		return err
+	}
+	txStmt := tx.StmtContext(ctx, txInsert)
+	_, err = txStmt.ExecContext(ctx, c1) // want `No. of args \(1\) does not match the no. of params \(2\) of the statement`
+	return err
+}
@@ -0,0 +1,38 @@
+package strict
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func run(table string) {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1`, p1)
+
+	const q = `DELETE FROM t WHERE c1 = $1`
+	db.Exec(q, p1)
+
+	db.Exec(fmt.Sprintf(`DELETE FROM %s WHERE c1 = $1`, table), p1) // want `Unverifiable query: value cannot be determined statically` `Non-constant table is interpolated`
+
+	query := `DELETE FROM t WHERE c1 = $1`
+	db.QueryRow(query, p1) // want `Unverifiable query: value cannot be determined statically`
+}
+
+func runArgs(cond bool, dynamic []interface{}) {
+	var db *sql.DB
+	var p1, p2 string
+
+	args := []interface{}{p1}
+	args = append(args, p2)
+	db.Exec(`DELETE FROM t WHERE c1 = $1 AND c2 = $2`, args...)
+
+	optional := []interface{}{p1}
+	if cond {
+		optional = append(optional, p2)
+	}
+	db.Exec(`DELETE FROM t WHERE c1 = $1 AND c2 = $2`, optional...) // want `Unverifiable args: no. of args is 1 to 2`
+
+	db.Exec(`DELETE FROM t WHERE c1 = $1 AND c2 = $2`, dynamic...) // want `Unverifiable args: no. of args is unknown`
+}
@@ -0,0 +1,63 @@
+package suppress
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1, p2 string
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) //nolint:sqlargs
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) //nolint:errcheck,sqlargs // the driver fills c2
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) //nolint
+
+	//sqlargs:ignore the trigger binds $2
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1)
+
+	// Comments at the end of a call spanning several lines apply to it.
+	db.Exec(`
+		INSERT INTO t (c1, c2)
+		VALUES ($1, $2)`,
+		p1) //sqlargs:ignore
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) //nolint:errcheck // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	db.Exec(`INSERT INTO t (c1, c2) VALUES ($1, $2)`, p1) //sqlargs:ignored // want `No. of args \(1\) is less than no. of params \(2\)`
+
+	//sqlargs:ignore
+
+	db.Exec(`UPDATE t SET c1 = $1`, p1, p2) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
+
+// The findings on the variables of a call are silenced too.
+func leaks(db *sql.DB) error {
+	_, err := db.Query(`SELECT c1 FROM t`) //nolint:sqlargs
+	if err != nil {
+		return err
+	}
+
+	//sqlargs:ignore closed by the caller
+	stmt, err := db.Prepare(`INSERT INTO t (c1) VALUES ($1)`)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(1)
+
+	rows, err := db.Query(`SELECT c1 FROM t`) //sqlargs:ignore
+	if err != nil {
+		return err
+	}
+	// The findings on other statements are still reported.
+	for rows.Next() { // want `Iteration errors are never checked`
+	}
+
+	tx, err := db.Begin() //nolint:sqlargs
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM t`)
+	return err
+}
@@ -0,0 +1,26 @@
+package swappedargs
+
+import "database/sql"
+
+type user struct {
+	ID    int64
+	Email string
+}
+
+func where(db *sql.DB, id int64, email string, u user) {
+	db.Exec(`DELETE FROM users WHERE id = $1 AND email = $2`, email, id) // want `email is passed for column id, and id for column email: the args look swapped`
+	db.Exec(`DELETE FROM users WHERE id = $1 AND email = $2`, id, email)
+	db.Exec(`DELETE FROM users WHERE id = ? AND email = ?`, u.Email, u.ID) // want `u.Email is passed for column id, and u.ID for column email`
+	db.Exec(`DELETE FROM users WHERE id = ? AND email = ?`, u.ID, u.Email)
+}
+
+func set(db *sql.DB, userID, orgID int64, name string) {
+	db.Exec(`UPDATE users SET org_id = $1, name = $2 WHERE user_id = $3`, userID, name, orgID) // want `userID is passed for column org_id, and orgID for column user_id`
+	// Names which are not the ones of the columns.
+	db.Exec(`UPDATE users SET org_id = $1 WHERE user_id = $2`, name, userID)
+}
+
+func insert(db *sql.DB, id int64, email string) {
+	db.Exec(`INSERT INTO users (id, email) VALUES ($1, $2)`, email, id) // want `email is passed for column id, and id for column email`
+	db.Exec(`INSERT INTO users (id, email) VALUES ($1, $2)`, id, email)
+}
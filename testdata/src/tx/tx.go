@@ -0,0 +1,101 @@
+package tx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+func deferred(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func never(db *sql.DB) error {
+	tx, err := db.Begin() // want `Transaction is never committed or rolled back: defer tx.Rollback\(\) after checking the error`
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	return err
+}
+
+func explicit(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func missedPath(db *sql.DB, n int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if n > 10 {
+		return errors.New("too many") // want `Transaction is neither committed nor rolled back on this path`
+	}
+	if _, err := tx.Exec(`DELETE FROM t WHERE c1 = $1`, n); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func endOfFunc(db *sql.DB) {
+	tx, err := db.Begin()
+	if err != nil {
+		return
+	}
+	tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	if err := tx.Commit(); err != nil {
+		return
+	}
+}
+
+func fallsOff(db *sql.DB, ok bool) {
+	tx, err := db.Begin()
+	if err != nil {
+		return
+	}
+	tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	if ok {
+		tx.Commit()
+	}
+} // want `Transaction is neither committed nor rolled back at the end of the function`
+
+func returned(db *sql.DB) (*sql.Tx, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func deferredFunc(db *sql.DB) (err error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	_, err = tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	return err
+}
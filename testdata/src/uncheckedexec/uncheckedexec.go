@@ -0,0 +1,29 @@
+package uncheckedexec
+
+import "database/sql"
+
+func bare(db *sql.DB) {
+	db.Exec(`DELETE FROM t WHERE c1 = $1`, 1) // want `Error of Exec is discarded: a failed write goes unnoticed`
+}
+
+func blank(tx *sql.Tx) {
+	_, _ = tx.Exec(`DELETE FROM t WHERE c1 = $1`, 1) // want `Error of Exec is discarded`
+}
+
+func checked(db *sql.DB) error {
+	_, err := db.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	return err
+}
+
+func result(db *sql.DB) (int64, error) {
+	res, err := db.Exec(`DELETE FROM t WHERE c1 = $1`, 1)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func query(db *sql.DB) {
+	// Only the writes of Exec are covered.
+	db.QueryRow(`SELECT c1 FROM t`)
+}
@@ -0,0 +1,10 @@
+package main
+
+import "database/sql"
+
+const SelectUsers = `SELECT id FROM users` // want `Query constant SelectUsers is never used`
+
+func main() {
+	db, _ := sql.Open("postgres", "")
+	db.Exec(`DELETE FROM users`)
+}
@@ -0,0 +1,35 @@
+package unusedqueries
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const (
+	columns      = `id, name`
+	selectUsers  = `SELECT ` + columns + ` FROM users`
+	selectByID   = selectUsers + ` WHERE id = $1`
+	deleteUser   = `DELETE FROM users WHERE id = $1` // want `Query constant deleteUser is never used: remove it if it was left behind`
+	updateUser   = `UPDATE users SET name = $1 WHERE id = $2`
+	insertUser   = `/* the old one */ INSERT INTO users (name) VALUES ($1)` // want `Query constant insertUser is never used`
+	oldSelect    = `SELECT id FROM users`                                   // want `Query constant oldSelect is never used`
+	oldByID      = oldSelect + ` WHERE id = $1`                             // want `Query constant oldByID is never used`
+	createTable  = `CREATE TABLE users (id bigint)`
+	message      = `select failed`
+	SelectOrders = `SELECT id FROM orders`
+)
+
+func get(db *sql.DB, id int64) error {
+	const byName = `SELECT id FROM users WHERE name = $1` // want `Query constant byName is never used`
+	var name string
+	return db.QueryRow(selectByID, id).Scan(&id, &name)
+}
+
+func wrapper(run func(string, ...interface{}) error) error {
+	// The queries passed to code which is not recognized are used.
+	return run(updateUser, "name", 1)
+}
+
+func errorf() error {
+	return fmt.Errorf(message)
+}
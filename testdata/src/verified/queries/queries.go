@@ -0,0 +1,11 @@
+package queries
+
+import "database/sql"
+
+// DeleteUser deletes a user by id.
+const DeleteUser = `DELETE FROM users WHERE id = $1`
+
+func Delete(db *sql.DB, id int) error {
+	_, err := db.Exec(DeleteUser, id)
+	return err
+}
@@ -0,0 +1,12 @@
+package verified
+
+import (
+	"database/sql"
+
+	"verified/queries"
+)
+
+func run(db *sql.DB) {
+	db.Exec(queries.DeleteUser, 1)
+	db.Exec(queries.DeleteUser, 1, 2) // want `No. of args \(2\) is more than no. of params \(1\)`
+}
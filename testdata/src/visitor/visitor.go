@@ -0,0 +1,24 @@
+package visitor
+
+import (
+	"database/sql"
+)
+
+func runVisitor() {
+	var db *sql.DB
+	var tenant, id string
+
+	db.QueryRow("SELECT c1 FROM t WHERE tenant_id = $1 AND id = $2", tenant, id)
+
+	db.QueryRow("SELECT c1 FROM t WHERE id = $1", id) // want `Query has no tenant_id filter`
+
+	//sqlargs:ignore the table is shared by all the tenants
+	db.QueryRow("SELECT c1 FROM shared WHERE id = $1", id)
+
+	// The query is not known statically, so it is not visited.
+	db.QueryRow(query(), id)
+}
+
+func query() string {
+	return "SELECT c1 FROM t WHERE id = $1"
+}
@@ -0,0 +1,26 @@
+package where
+
+import (
+	"database/sql"
+)
+
+func run() {
+	var db *sql.DB
+	var p1 string
+
+	db.Exec(`UPDATE t SET c1 = $1 WHERE id = $2`, p1, p1)
+
+	db.Exec(`UPDATE t SET c1 = $1`, p1) // want `UPDATE without a WHERE clause changes every row: add a -- sqlargs:all-rows comment if it is intended`
+
+	db.Exec(`DELETE FROM t`) // want `DELETE without a WHERE clause changes every row`
+
+	db.Exec(`DELETE FROM t -- sqlargs:all-rows`)
+
+	db.Exec(`DELETE FROM t WHERE id IN (SELECT id FROM u)`)
+
+	db.Exec(`DELETE FROM t WHERE id = 1; UPDATE u SET c1 = (SELECT c1 FROM v WHERE v.id = u.id)`) // want `UPDATE without a WHERE clause`
+
+	db.Exec(`WITH old AS (SELECT id FROM t WHERE created < now()) DELETE FROM t USING old`) // want `DELETE without a WHERE clause`
+
+	db.Exec(`INSERT INTO t (id, c1) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET c1 = EXCLUDED.c1`, p1, p1)
+}
@@ -0,0 +1,9 @@
+package wrapper
+
+import (
+	"wrapper/db"
+)
+
+func updateUser(name string) {
+	db.Exec("UPDATE users SET name = $1 WHERE id = $2", name) // want `No. of args \(1\) is less than no. of params \(2\)`
+}
@@ -0,0 +1,140 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// isBeginCall reports whether call begins a transaction, with the Begin
+// methods of sql.DB and sql.Conn, or the Beginx ones of sqlx.
+func isBeginCall(call *ast.CallExpr, info *types.Info) bool {
+	method, recv, ok := dbMethod(call, info)
+	if !ok || recv != "DB" && recv != "Conn" {
+		return false
+	}
+	switch method {
+	case "Begin", "BeginTx", "Beginx", "BeginTxx", "MustBegin", "MustBeginTx":
+		return true
+	}
+	return false
+}
+
+// checkTxEnd reports the paths of the function on which the transaction begun
+// by call is neither committed nor rolled back, as it holds its connection
+// until it is. A deferred Commit or Rollback ends it on all paths. Otherwise,
+// every return, and the end of the function, must follow a Commit or Rollback
+// of its own block or of an enclosing one. Transactions which escape the
+// function are left to their owner.
+func checkTxEnd(call *ast.CallExpr, stack []ast.Node, pass *analysis.Pass) {
+	body := enclosingBody(stack)
+	obj, assign, end, ok := localVar(call, stack, body, pass.TypesInfo)
+	if !ok {
+		return
+	}
+	commits, commitsDeferred := methodCalls(obj, "Commit", body, assign.End(), end, pass.TypesInfo)
+	rollbacks, rollbacksDeferred := methodCalls(obj, "Rollback", body, assign.End(), end, pass.TypesInfo)
+	ends := append(commits, rollbacks...)
+	if len(ends) == 0 {
+		reportf(pass, catTx, assign.Lhs[0].Pos(), "Transaction is never committed or rolled back: defer %s.Rollback() after checking the error, as it holds a connection until it ends", obj.Name())
+		return
+	}
+	for _, isDeferred := range append(commitsDeferred, rollbacksDeferred...) {
+		if isDeferred {
+			return
+		}
+	}
+	blocks := endBlocks(ends, body)
+	// ended reports whether the transaction ended before the end of stmt, in
+	// a block enclosing it.
+	ended := func(stmt ast.Stmt) bool {
+		for i, c := range ends {
+			b := blocks[i]
+			if b != nil && c.Pos() < stmt.End() && b.Pos() <= stmt.Pos() && stmt.End() <= b.End() {
+				return true
+			}
+		}
+		return false
+	}
+	var errObj types.Object
+	if len(assign.Lhs) == 2 {
+		if ident, ok := assign.Lhs[1].(*ast.Ident); ok {
+			errObj = pass.TypesInfo.ObjectOf(ident)
+		}
+	}
+	first := firstUse(obj, body, assign, pass.TypesInfo)
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil || n.End() <= assign.End() || n.Pos() >= end {
+			return n == nil || n.Pos() < end
+		}
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.IfStmt:
+			// There is no transaction when Begin fails.
+			if errObj != nil && mentions(n.Cond, errObj, pass.TypesInfo) && n.Pos() < first {
+				return false
+			}
+		case *ast.ReturnStmt:
+			if !ended(n) {
+				reportf(pass, catTx, n.Pos(), "Transaction is neither committed nor rolled back on this path: defer %s.Rollback() after checking the error", obj.Name())
+			}
+		}
+		return true
+	})
+	if end != body.End() || len(body.List) == 0 {
+		return
+	}
+	if _, ok := body.List[len(body.List)-1].(*ast.ReturnStmt); !ok && !ended(body.List[len(body.List)-1]) {
+		reportf(pass, catTx, body.Rbrace, "Transaction is neither committed nor rolled back at the end of the function: defer %s.Rollback() after checking the error", obj.Name())
+	}
+}
+
+// endBlocks returns the innermost block, or case clause, of body containing
+// each of calls.
+func endBlocks(calls []*ast.CallExpr, body *ast.BlockStmt) []ast.Node {
+	blocks := make([]ast.Node, len(calls))
+	var stack []ast.Node
+	ast.Inspect(body, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		for i, c := range calls {
+			if c != call {
+				continue
+			}
+			for j := len(stack) - 1; j >= 0; j-- {
+				switch stack[j].(type) {
+				case *ast.BlockStmt, *ast.CaseClause, *ast.CommClause:
+					blocks[i] = stack[j]
+				}
+				if blocks[i] != nil {
+					break
+				}
+			}
+		}
+		return true
+	})
+	return blocks
+}
+
+// firstUse returns the position of the first use of obj in body after
+// assign, or the end of body.
+func firstUse(obj types.Object, body *ast.BlockStmt, assign *ast.AssignStmt, info *types.Info) token.Pos {
+	first := body.End()
+	ast.Inspect(body, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && ident.Pos() > assign.End() && ident.Pos() < first && info.ObjectOf(ident) == obj {
+			first = ident.Pos()
+		}
+		return true
+	})
+	return first
+}
@@ -0,0 +1,112 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkUnusedQueries reports the string constants of the package which hold a
+// SELECT, INSERT, UPDATE or DELETE statement, in dialect d, but are never
+// used, like the queries left behind by a refactoring. A constant is used if
+// it is referred to outside the declarations of constants, or by a constant
+// which is used, as its value may then be run through code which is not
+// recognized. Exported constants can be run by other packages, so they are
+// only reported in main packages. The constants of the skipped files are not
+// reported.
+func checkUnusedQueries(d *dialect, skipped map[*ast.File]bool, pass *analysis.Pass) {
+	used := make(map[*types.Const]bool)
+	// refs maps the constants to the constants their declarations refer to.
+	refs := make(map[*types.Const][]*types.Const)
+	var queries []*types.Const
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.GenDecl:
+				if n.Tok != token.CONST {
+					return true
+				}
+				for _, spec := range n.Specs {
+					spec := spec.(*ast.ValueSpec)
+					var consts []*types.Const
+					for _, name := range spec.Names {
+						c, ok := pass.TypesInfo.Defs[name].(*types.Const)
+						if !ok {
+							continue
+						}
+						consts = append(consts, c)
+						if !skipped[f] && name.Name != "_" && isQueryConst(c, d) {
+							queries = append(queries, c)
+						}
+					}
+					for _, v := range spec.Values {
+						ast.Inspect(v, func(n ast.Node) bool {
+							if ref := localConst(n, pass); ref != nil {
+								for _, c := range consts {
+									refs[c] = append(refs[c], ref)
+								}
+							}
+							return true
+						})
+					}
+				}
+				return false
+			default:
+				if c := localConst(n, pass); c != nil {
+					used[c] = true
+				}
+			}
+			return true
+		})
+	}
+	var queue []*types.Const
+	for c := range used {
+		queue = append(queue, c)
+	}
+	for len(queue) > 0 {
+		c := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		for _, ref := range refs[c] {
+			if !used[ref] {
+				used[ref] = true
+				queue = append(queue, ref)
+			}
+		}
+	}
+	for _, c := range queries {
+		if used[c] || c.Exported() && pass.Pkg.Name() != "main" {
+			continue
+		}
+		reportf(pass, catUnusedQueries, c.Pos(), "Query constant %s is never used: remove it if it was left behind", c.Name())
+	}
+}
+
+// localConst returns the constant of the package of pass which n refers to,
+// if n is an identifier.
+func localConst(n ast.Node, pass *analysis.Pass) *types.Const {
+	ident, ok := n.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	c, ok := pass.TypesInfo.Uses[ident].(*types.Const)
+	if !ok || c.Pkg() != pass.Pkg {
+		return nil
+	}
+	return c
+}
+
+// isQueryConst reports whether the value of c is a SELECT, INSERT, UPDATE or
+// DELETE statement in dialect d.
+func isQueryConst(c *types.Const, d *dialect) bool {
+	if c.Val().Kind() != constant.String {
+		return false
+	}
+	switch statementKeyword(constant.StringVal(c.Val()), d) {
+	case "SELECT", "INSERT", "UPDATE", "DELETE":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package sqlargs
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// verifiedQuery is the fact exported for the exported query constants which
+// were parsed without error, so that the packages importing them do not parse
+// them again.
+type verifiedQuery struct {
+	// Dialect and Parser are the names of the dialect and of the backend the
+	// query was parsed with.
+	Dialect string
+	Parser  string
+}
+
+func (*verifiedQuery) AFact() {}
+
+func (v *verifiedQuery) String() string {
+	return fmt.Sprintf("verified %s query", v.Dialect)
+}
+
+// queryConst returns the constant expr refers to, if it is a named constant.
+func queryConst(expr ast.Expr, info *types.Info) *types.Const {
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+	c, _ := info.Uses[ident].(*types.Const)
+	return c
+}
+
+// importVerified records query, the value of the constant c, as parsed without
+// error in parsed, if it was verified by the package declaring it, with the
+// dialect d and the parser name.
+func importVerified(c *types.Const, query string, d *dialect, parser string, parsed parseMemo, pass *analysis.Pass) {
+	if c == nil || c.Pkg() == pass.Pkg {
+		return
+	}
+	var v verifiedQuery
+	if pass.ImportObjectFact(c, &v) && v.Dialect == d.name && v.Parser == parser {
+		parsed[parsedKey{d, query}] = nil
+	}
+}
+
+// exportVerified exports the fact that query, the value of the exported
+// constant c of the package of pass, was parsed without error with the dialect
+// d and the parser name.
+func exportVerified(c *types.Const, query string, d *dialect, parser string, parsed parseMemo, pass *analysis.Pass) {
+	if c == nil || c.Pkg() != pass.Pkg || !c.Exported() || c.Parent() != c.Pkg().Scope() {
+		return
+	}
+	if err, ok := parsed[parsedKey{d, query}]; ok && err == nil {
+		pass.ExportObjectFact(c, &verifiedQuery{Dialect: d.name, Parser: parser})
+	}
+}
@@ -0,0 +1,53 @@
+package sqlargs
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// QueryVisitor is a custom check, run by an analyzer created with NewAnalyzer
+// on every query which is known statically, like one requiring a tenant_id
+// filter.
+type QueryVisitor func(q *QueryCall)
+
+// QueryCall is a query run by a call, as given to a QueryVisitor.
+type QueryCall struct {
+	// Text is the text of the query. Actions of text/template queries are
+	// replaced by identifiers.
+	Text string
+	// Constant reports whether the query is a constant, rather than built
+	// with text/template.
+	Constant bool
+	// Dialect is the dialect of the query.
+	Dialect Dialect
+	// Call is the call running the query. For the ExtraFuncs of Options, its
+	// Args are only the query followed by its args.
+	Call *ast.CallExpr
+	// Args are the args bound to the query.
+	Args []ast.Expr
+	// Method is Exec, Query or QueryRow, a method of sqlx like Get, or "" for
+	// the ExtraFuncs of Options with other names.
+	Method string
+	// Pass is the pass of the package of the call. Its diagnostics are
+	// filtered like the ones of the analyzer, by suppression comments and the
+	// baseline.
+	Pass *analysis.Pass
+}
+
+// Reportf reports a finding of the "custom" category on the call.
+func (q *QueryCall) Reportf(format string, args ...interface{}) {
+	reportf(q.Pass, catCustom, q.Call.Lparen, format, args...)
+}
+
+// visit runs the visitors of cfg on query, run by call with method in dialect
+// d.
+func (cfg *config) visit(query string, constant bool, d *dialect, call *ast.CallExpr, method string, pass *analysis.Pass) {
+	if len(cfg.visitors) == 0 {
+		return
+	}
+	q := &QueryCall{Text: query, Constant: constant, Dialect: d, Call: call, Args: call.Args[1:], Method: method, Pass: pass}
+	for _, visit := range cfg.visitors {
+		visit(q)
+	}
+}
@@ -0,0 +1,32 @@
+//go:build sqlargs_vitess
+
+package sqlargs
+
+import (
+	"sync"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+var _ = builtin("vitess", &vitess{})
+
+// vitess is the backend parsing MySQL queries with the parser of Vitess.
+type vitess struct {
+	once   sync.Once
+	parser *sqlparser.Parser
+	err    error
+}
+
+func (v *vitess) Parse(query string, d Dialect) error {
+	if d.Name() != "mysql" {
+		return nil
+	}
+	v.once.Do(func() {
+		v.parser, v.err = sqlparser.New(sqlparser.Options{})
+	})
+	if v.err != nil {
+		return v.err
+	}
+	_, err := v.parser.Parse(query)
+	return err
+}
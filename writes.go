@@ -0,0 +1,65 @@
+package sqlargs
+
+import (
+	"go/ast"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// allRowsMarker is the comment which marks a query as intentionally changing
+// every row of a table.
+const allRowsMarker = "sqlargs:all-rows"
+
+// checkFullTableWrites reports the UPDATE and DELETE statements of query which
+// have no WHERE clause, unless the query has an allRowsMarker comment.
+func checkFullTableWrites(query string, d *dialect, call *ast.CallExpr, pass *analysis.Pass) {
+	var stmt []lexeme
+	for _, l := range lex(query, d) {
+		if l.kind == lexComment && strings.Contains(l.text, allRowsMarker) {
+			return
+		}
+		if l.kind == lexComment {
+			continue
+		}
+		if l.text == ";" {
+			if verb := fullTableWrite(stmt); verb != "" {
+				reportf(pass, catRequireWhere, call.Lparen, "%s without a WHERE clause changes every row: add a -- %s comment if it is intended", verb, allRowsMarker)
+				return
+			}
+			stmt = stmt[:0]
+			continue
+		}
+		stmt = append(stmt, l)
+	}
+	if verb := fullTableWrite(stmt); verb != "" {
+		reportf(pass, catRequireWhere, call.Lparen, "%s without a WHERE clause changes every row: add a -- %s comment if it is intended", verb, allRowsMarker)
+	}
+}
+
+// fullTableWrite returns UPDATE or DELETE if the lexemes of a statement are
+// one without a WHERE clause, and "" otherwise. The statements of a WITH
+// clause are not looked at.
+func fullTableWrite(stmt []lexeme) string {
+	depth := 0
+	verb := ""
+	for _, l := range stmt {
+		switch {
+		case l.text == "(":
+			depth++
+		case l.text == ")":
+			depth--
+		case depth != 0 || l.kind != lexWord:
+		case verb == "":
+			switch keyword := strings.ToUpper(l.text); keyword {
+			case "UPDATE", "DELETE":
+				verb = keyword
+			case "INSERT", "SELECT", "MERGE", "VALUES", "CALL":
+				return ""
+			}
+		case strings.EqualFold(l.text, "WHERE"):
+			return ""
+		}
+	}
+	return verb
+}